@@ -0,0 +1,54 @@
+// momentum-gen scaffolds a new microservice from a proto service definition.
+//
+// The proto file is read as a descriptor set, so it must be compiled first:
+//
+//	protoc -I=shared --include_imports --descriptor_set_out=catalog.pb shared/protobuf/catalog.proto
+//	go run ./cmd/momentum-gen -descriptor_set catalog.pb -service CatalogService -name catalog
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	descriptorSet := flag.String("descriptor_set", "", "path to a FileDescriptorSet produced by protoc --include_imports --descriptor_set_out")
+	serviceName := flag.String("service", "", "proto service to scaffold (e.g. CatalogService or shared.CatalogService)")
+	name := flag.String("name", "", "short name of the new service, used for the directory and environment prefix (e.g. catalog)")
+	module := flag.String("module", "github.com/gabehamasaki/momentum", "Go module path of the repository")
+	out := flag.String("out", "", "output directory (defaults to services/<name>)")
+	force := flag.Bool("force", false, "overwrite existing files")
+	flag.Parse()
+
+	if *descriptorSet == "" || *serviceName == "" || *name == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *out == "" {
+		*out = filepath.Join("services", *name)
+	}
+
+	data, err := loadScaffold(*descriptorSet, *serviceName, *name, *module)
+	if err != nil {
+		fatal(err)
+	}
+
+	files, err := render(data)
+	if err != nil {
+		fatal(err)
+	}
+
+	if err := write(*out, files, *force); err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("Scaffolded %s in %s\n", data.Service, *out)
+	fmt.Println("Next steps: add your models to models/models.go and implement the server methods.")
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "momentum-gen:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// render executes every template, mapping templates/x/y.go.tmpl to x/y.go
+func render(data *scaffold) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	err := fs.WalkDir(templates, "templates", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		tmpl, err := template.ParseFS(templates, name)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", name, err)
+		}
+
+		target := strings.TrimSuffix(strings.TrimPrefix(name, "templates/"), ".tmpl")
+		content := buf.Bytes()
+		if strings.HasSuffix(target, ".go") {
+			if content, err = format.Source(content); err != nil {
+				return fmt.Errorf("generated %s is not valid Go: %w", target, err)
+			}
+		}
+
+		files[target] = content
+		return nil
+	})
+
+	return files, err
+}
+
+// write creates the files under dir, refusing to overwrite unless force is set
+func write(dir string, files map[string][]byte, force bool) error {
+	if !force {
+		for name := range files {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return fmt.Errorf("%s already exists, use -force to overwrite", filepath.Join(dir, name))
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+
+	for name, content := range files {
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", target)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// scaffold is the data passed to every template
+type scaffold struct {
+	Module       string
+	Name         string
+	ServiceName  string
+	EnvPrefix    string
+	ProtoImport  string
+	Service      string
+	UsesEmpty    bool
+	HasUnary     bool
+	HasStreaming bool
+	Methods      []method
+}
+
+// method describes a single RPC of the scaffolded service
+type method struct {
+	Name            string
+	Input           string
+	Output          string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// loadScaffold reads the descriptor set and collects the service's RPCs
+func loadScaffold(descriptorSet, serviceName, name, module string) (*scaffold, error) {
+	raw, err := os.ReadFile(descriptorSet)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode descriptor set: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set (was it built with --include_imports?): %w", err)
+	}
+
+	service, err := findService(files, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	goPackage := service.ParentFile().Options().(*descriptorpb.FileOptions).GetGoPackage()
+	if goPackage == "" {
+		return nil, fmt.Errorf("%s has no go_package option", service.ParentFile().Path())
+	}
+	// go_package is relative to shared/, as with identity.proto ("v1/proto")
+	importPath, _, _ := strings.Cut(goPackage, ";")
+	if !strings.Contains(importPath, ".") {
+		importPath = path.Join(module, "shared", importPath)
+	}
+
+	data := &scaffold{
+		Module:      module,
+		Name:        name,
+		ServiceName: name + "-service",
+		EnvPrefix:   strings.ToUpper(strings.ReplaceAll(name, "-", "_")),
+		ProtoImport: importPath,
+		Service:     string(service.Name()),
+	}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.Get(i)
+
+		input, err := goType(m.Input(), service.ParentFile().Package(), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.FullName(), err)
+		}
+		output, err := goType(m.Output(), service.ParentFile().Package(), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m.FullName(), err)
+		}
+
+		if m.IsStreamingClient() || m.IsStreamingServer() {
+			data.HasStreaming = true
+		} else {
+			data.HasUnary = true
+		}
+
+		data.Methods = append(data.Methods, method{
+			Name:            string(m.Name()),
+			Input:           input,
+			Output:          output,
+			ClientStreaming: m.IsStreamingClient(),
+			ServerStreaming: m.IsStreamingServer(),
+		})
+	}
+
+	return data, nil
+}
+
+func findService(files *protoregistry.Files, name string) (protoreflect.ServiceDescriptor, error) {
+	var found []protoreflect.ServiceDescriptor
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			sd := services.Get(i)
+			if string(sd.FullName()) == name || string(sd.Name()) == name {
+				found = append(found, sd)
+			}
+		}
+		return true
+	})
+
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("service %q not found in descriptor set", name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("service name %q is ambiguous, use the fully qualified name", name)
+	}
+}
+
+// goType returns the Go type of a request or response message as used by the generated server
+func goType(msg protoreflect.MessageDescriptor, pkg protoreflect.FullName, data *scaffold) (string, error) {
+	switch {
+	case msg.FullName() == "google.protobuf.Empty":
+		data.UsesEmpty = true
+		return "empty.Empty", nil
+	case msg.ParentFile().Package() == pkg:
+		return "proto." + goName(msg), nil
+	default:
+		return "", fmt.Errorf("message %s is not in package %s; only local messages and google.protobuf.Empty are supported", msg.FullName(), pkg)
+	}
+}
+
+// goName follows protoc-gen-go naming for nested messages (Outer_Inner)
+func goName(msg protoreflect.MessageDescriptor) string {
+	name := string(msg.Name())
+	for parent, ok := msg.Parent().(protoreflect.MessageDescriptor); ok; parent, ok = parent.Parent().(protoreflect.MessageDescriptor) {
+		name = string(parent.Name()) + "_" + name
+	}
+	return name
+}
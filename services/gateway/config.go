@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/config"
+)
+
+// serviceConfig holds the environment configuration of the gateway
+type serviceConfig struct {
+	Server config.Server
+	Logger config.Logger
+
+	ErrorReporting config.ErrorReporting
+
+	IdentityAddr   string
+	WebhooksConfig string
+
+	// IdentityServiceToken authenticates the calls webhooks make to identity
+	IdentityServiceToken shared.Secret
+}
+
+// loadConfig reads the gateway configuration, returning every invalid or missing variable at once
+func loadConfig() (*serviceConfig, error) {
+	env := shared.NewEnv()
+	config.LoadFile(env)
+
+	server := config.LoadServer(env, "GATEWAY", config.Server{HTTPPort: "8000"})
+
+	cfg := &serviceConfig{
+		Server:         server,
+		Logger:         config.LoadLogger(env, server, "/var/log/gateway.log"),
+		ErrorReporting: config.LoadErrorReporting(env),
+		IdentityAddr:   env.String("IDENTITY_GRPC_ADDR", "localhost:50051"),
+		WebhooksConfig: env.String("GATEWAY_WEBHOOKS_CONFIG", ""),
+	}
+
+	// Webhook deliveries carry no user token, so identity only accepts them with the gateway's own
+	cfg.IdentityServiceToken = env.Secret("IDENTITY_SERVICE_TOKEN", server.IsProduction() && cfg.WebhooksConfig != "")
+
+	return cfg, env.Err()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/gateway/webhooks"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/clients/identity"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	_ "github.com/joho/godotenv/autoload"
+	"go.uber.org/zap"
+)
+
+const serviceName = "gateway"
+
+func main() {
+	// 1. Load configuration and initialize logger
+	cfg, cfgErr := loadConfig()
+	errorReporter := cfg.ErrorReporting.Reporter(serviceName, cfg.Server.Environment, shared.Version)
+	loggerConfig := cfg.Logger.LoggerConfig(serviceName, cfg.Server.Environment)
+	if errorReporter != nil {
+		loggerConfig.ErrorReporter = errorReporter
+	}
+	if err := shared.InitLogger(loggerConfig); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger := shared.GetLogger()
+	if cfgErr != nil {
+		logger.Fatal("Invalid configuration", zap.Error(cfgErr))
+	}
+	shared.LogStartup(serviceName, shared.Version, cfg.Server.HTTPPort)
+
+	// 2. Setup graceful shutdown
+	ctx, cancel := setupGracefulShutdown()
+	defer cancel()
+
+	// 3. Connect to upstream services
+	identityClient, err := identity.New(cfg.IdentityAddr, &identity.Config{
+		Logger:       logger,
+		Name:         serviceName,
+		ServiceToken: cfg.IdentityServiceToken,
+	})
+	if err != nil {
+		logger.Fatal("Failed to create identity client", zap.String("address", cfg.IdentityAddr), zap.Error(err))
+	}
+	defer identityClient.Close()
+
+	// 4. Setup HTTP routes
+	mux := http.NewServeMux()
+	if err := setupWebhooks(logger, cfg, mux, identityClient); err != nil {
+		logger.Fatal("Failed to configure webhooks", zap.Error(err))
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%s", cfg.Server.HTTPPort),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		logger.Info("Starting HTTP server", zap.String("address", httpServer.Addr))
+
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Failed to serve HTTP", zap.Error(err))
+		}
+	}()
+
+	// 5. Wait for shutdown signal
+	<-ctx.Done()
+
+	// 6. Graceful shutdown
+	shared.LogShutdown(serviceName, "received shutdown signal")
+
+	shutdown := shared.NewShutdownManager(&shared.ShutdownConfig{Logger: logger, DrainTimeout: cfg.Server.ShutdownTimeout})
+	shutdown.AddHTTPServer("http", httpServer)
+	shutdown.Shutdown()
+
+	logger.Info("Server shutdown completed")
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+	errorReporter.Close(flushCtx)
+	shared.Sync() // Flush logs
+}
+
+// setupGracefulShutdown configures graceful shutdown handling
+func setupGracefulShutdown() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
+
+	go func() {
+		<-c
+		shared.GetLogger().Info("Received shutdown signal")
+		cancel()
+	}()
+
+	return ctx, cancel
+}
+
+// setupWebhooks registers the inbound webhook receiver when a configuration file is provided
+func setupWebhooks(logger *zap.Logger, cfg *serviceConfig, mux *http.ServeMux, identity proto.IdentityServiceClient) error {
+	if cfg.WebhooksConfig == "" {
+		logger.Info("GATEWAY_WEBHOOKS_CONFIG not set, inbound webhooks disabled")
+		return nil
+	}
+
+	webhooksConfig, err := webhooks.LoadConfig(cfg.WebhooksConfig)
+	if err != nil {
+		return err
+	}
+
+	receiver := webhooks.NewReceiver(
+		webhooksConfig,
+		webhooks.NewDispatcher(identity),
+		webhooks.NewMemoryIdempotencyStore(webhooks.DefaultIdempotencyTTL),
+		webhooks.NewMemoryReplayCache(),
+		logger,
+	)
+	receiver.Register(mux)
+
+	logger.Info("Inbound webhooks enabled", zap.Int("sources", len(webhooksConfig.Sources)))
+	return nil
+}
@@ -0,0 +1,87 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Action is an identity operation a webhook event can be mapped to
+type Action string
+
+const (
+	// ActionCreateUser creates a user through IdentityService.StoreUser
+	ActionCreateUser Action = "create_user"
+)
+
+// actionArguments lists the arguments each action requires
+var actionArguments = map[Action][]string{
+	ActionCreateUser: {"name", "email", "role_id"},
+}
+
+// validate checks that the action is known and every required argument is mapped
+func (a Action) validate(mapping EventMapping) error {
+	required, ok := actionArguments[a]
+	if !ok {
+		return fmt.Errorf("unsupported action %q", a)
+	}
+
+	for _, arg := range required {
+		_, inFields := mapping.Fields[arg]
+		_, inDefaults := mapping.Defaults[arg]
+		if !inFields && !inDefaults {
+			return fmt.Errorf("action %q requires argument %q", a, arg)
+		}
+	}
+
+	return nil
+}
+
+// Dispatcher executes webhook actions against the identity service
+type Dispatcher struct {
+	identity proto.IdentityServiceClient
+}
+
+// NewDispatcher creates a dispatcher backed by an identity gRPC client
+func NewDispatcher(identity proto.IdentityServiceClient) *Dispatcher {
+	return &Dispatcher{identity: identity}
+}
+
+// Dispatch runs an action with the resolved arguments and returns the affected user ID
+func (d *Dispatcher) Dispatch(ctx context.Context, action Action, args map[string]string) (string, error) {
+	switch action {
+	case ActionCreateUser:
+		// Provisioned accounts get an unusable random password until the user sets their own
+		password, err := randomPassword()
+		if err != nil {
+			return "", err
+		}
+
+		resp, err := d.identity.StoreUser(ctx, &proto.StoreUserRequest{
+			Name:     args["name"],
+			Email:    args["email"],
+			Password: password,
+			RoleId:   args["role_id"],
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.GetUser().GetId(), nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unsupported action %q", action)
+	}
+}
+
+// randomPassword generates a high-entropy password for provisioned accounts
+func randomPassword() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
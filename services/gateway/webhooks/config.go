@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared"
+)
+
+// Config describes the inbound webhook sources accepted by the gateway
+type Config struct {
+	Sources []SourceConfig `json:"sources"`
+}
+
+// SourceConfig configures a single integration posting webhooks to /webhooks/{name}
+type SourceConfig struct {
+	// Name identifies the source and is used as the URL path segment
+	Name string `json:"name"`
+
+	// SecretEnv is the environment variable holding the HMAC signing secret
+	SecretEnv string `json:"secret_env"`
+
+	// SignatureHeader carries the "sha256=<hex>" HMAC of the raw body (defaults to X-Webhook-Signature)
+	SignatureHeader string `json:"signature_header"`
+
+	// DeliveryIDHeader carries the unique delivery ID used for idempotency (defaults to X-Webhook-Id)
+	DeliveryIDHeader string `json:"delivery_id_header"`
+
+	// ReplayProtection requires a timestamp and a single-use nonce, both covered by the
+	// signature, so captured deliveries cannot be sent again
+	ReplayProtection bool `json:"replay_protection"`
+
+	// TimestampHeader carries the unix time the delivery was signed (defaults to X-Webhook-Timestamp)
+	TimestampHeader string `json:"timestamp_header"`
+
+	// NonceHeader carries a value unique to each signed request (defaults to X-Webhook-Nonce)
+	NonceHeader string `json:"nonce_header"`
+
+	// Tolerance is the accepted clock skew, e.g. "5m" (defaults to DefaultReplayTolerance)
+	Tolerance string `json:"tolerance"`
+
+	// EventField is the dot-separated path of the event type in the payload (defaults to "type")
+	EventField string `json:"event_field"`
+
+	// Events maps event types to the identity action they trigger
+	Events map[string]EventMapping `json:"events"`
+
+	secret    []byte
+	tolerance time.Duration
+}
+
+// EventMapping maps an event type to an identity action and its arguments
+type EventMapping struct {
+	// Action is the identity operation to dispatch (see Action constants)
+	Action Action `json:"action"`
+
+	// Fields maps action arguments to dot-separated paths in the payload
+	Fields map[string]string `json:"fields"`
+
+	// Defaults provides constant arguments used when a field is absent from the payload
+	Defaults map[string]string `json:"defaults"`
+}
+
+const (
+	defaultSignatureHeader  = "X-Webhook-Signature"
+	defaultDeliveryIDHeader = "X-Webhook-Id"
+	defaultEventField       = "type"
+
+	// DefaultIdempotencyTTL is how long processed delivery IDs are remembered
+	DefaultIdempotencyTTL = 24 * time.Hour
+)
+
+// LoadConfig reads the webhook configuration from a JSON file and resolves source secrets
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config: %w", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// validate applies defaults and checks that every source is usable
+func (c *Config) validate() error {
+	seen := make(map[string]bool, len(c.Sources))
+	for i := range c.Sources {
+		source := &c.Sources[i]
+		if source.Name == "" {
+			return fmt.Errorf("webhook source %d has no name", i)
+		}
+		if seen[source.Name] {
+			return fmt.Errorf("duplicate webhook source %q", source.Name)
+		}
+		seen[source.Name] = true
+
+		if source.SecretEnv == "" {
+			return fmt.Errorf("webhook source %q has no secret_env", source.Name)
+		}
+		secret, err := shared.GetEnvSecret(source.SecretEnv, true)
+		if err != nil {
+			return fmt.Errorf("webhook source %q: %w", source.Name, err)
+		}
+		source.secret = []byte(secret.Reveal())
+
+		if source.SignatureHeader == "" {
+			source.SignatureHeader = defaultSignatureHeader
+		}
+		if source.DeliveryIDHeader == "" {
+			source.DeliveryIDHeader = defaultDeliveryIDHeader
+		}
+		if source.EventField == "" {
+			source.EventField = defaultEventField
+		}
+		if source.ReplayProtection {
+			if source.TimestampHeader == "" {
+				source.TimestampHeader = defaultTimestampHeader
+			}
+			if source.NonceHeader == "" {
+				source.NonceHeader = defaultNonceHeader
+			}
+			source.tolerance = DefaultReplayTolerance
+			if source.Tolerance != "" {
+				tolerance, err := time.ParseDuration(source.Tolerance)
+				if err != nil || tolerance <= 0 {
+					return fmt.Errorf("webhook source %q has an invalid tolerance %q", source.Name, source.Tolerance)
+				}
+				source.tolerance = tolerance
+			}
+		}
+
+		for event, mapping := range source.Events {
+			if err := mapping.Action.validate(mapping); err != nil {
+				return fmt.Errorf("webhook source %q event %q: %w", source.Name, event, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package webhooks
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyStore remembers processed deliveries so provider retries are not applied twice
+type IdempotencyStore interface {
+	// Begin reserves a delivery ID. It returns the stored result and false if the
+	// delivery was already processed or is being processed.
+	Begin(key string) (*Result, bool)
+
+	// Complete stores the result of a processed delivery
+	Complete(key string, result *Result)
+
+	// Abort releases a reservation so the delivery can be retried
+	Abort(key string)
+}
+
+// Result is the outcome of a processed delivery, replayed on duplicate deliveries
+type Result struct {
+	StatusCode int    `json:"-"`
+	Action     Action `json:"action,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+type idempotencyEntry struct {
+	result    *Result
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore with TTL expiry
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]idempotencyEntry
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store that forgets deliveries after ttl
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &MemoryIdempotencyStore{
+		ttl:     ttl,
+		entries: make(map[string]idempotencyEntry),
+	}
+}
+
+// Begin implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Begin(key string) (*Result, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evictExpired(now)
+
+	if entry, ok := s.entries[key]; ok {
+		if entry.result == nil {
+			return &Result{StatusCode: 409, Message: "delivery is already being processed"}, false
+		}
+		return entry.result, false
+	}
+
+	s.entries[key] = idempotencyEntry{expiresAt: now.Add(s.ttl)}
+	return nil, true
+}
+
+// Complete implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Complete(key string, result *Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = idempotencyEntry{result: result, expiresAt: time.Now().Add(s.ttl)}
+}
+
+// Abort implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Abort(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// evictExpired drops entries past their TTL; callers must hold the lock
+func (s *MemoryIdempotencyStore) evictExpired(now time.Time) {
+	for key, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maxPayloadBytes = 1 << 20
+
+// Receiver is the HTTP handler for signed inbound webhooks
+type Receiver struct {
+	sources    map[string]*SourceConfig
+	dispatcher *Dispatcher
+	store      IdempotencyStore
+	replays    ReplayCache
+	logger     *zap.Logger
+}
+
+// NewReceiver creates a receiver for the configured sources
+func NewReceiver(config *Config, dispatcher *Dispatcher, store IdempotencyStore, replays ReplayCache, logger *zap.Logger) *Receiver {
+	sources := make(map[string]*SourceConfig, len(config.Sources))
+	for i := range config.Sources {
+		sources[config.Sources[i].Name] = &config.Sources[i]
+	}
+
+	return &Receiver{
+		sources:    sources,
+		dispatcher: dispatcher,
+		store:      store,
+		replays:    replays,
+		logger:     logger,
+	}
+}
+
+// Register mounts the receiver on POST /webhooks/{source}
+func (r *Receiver) Register(mux *http.ServeMux) {
+	mux.Handle("POST /webhooks/{source}", r)
+}
+
+// ServeHTTP verifies, deduplicates and dispatches a webhook delivery
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	source, ok := r.sources[req.PathValue("source")]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	logger := r.logger.With(zap.String("webhook.source", source.Name))
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxPayloadBytes))
+	if err != nil {
+		writeResult(w, &Result{StatusCode: http.StatusRequestEntityTooLarge, Message: "payload too large"})
+		return
+	}
+
+	if err := r.verify(req, source, payload); err != nil {
+		logger.Warn("Rejected unverified webhook", zap.Error(err))
+		writeResult(w, &Result{StatusCode: http.StatusUnauthorized, Message: err.Error()})
+		return
+	}
+
+	deliveryID := req.Header.Get(source.DeliveryIDHeader)
+	if deliveryID == "" {
+		writeResult(w, &Result{StatusCode: http.StatusBadRequest, Message: "missing " + source.DeliveryIDHeader + " header"})
+		return
+	}
+	logger = logger.With(zap.String("webhook.delivery_id", deliveryID))
+
+	key := source.Name + ":" + deliveryID
+	if previous, ok := r.store.Begin(key); !ok {
+		logger.Info("Replaying result for duplicate webhook delivery")
+		w.Header().Set("X-Webhook-Replayed", "true")
+		writeResult(w, previous)
+		return
+	}
+
+	result, retryable := r.process(req, source, payload, logger)
+	if retryable {
+		// The provider redelivers the same signed request, nonce included
+		r.store.Abort(key)
+		if source.ReplayProtection {
+			r.replayCache(source).Release(req.Header.Get(source.NonceHeader))
+		}
+	} else {
+		r.store.Complete(key, result)
+	}
+
+	writeResult(w, result)
+}
+
+// verify checks the signature and, for sources with replay protection, the timestamp and nonce
+func (r *Receiver) verify(req *http.Request, source *SourceConfig, payload []byte) error {
+	signature := req.Header.Get(source.SignatureHeader)
+	if !source.ReplayProtection {
+		return VerifySignature(source.secret, payload, signature)
+	}
+
+	// Senders sign the raw nonce; it is only scoped to the source in the replay
+	// cache, so two providers cannot collide
+	nonce := req.Header.Get(source.NonceHeader)
+	return VerifyRequest(source.secret, payload, signature, req.Header.Get(source.TimestampHeader), nonce, source.tolerance, r.replayCache(source))
+}
+
+func (r *Receiver) replayCache(source *SourceConfig) prefixedReplayCache {
+	return prefixedReplayCache{prefix: source.Name + ":", cache: r.replays}
+}
+
+// process maps the payload to an action and dispatches it. It reports whether
+// the failure is transient, in which case the delivery is not remembered.
+func (r *Receiver) process(req *http.Request, source *SourceConfig, payload []byte, logger *zap.Logger) (*Result, bool) {
+	var body map[string]any
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return &Result{StatusCode: http.StatusBadRequest, Message: "payload is not a JSON object"}, false
+	}
+
+	event, _ := lookupField(body, source.EventField)
+	mapping, ok := source.Events[event]
+	if !ok {
+		logger.Debug("Ignoring unmapped webhook event", zap.String("webhook.event", event))
+		return &Result{StatusCode: http.StatusAccepted, Message: "event ignored"}, false
+	}
+
+	args := make(map[string]string, len(mapping.Fields)+len(mapping.Defaults))
+	for arg, value := range mapping.Defaults {
+		args[arg] = value
+	}
+	for arg, path := range mapping.Fields {
+		if value, ok := lookupField(body, path); ok {
+			args[arg] = value
+		}
+	}
+	for _, arg := range actionArguments[mapping.Action] {
+		if args[arg] == "" {
+			return &Result{StatusCode: http.StatusUnprocessableEntity, Action: mapping.Action, Message: "missing argument " + arg}, false
+		}
+	}
+
+	target, err := r.dispatcher.Dispatch(req.Context(), mapping.Action, args)
+	if err != nil {
+		retryable := isRetryable(err)
+		logger.Error("Failed to dispatch webhook action",
+			zap.String("webhook.event", event),
+			zap.String("webhook.action", string(mapping.Action)),
+			zap.Bool("retryable", retryable),
+			zap.Error(err),
+		)
+
+		statusCode := http.StatusUnprocessableEntity
+		if retryable {
+			statusCode = http.StatusServiceUnavailable
+		}
+		return &Result{StatusCode: statusCode, Action: mapping.Action, Message: status.Convert(err).Message()}, retryable
+	}
+
+	logger.Info("Processed webhook",
+		zap.String("webhook.event", event),
+		zap.String("webhook.action", string(mapping.Action)),
+		zap.String("webhook.target", target),
+	)
+
+	return &Result{StatusCode: http.StatusOK, Action: mapping.Action, Target: target}, false
+}
+
+// isRetryable reports whether the provider should redeliver after this error
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// lookupField resolves a dot-separated path in a decoded JSON object
+func lookupField(body map[string]any, path string) (string, bool) {
+	var current any = body
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if current, ok = object[part]; !ok {
+			return "", false
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	default:
+		return "", false
+	}
+}
+
+// writeResult writes a delivery result as JSON
+func writeResult(w http.ResponseWriter, result *Result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(result.StatusCode)
+	_ = json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,133 @@
+package webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeIdentity answers StoreUser, failing with err when set; any other method
+// panics through the nil embedded client
+type fakeIdentity struct {
+	proto.IdentityServiceClient
+	stored []*proto.StoreUserRequest
+	err    error
+}
+
+func (f *fakeIdentity) StoreUser(ctx context.Context, req *proto.StoreUserRequest, opts ...grpc.CallOption) (*proto.StoreUserResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.stored = append(f.stored, req)
+	return &proto.StoreUserResponse{User: &proto.User{Id: "user-1"}}, nil
+}
+
+const hiredPayload = `{"event":"employee.hired","employee":{"full_name":"Ada Lovelace","work_email":"ada@example.com"}}`
+
+func newExampleReceiver(t *testing.T) (*Receiver, *fakeIdentity) {
+	t.Helper()
+	t.Setenv("WEBHOOK_HR_SECRET", "test-secret")
+
+	config, err := LoadConfig("../webhooks.example.json")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	identity := &fakeIdentity{}
+	receiver := NewReceiver(config, NewDispatcher(identity), NewMemoryIdempotencyStore(time.Hour), NewMemoryReplayCache(), zap.NewNop())
+	return receiver, identity
+}
+
+func signedRequest(secret []byte, payload, delivery, nonce string, sentAt time.Time) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/hr", strings.NewReader(payload))
+	req.SetPathValue("source", "hr")
+	req.Header.Set("X-Hr-Delivery", delivery)
+	req.Header.Set("X-Hr-Timestamp", strconv.FormatInt(sentAt.Unix(), 10))
+	req.Header.Set("X-Hr-Nonce", nonce)
+	req.Header.Set("X-Hr-Signature", SignRequest(secret, sentAt, nonce, []byte(payload)))
+	return req
+}
+
+func TestReceiverAcceptsSignedRequest(t *testing.T) {
+	receiver, identity := newExampleReceiver(t)
+
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, signedRequest([]byte("test-secret"), hiredPayload, "delivery-1", "nonce-1", time.Now()))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if len(identity.stored) != 1 || identity.stored[0].GetEmail() != "ada@example.com" {
+		t.Fatalf("StoreUser calls = %v, want one for ada@example.com", identity.stored)
+	}
+}
+
+func TestReceiverRejectsReplayedNonce(t *testing.T) {
+	receiver, _ := newExampleReceiver(t)
+	secret := []byte("test-secret")
+
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, signedRequest(secret, hiredPayload, "delivery-1", "nonce-1", time.Now()))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("first delivery status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	receiver.ServeHTTP(rec, signedRequest(secret, hiredPayload, "delivery-2", "nonce-1", time.Now()))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replayed nonce status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestReceiverAcceptsRetryAfterRetryableFailure redelivers the same signed request,
+// nonce included, after the action failed with a retryable error
+func TestReceiverAcceptsRetryAfterRetryableFailure(t *testing.T) {
+	receiver, identity := newExampleReceiver(t)
+	identity.err = status.Error(codes.Unavailable, "identity is down")
+	sentAt := time.Now()
+	deliver := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, signedRequest([]byte("test-secret"), hiredPayload, "delivery-1", "nonce-1", sentAt))
+		return rec
+	}
+
+	if rec := deliver(); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("failed delivery status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	identity.err = nil
+	if rec := deliver(); rec.Code != http.StatusOK {
+		t.Fatalf("retry status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if len(identity.stored) != 1 {
+		t.Fatalf("StoreUser calls = %d, want 1", len(identity.stored))
+	}
+
+	// Once processed, the nonce is used for good
+	if rec := deliver(); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replay after success status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestReceiverRejectsWrongSecret(t *testing.T) {
+	receiver, identity := newExampleReceiver(t)
+
+	rec := httptest.NewRecorder()
+	receiver.ServeHTTP(rec, signedRequest([]byte("other-secret"), hiredPayload, "delivery-1", "nonce-1", time.Now()))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if len(identity.stored) != 0 {
+		t.Fatalf("StoreUser called for an unverified delivery")
+	}
+}
@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimestampHeader = "X-Webhook-Timestamp"
+	defaultNonceHeader     = "X-Webhook-Nonce"
+
+	// DefaultReplayTolerance is how far a request timestamp may be from the local clock
+	DefaultReplayTolerance = 5 * time.Minute
+)
+
+var (
+	// ErrStaleRequest is returned when the timestamp is missing or outside the tolerance
+	ErrStaleRequest = errors.New("request timestamp is missing or outside the allowed clock skew")
+
+	// ErrReplayedRequest is returned when the nonce is missing or was already used
+	ErrReplayedRequest = errors.New("request nonce is missing or was already used")
+)
+
+// ReplayCache remembers the nonces of accepted requests
+type ReplayCache interface {
+	// Use records the nonce until expiresAt, returning false if it was already recorded
+	Use(nonce string, expiresAt time.Time) bool
+
+	// Release forgets a nonce so a retry of its request is accepted
+	Release(nonce string)
+}
+
+// MemoryReplayCache is an in-process ReplayCache; entries expire with the timestamp
+// tolerance, so its size is bounded by the request rate over that window
+type MemoryReplayCache struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+// NewMemoryReplayCache creates an empty in-memory replay cache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{nonces: make(map[string]time.Time)}
+}
+
+// Use implements ReplayCache
+func (c *MemoryReplayCache) Use(nonce string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, expiry := range c.nonces {
+		if now.After(expiry) {
+			delete(c.nonces, key)
+		}
+	}
+
+	if _, ok := c.nonces[nonce]; ok {
+		return false
+	}
+	c.nonces[nonce] = expiresAt
+	return true
+}
+
+// Release implements ReplayCache
+func (c *MemoryReplayCache) Release(nonce string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.nonces, nonce)
+}
+
+// prefixedReplayCache records nonces under a prefix in a shared ReplayCache
+type prefixedReplayCache struct {
+	prefix string
+	cache  ReplayCache
+}
+
+// Use implements ReplayCache
+func (c prefixedReplayCache) Use(nonce string, expiresAt time.Time) bool {
+	return c.cache.Use(c.prefix+nonce, expiresAt)
+}
+
+// Release implements ReplayCache
+func (c prefixedReplayCache) Release(nonce string) {
+	c.cache.Release(c.prefix + nonce)
+}
+
+// SignRequest returns the signature header value of a request protected against
+// replays: the HMAC covers "<timestamp>.<nonce>.<payload>"
+func SignRequest(secret []byte, timestamp time.Time, nonce string, payload []byte) string {
+	return Sign(secret, signedContent(strconv.FormatInt(timestamp.Unix(), 10), nonce, payload))
+}
+
+// VerifyRequest checks the signature of a request protected against replays, that its
+// unix timestamp is within tolerance of now and that its nonce was not used before
+func VerifyRequest(secret, payload []byte, signature, timestamp, nonce string, tolerance time.Duration, cache ReplayCache) error {
+	if err := VerifySignature(secret, signedContent(timestamp, nonce, payload), signature); err != nil {
+		return err
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrStaleRequest
+	}
+	sentAt := time.Unix(seconds, 0)
+	if skew := time.Since(sentAt); skew > tolerance || skew < -tolerance {
+		return ErrStaleRequest
+	}
+
+	// A nonce only needs to be remembered while its timestamp is still accepted
+	if nonce == "" || !cache.Use(nonce, sentAt.Add(tolerance)) {
+		return ErrReplayedRequest
+	}
+	return nil
+}
+
+func signedContent(timestamp, nonce string, payload []byte) []byte {
+	content := make([]byte, 0, len(timestamp)+len(nonce)+len(payload)+2)
+	content = append(content, timestamp...)
+	content = append(content, '.')
+	content = append(content, nonce...)
+	content = append(content, '.')
+	return append(content, payload...)
+}
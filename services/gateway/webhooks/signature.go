@@ -0,0 +1,46 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const signaturePrefix = "sha256="
+
+var (
+	// ErrMissingSignature is returned when the request carries no signature header
+	ErrMissingSignature = errors.New("missing webhook signature")
+
+	// ErrInvalidSignature is returned when the signature does not match the payload
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Sign returns the signature header value for a payload, in the "sha256=<hex>" format
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks an HMAC-SHA256 signature against the raw payload in constant time
+func VerifySignature(secret, payload []byte, signature string) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package anonymize
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultBatchSize is the number of users copied per batch
+const DefaultBatchSize = 500
+
+// Options controls how data is copied to the target database
+type Options struct {
+	// BatchSize is the number of users read and written per batch
+	BatchSize int
+	// PasswordHash replaces every user's password hash in the target
+	PasswordHash string
+}
+
+// Stats counts the rows copied per table
+type Stats struct {
+	Permissions     int
+	Roles           int
+	RolePermissions int
+	Users           int
+	UserPermissions int
+}
+
+// Dump copies the identity tables from source to target, anonymizing personal
+// data on the way. The source is read in a single read-only repeatable read
+// transaction, so the copy is a consistent snapshot even while the service is
+// serving traffic. Primary keys are preserved so references stay intact.
+func Dump(ctx context.Context, source, target *gorm.DB, faker *Faker, opts Options) (*Stats, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	if err := ensureEmpty(target.WithContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+	snapshot := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+	err := source.WithContext(ctx).Transaction(func(src *gorm.DB) error {
+		return target.WithContext(ctx).Transaction(func(dst *gorm.DB) error {
+			// IDs are preserved, so BeforeCreate hooks must not generate new ones
+			dst = dst.Session(&gorm.Session{SkipHooks: true}).Omit(clause.Associations)
+
+			var permissions []*models.Permission
+			if err := src.Unscoped().Find(&permissions).Error; err != nil {
+				return fmt.Errorf("failed to read permissions: %w", err)
+			}
+			if err := createAll(dst, permissions, opts.BatchSize); err != nil {
+				return fmt.Errorf("failed to write permissions: %w", err)
+			}
+			stats.Permissions = len(permissions)
+
+			var roles []*models.Role
+			if err := src.Unscoped().Find(&roles).Error; err != nil {
+				return fmt.Errorf("failed to read roles: %w", err)
+			}
+			if err := createAll(dst, roles, opts.BatchSize); err != nil {
+				return fmt.Errorf("failed to write roles: %w", err)
+			}
+			stats.Roles = len(roles)
+
+			count, err := copyJoinTable(src, dst, "role_permissions", opts.BatchSize)
+			if err != nil {
+				return err
+			}
+			stats.RolePermissions = count
+
+			var batch []*models.User
+			result := src.Unscoped().FindInBatches(&batch, opts.BatchSize, func(tx *gorm.DB, _ int) error {
+				for _, user := range batch {
+					user.Name = faker.Name(user.Name)
+					user.Email = faker.Email(user.Email)
+					user.Password = opts.PasswordHash
+				}
+				if err := dst.Create(&batch).Error; err != nil {
+					return fmt.Errorf("failed to write users: %w", err)
+				}
+				stats.Users += len(batch)
+				return nil
+			})
+			if result.Error != nil {
+				return fmt.Errorf("failed to copy users: %w", result.Error)
+			}
+
+			count, err = copyJoinTable(src, dst, "user_permissions", opts.BatchSize)
+			if err != nil {
+				return err
+			}
+			stats.UserPermissions = count
+
+			return nil
+		})
+	}, snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// ensureEmpty refuses to write into a database that already holds identity data
+func ensureEmpty(db *gorm.DB) error {
+	for _, model := range []any{&models.Permission{}, &models.Role{}, &models.User{}} {
+		var count int64
+		if err := db.Unscoped().Model(model).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to inspect target database: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("target database is not empty: found existing %T rows", model)
+		}
+	}
+	return nil
+}
+
+func createAll[T any](db *gorm.DB, rows []*T, batchSize int) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return db.CreateInBatches(rows, batchSize).Error
+}
+
+// copyJoinTable copies a many2many table verbatim
+func copyJoinTable(src, dst *gorm.DB, table string, batchSize int) (int, error) {
+	var rows []map[string]any
+	if err := src.Table(table).Find(&rows).Error; err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if err := dst.Table(table).CreateInBatches(rows, batchSize).Error; err != nil {
+		return 0, fmt.Errorf("failed to write %s: %w", table, err)
+	}
+	return len(rows), nil
+}
@@ -0,0 +1,54 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+)
+
+var firstNames = []string{
+	"Alice", "Bruno", "Carla", "Diego", "Elisa", "Fabio", "Gabriela", "Heitor",
+	"Isabela", "João", "Larissa", "Marcos", "Natália", "Otávio", "Paula", "Rafael",
+	"Sofia", "Tiago", "Vanessa", "Wagner",
+}
+
+var lastNames = []string{
+	"Almeida", "Barbosa", "Cardoso", "Dias", "Esteves", "Ferreira", "Gomes", "Lima",
+	"Martins", "Nunes", "Oliveira", "Pereira", "Ribeiro", "Santos", "Teixeira", "Vieira",
+}
+
+// Faker replaces personal data with fake values derived from an HMAC of the
+// original, so the same input always maps to the same output for a given key
+type Faker struct {
+	key    []byte
+	domain string
+}
+
+// NewFaker creates a Faker; emails are generated under the given domain
+func NewFaker(key string, domain string) *Faker {
+	return &Faker{key: []byte(key), domain: domain}
+}
+
+// Name returns a fake full name for the original name
+func (f *Faker) Name(original string) string {
+	sum := f.sum("name", original)
+	first := firstNames[binary.BigEndian.Uint32(sum[0:4])%uint32(len(firstNames))]
+	last := lastNames[binary.BigEndian.Uint32(sum[4:8])%uint32(len(lastNames))]
+	return first + " " + last
+}
+
+// Email returns a fake email that is unique as long as the originals are
+func (f *Faker) Email(original string) string {
+	sum := f.sum("email", strings.ToLower(strings.TrimSpace(original)))
+	return "user-" + hex.EncodeToString(sum[:8]) + "@" + f.domain
+}
+
+func (f *Faker) sum(field string, value string) []byte {
+	mac := hmac.New(sha256.New, f.key)
+	mac.Write([]byte(field))
+	mac.Write([]byte{0})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
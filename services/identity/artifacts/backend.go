@@ -0,0 +1,78 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Backend stores artifact contents addressed by their SHA-256 hex digest
+type Backend interface {
+	// Write stores the content under digest; writing an existing digest is a no-op
+	Write(ctx context.Context, digest string, content io.Reader) error
+
+	// Open returns the content stored under digest, or fs.ErrNotExist
+	Open(ctx context.Context, digest string) (io.ReadCloser, error)
+}
+
+// FileBackend stores contents in a directory, sharded by the first digest bytes
+// (ab/cd/abcd...) so no directory grows too large
+type FileBackend struct {
+	root string
+}
+
+// NewFileBackend creates the root directory if needed
+func NewFileBackend(root string) (*FileBackend, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return &FileBackend{root: root}, nil
+}
+
+func (b *FileBackend) path(digest string) string {
+	return filepath.Join(b.root, digest[:2], digest[2:4], digest)
+}
+
+// Write implements Backend. The content is written to a temporary file and renamed,
+// so a crash never leaves a partial file under a valid digest.
+func (b *FileBackend) Write(ctx context.Context, digest string, content io.Reader) error {
+	path := b.path(digest)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), digest+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Open implements Backend
+func (b *FileBackend) Open(ctx context.Context, digest string) (io.ReadCloser, error) {
+	file, err := os.Open(b.path(digest))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fs.ErrNotExist
+	}
+	return file, err
+}
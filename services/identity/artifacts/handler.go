@@ -0,0 +1,63 @@
+package artifacts
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// DownloadPath is the URL prefix of artifact downloads, followed by the artifact ID
+const DownloadPath = "/artifacts/"
+
+// Handler serves artifacts for URLs signed by URL; the signature is the only
+// credential, so links can be handed to browsers and other tools
+func (s *Store) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		query := r.URL.Query()
+		if err := s.verifyURL(id, query.Get("expires"), query.Get("signature")); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		artifact, err := s.Get(r.Context(), id)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			s.logger.Error("Failed to load artifact", zap.String("artifact_id", id), zap.Error(err))
+			http.Error(w, "failed to load artifact", http.StatusInternalServerError)
+			return
+		}
+
+		content, err := s.Open(r.Context(), artifact)
+		if err != nil {
+			s.logger.Error("Failed to open artifact", zap.String("artifact_id", id), zap.Error(err))
+			http.Error(w, "failed to open artifact", http.StatusInternalServerError)
+			return
+		}
+		defer content.Close()
+
+		// Repr-Digest (RFC 9530) lets clients verify the download themselves
+		digest, _ := hex.DecodeString(artifact.Digest)
+		w.Header().Set("Content-Type", artifact.ContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(artifact.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.Name}))
+		w.Header().Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(digest)+":")
+		w.Header().Set("ETag", strconv.Quote(artifact.Digest))
+		w.Header().Set("Cache-Control", "private, no-store")
+
+		if _, err := io.Copy(w, content); err != nil {
+			s.logger.Error("Failed to send artifact", zap.String("artifact_id", id), zap.Error(err))
+			// Headers are already sent; abort so the client does not keep a corrupted file
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
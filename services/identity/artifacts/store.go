@@ -0,0 +1,181 @@
+// Package artifacts stores exported files by their SHA-256 digest, records their
+// metadata in the artifacts table and serves them through signed, expiring URLs.
+package artifacts
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DefaultURLTTL is how long download URLs stay valid
+const DefaultURLTTL = 15 * time.Minute
+
+var (
+	ErrNotFound = errors.New("artifact not found")
+
+	// ErrCorrupted is returned when stored content no longer matches its digest
+	ErrCorrupted = errors.New("artifact content does not match its digest")
+
+	// ErrInvalidURL is returned for download URLs with a bad signature or past their expiry
+	ErrInvalidURL = errors.New("download URL is invalid or expired")
+)
+
+// Store writes artifacts to a Backend and their metadata to the database
+type Store struct {
+	db         *database.Database
+	backend    Backend
+	logger     *zap.Logger
+	signingKey []byte
+	baseURL    string
+	urlTTL     time.Duration
+}
+
+// NewStore creates a store; download URLs are signed with signingKey, start with
+// baseURL, e.g. https://identity.example.com, and stay valid for urlTTL
+func NewStore(db *database.Database, backend Backend, logger *zap.Logger, signingKey []byte, baseURL string, urlTTL time.Duration) *Store {
+	if urlTTL <= 0 {
+		urlTTL = DefaultURLTTL
+	}
+	return &Store{
+		db:         db,
+		backend:    backend,
+		logger:     logger,
+		signingKey: signingKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		urlTTL:     urlTTL,
+	}
+}
+
+// Put stores the content and records an artifact with the given kind, name, content
+// type and creator. The content is spooled to a temporary file to compute its digest.
+func (s *Store) Put(ctx context.Context, artifact models.Artifact, content io.Reader) (*models.Artifact, error) {
+	spool, err := os.CreateTemp("", "artifact-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool artifact: %w", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, digest), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool artifact: %w", err)
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	artifact.Digest = hex.EncodeToString(digest.Sum(nil))
+	artifact.Size = size
+	if err := s.backend.Write(ctx, artifact.Digest, spool); err != nil {
+		return nil, fmt.Errorf("failed to store artifact: %w", err)
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Create(&artifact).Error; err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Stored artifact",
+		zap.String("artifact_id", artifact.ID),
+		zap.String("kind", artifact.Kind),
+		zap.String("digest", artifact.Digest),
+		zap.Int64("size", artifact.Size),
+	)
+	return &artifact, nil
+}
+
+// Get returns the metadata of an artifact
+func (s *Store) Get(ctx context.Context, id string) (*models.Artifact, error) {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var artifact models.Artifact
+	if err := conn.First(&artifact, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// Open returns the content of an artifact. The reader verifies the digest as it is
+// read and returns ErrCorrupted instead of io.EOF when the content was altered.
+func (s *Store) Open(ctx context.Context, artifact *models.Artifact) (io.ReadCloser, error) {
+	content, err := s.backend.Open(ctx, artifact.Digest)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &verifyingReader{ReadCloser: content, hash: sha256.New(), digest: artifact.Digest}, nil
+}
+
+// URL returns a signed download URL for the artifact and its expiry
+func (s *Store) URL(artifact *models.Artifact) (string, time.Time) {
+	expiresAt := time.Now().Add(s.urlTTL).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(artifact.ID, expires))
+	return s.baseURL + DownloadPath + artifact.ID + "?" + query.Encode(), expiresAt
+}
+
+// verifyURL checks the signature and expiry of a download URL
+func (s *Store) verifyURL(id, expires, signature string) error {
+	seconds, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().After(time.Unix(seconds, 0)) {
+		return ErrInvalidURL
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, expires))) {
+		return ErrInvalidURL
+	}
+	return nil
+}
+
+func (s *Store) sign(id, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(id + "." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyingReader hashes the content as it is read and checks it against the digest at EOF
+type verifyingReader struct {
+	io.ReadCloser
+	hash   hash.Hash
+	digest string
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF && hex.EncodeToString(r.hash.Sum(nil)) != r.digest {
+		return n, ErrCorrupted
+	}
+	return n, err
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/anonymize"
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/rbac"
+	"github.com/gabehamasaki/momentum/services/identity/utils"
+	"gorm.io/gorm"
+)
+
+// runCommand executes a CLI subcommand and returns the process exit code
+func runCommand(name string, args []string) int {
+	switch name {
+	case "apply":
+		return runApply(args)
+	case "dump":
+		return runDump(args)
+	case "migrate":
+		return runMigrate(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+		fmt.Fprintln(os.Stderr, "usage: identity [apply|dump|migrate]")
+		return 2
+	}
+}
+
+// runApply reconciles roles, permissions and assignments with a declarative definition file
+func runApply(args []string) int {
+	flags := flag.NewFlagSet("apply", flag.ContinueOnError)
+	file := flags.String("f", "", "path to the RBAC definition file (JSON, or YAML for .yaml/.yml)")
+	dryRun := flags.Bool("dry-run", false, "only print the plan")
+	prune := flags.Bool("prune", false, "delete roles and permissions absent from the definition")
+	autoApprove := flags.Bool("auto-approve", false, "apply without interactive approval")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "apply: -f is required")
+		return 2
+	}
+
+	def, err := rbac.LoadDefinition(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: %v\n", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	db, conn, err := openCommandDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	opts := rbac.Options{Prune: *prune}
+	plan, err := rbac.ComputePlan(ctx, conn, def, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: failed to compute plan: %v\n", err)
+		return 1
+	}
+
+	plan.Print(os.Stdout)
+	if plan.Empty() || *dryRun {
+		return 0
+	}
+
+	if !*autoApprove && !confirm("\nApply these changes? Only 'yes' will be accepted: ") {
+		fmt.Println("Apply cancelled.")
+		return 1
+	}
+
+	// Cached users are not invalidated from here, they expire after USER_CACHE_TTL
+	if _, err := rbac.Apply(ctx, conn, def, opts, plan); err != nil {
+		fmt.Fprintf(os.Stderr, "apply: %v\n", err)
+		return 1
+	}
+
+	fmt.Println("Apply complete.")
+	return 0
+}
+
+// runDump copies identity data into a target database with personal data anonymized
+func runDump(args []string) int {
+	flags := flag.NewFlagSet("dump", flag.ContinueOnError)
+	target := flags.String("target", os.Getenv("IDENTITY_DUMP_TARGET_DSN"), "DSN of the database to copy into")
+	key := flags.String("key", os.Getenv("IDENTITY_ANONYMIZE_KEY"), "secret used to derive fake values; reuse it for stable output")
+	domain := flags.String("domain", "example.com", "domain for generated emails")
+	password := flags.String("password", "momentum", "password set for every copied user")
+	batchSize := flags.Int("batch-size", anonymize.DefaultBatchSize, "users copied per batch")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *target == "" {
+		fmt.Fprintln(os.Stderr, "dump: -target or IDENTITY_DUMP_TARGET_DSN is required")
+		return 2
+	}
+	if *key == "" {
+		fmt.Fprintln(os.Stderr, "dump: -key or IDENTITY_ANONYMIZE_KEY is required")
+		return 2
+	}
+	if *target == os.Getenv("IDENTITY_DSN") {
+		fmt.Fprintln(os.Stderr, "dump: target must differ from IDENTITY_DSN")
+		return 2
+	}
+
+	ctx := context.Background()
+	db, source, err := openCommandDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dump: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	targetDB := database.NewDB(*target)
+	defer targetDB.Close()
+
+	if err := targetDB.MigrateWithContext(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "dump: %v\n", err)
+		return 1
+	}
+	dest, err := targetDB.ConnWithContext(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dump: %v\n", err)
+		return 1
+	}
+
+	passwordHash, err := utils.Bcrypt(*password)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dump: failed to hash password: %v\n", err)
+		return 1
+	}
+
+	stats, err := anonymize.Dump(ctx, source, dest, anonymize.NewFaker(*key, *domain), anonymize.Options{
+		BatchSize:    *batchSize,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dump: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Dump complete: %d permissions, %d roles, %d role permissions, %d users, %d user permissions.\n",
+		stats.Permissions, stats.Roles, stats.RolePermissions, stats.Users, stats.UserPermissions)
+	return 0
+}
+
+// runMigrate applies, rolls back or lists the versioned SQL migrations
+func runMigrate(args []string) int {
+	direction := "up"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		direction, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := flags.Int64("to", 0, "up: stop after this version (0 applies every pending migration)")
+	steps := flags.Int("steps", 1, "down: number of migrations to roll back")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	ctx := context.Background()
+	db, _, err := openCommandDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	var done []database.Migration
+	switch direction {
+	case "up":
+		done, err = db.MigrateUp(ctx, *to)
+	case "down":
+		if *steps < 1 {
+			fmt.Fprintln(os.Stderr, "migrate: -steps must be at least 1")
+			return 2
+		}
+		done, err = db.MigrateDown(ctx, *steps)
+	case "status":
+		return printMigrationStatus(ctx, db)
+	default:
+		fmt.Fprintf(os.Stderr, "migrate: unknown direction %q\n", direction)
+		fmt.Fprintln(os.Stderr, "usage: identity migrate [up|down|status] [-to version] [-steps n]")
+		return 2
+	}
+
+	for _, migration := range done {
+		fmt.Printf("%s %d_%s\n", direction, migration.Version, migration.Name)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Migrate %s complete: %d migrations.\n", direction, len(done))
+	return 0
+}
+
+// printMigrationStatus lists every migration with the time it was applied
+func printMigrationStatus(ctx context.Context, db *database.Database) int {
+	states, err := db.MigrationStatus(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+
+	for _, state := range states {
+		applied := "pending"
+		if state.AppliedAt != nil {
+			applied = state.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%d_%s\t%s\n", state.Version, state.Name, applied)
+	}
+	return 0
+}
+
+// openCommandDatabase connects to the identity database for CLI commands
+func openCommandDatabase(ctx context.Context) (*database.Database, *gorm.DB, error) {
+	dsn := os.Getenv("IDENTITY_DSN")
+	if dsn == "" {
+		return nil, nil, fmt.Errorf("IDENTITY_DSN environment variable is not set")
+	}
+
+	db := database.NewDB(dsn)
+	conn, err := db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return db, conn, nil
+}
+
+// confirm asks the operator for an explicit "yes" on stdin
+func confirm(prompt string) bool {
+	fmt.Print(prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(answer) == "yes"
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/artifacts"
+	"github.com/gabehamasaki/momentum/services/identity/outbox"
+	"github.com/gabehamasaki/momentum/services/identity/services"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/config"
+)
+
+// serviceConfig holds the environment configuration of the identity service
+type serviceConfig struct {
+	Server   config.Server
+	Database config.Database
+	Logger   config.Logger
+	Auth     config.Auth
+	Cache    config.Cache
+	Events   config.Events
+
+	// NATSRPCMethods are served over NATS request-reply in addition to gRPC and Connect
+	NATSRPCMethods []string
+
+	ErrorReporting config.ErrorReporting
+
+	ConfigSigningKey shared.Secret
+
+	DeprovisioningInterval time.Duration
+
+	// OutboxRelayInterval is how often pending events are published from the outbox
+	OutboxRelayInterval time.Duration
+	// OutboxMaxAttempts is how often the bus may reject an event before it is dead-lettered (0 never)
+	OutboxMaxAttempts int
+
+	// ShadowSampleRate is the fraction of calls to shadowed methods also sent to their
+	// alternate implementation (0 disables shadowing)
+	ShadowSampleRate float64
+
+	// UserCacheTTL is how long users read by GetUser stay cached (0 disables the cache)
+	UserCacheTTL time.Duration
+
+	PasswordResetTTL     time.Duration
+	PasswordResetLimit   int
+	PasswordResetWebhook string
+
+	EmailVerificationTTL     time.Duration
+	EmailVerificationLimit   int
+	EmailVerificationWebhook string
+
+	// Exports are stored under ArtifactDir and downloaded through ArtifactBaseURL links
+	// signed with ArtifactSigningKey, valid for ArtifactURLTTL
+	ArtifactDir        string
+	ArtifactBaseURL    string
+	ArtifactSigningKey shared.Secret
+	ArtifactURLTTL     time.Duration
+
+	SensitiveFieldsFile string
+	AccessLogPath       string
+	LargePayloadBytes   int
+	SLOFile             string
+	AlertRulesFile      string
+
+	// ExpensiveCall flags calls using more resources than these; a fraction
+	// ExpensiveCallTraceRate of them is logged with the statements they ran
+	ExpensiveCall          shared.CostThresholds
+	ExpensiveCallTraceRate float64
+
+	// MetricsTenants are always labeled; up to MetricsTenantLimit others are labeled as they appear
+	MetricsTenants     []string
+	MetricsTenantLimit int
+}
+
+// loadConfig reads the service configuration, returning every invalid or missing variable at once
+func loadConfig() (*serviceConfig, error) {
+	env := shared.NewEnv()
+	config.LoadFile(env)
+
+	server := config.LoadServer(env, "IDENTITY", config.Server{GRPCPort: "50051", HTTPPort: "8080", MetricsPort: "9090"})
+
+	cfg := &serviceConfig{
+		Server:   server,
+		Database: config.LoadDatabase(env, "IDENTITY"),
+		Logger:   config.LoadLogger(env, server, "/var/log/identity-service.log"),
+		Auth:     config.LoadAuth(env, server),
+		Cache:    config.LoadCache(env),
+		Events:   config.LoadEvents(env),
+
+		NATSRPCMethods: shared.SplitList(env.String("NATS_RPC_METHODS", "")),
+
+		ErrorReporting: config.LoadErrorReporting(env),
+
+		// Bundles exported from production must always be signed
+		ConfigSigningKey: env.Secret("IDENTITY_CONFIG_SIGNING_KEY", server.IsProduction()),
+
+		DeprovisioningInterval: env.Duration("IDENTITY_DEPROVISIONING_INTERVAL", time.Minute),
+
+		OutboxRelayInterval: env.Duration("OUTBOX_RELAY_INTERVAL", outbox.DefaultRelayInterval),
+		OutboxMaxAttempts:   env.Int("OUTBOX_MAX_ATTEMPTS", outbox.DefaultMaxAttempts),
+
+		ShadowSampleRate: env.Float("SHADOW_SAMPLE_RATE", 0),
+
+		UserCacheTTL: env.Duration("USER_CACHE_TTL", services.DefaultUserCacheTTL),
+
+		PasswordResetTTL:     env.Duration("PASSWORD_RESET_TTL", services.DefaultPasswordResetTTL),
+		PasswordResetLimit:   env.Int("PASSWORD_RESET_LIMIT", services.DefaultPasswordResetLimit),
+		PasswordResetWebhook: env.String("PASSWORD_RESET_WEBHOOK", ""),
+
+		EmailVerificationTTL:     env.Duration("EMAIL_VERIFICATION_TTL", services.DefaultEmailVerificationTTL),
+		EmailVerificationLimit:   env.Int("EMAIL_VERIFICATION_LIMIT", services.DefaultEmailVerificationLimit),
+		EmailVerificationWebhook: env.String("EMAIL_VERIFICATION_WEBHOOK", ""),
+
+		ArtifactDir:        env.String("ARTIFACT_DIR", "data/artifacts"),
+		ArtifactBaseURL:    env.String("ARTIFACT_BASE_URL", "http://localhost:"+server.HTTPPort),
+		ArtifactSigningKey: env.Secret("ARTIFACT_SIGNING_KEY", server.IsProduction()),
+		ArtifactURLTTL:     env.Duration("ARTIFACT_URL_TTL", artifacts.DefaultURLTTL),
+
+		SensitiveFieldsFile: env.String("SENSITIVE_FIELDS_FILE", ""),
+		AccessLogPath:       env.String("ACCESS_LOG_PATH", ""),
+		LargePayloadBytes:   env.Int("GRPC_LARGE_PAYLOAD_BYTES", shared.DefaultLargePayloadBytes),
+		SLOFile:             env.String("SLO_FILE", ""),
+		AlertRulesFile:      env.String("ALERT_RULES_FILE", ""),
+
+		ExpensiveCall: shared.CostThresholds{
+			Statements:      env.Int("EXPENSIVE_CALL_STATEMENTS", 0),
+			Bytes:           env.Int("EXPENSIVE_CALL_BYTES", 0),
+			DownstreamCalls: env.Int("EXPENSIVE_CALL_DOWNSTREAM_CALLS", 0),
+		},
+		ExpensiveCallTraceRate: env.Float("EXPENSIVE_CALL_TRACE_RATE", 1),
+
+		MetricsTenants:     shared.SplitList(env.String("METRICS_TENANTS", "")),
+		MetricsTenantLimit: env.Int("METRICS_TENANT_LIMIT", shared.DefaultTenantLabelLimit),
+	}
+	if cfg.ExpensiveCallTraceRate < 0 || cfg.ExpensiveCallTraceRate > 1 {
+		env.Invalid("EXPENSIVE_CALL_TRACE_RATE", "must be between 0 and 1")
+	}
+	if cfg.ShadowSampleRate < 0 || cfg.ShadowSampleRate > 1 {
+		env.Invalid("SHADOW_SAMPLE_RATE", "must be between 0 and 1")
+	}
+	if len(cfg.NATSRPCMethods) > 0 && cfg.Events.NATSURL.Reveal() == "" {
+		env.Invalid("NATS_RPC_METHODS", "requires NATS_URL")
+	}
+	if cfg.OutboxMaxAttempts < 0 {
+		env.Invalid("OUTBOX_MAX_ATTEMPTS", "must not be negative")
+	}
+	if cfg.MetricsTenantLimit < 0 {
+		env.Invalid("METRICS_TENANT_LIMIT", "must not be negative")
+	}
+
+	return cfg, env.Err()
+}
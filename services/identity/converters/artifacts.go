@@ -0,0 +1,24 @@
+package converters
+
+import (
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+)
+
+// Artifact converts artifact metadata along with its signed download URL
+func Artifact(artifact *models.Artifact, url string, expiresAt time.Time) *proto.Artifact {
+	return &proto.Artifact{
+		Id:                   artifact.ID,
+		Kind:                 artifact.Kind,
+		Name:                 artifact.Name,
+		ContentType:          artifact.ContentType,
+		Sha256:               artifact.Digest,
+		Size:                 artifact.Size,
+		CreatedBy:            artifact.CreatedBy,
+		CreatedAt:            Time(artifact.CreatedAt),
+		DownloadUrl:          url,
+		DownloadUrlExpiresAt: Time(expiresAt),
+	}
+}
@@ -0,0 +1,46 @@
+package converters
+
+import (
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+)
+
+// AuditEvents converts audit trail entries
+func AuditEvents(events []models.AuditEvent) []*proto.AuditEvent {
+	protoEvents := make([]*proto.AuditEvent, len(events))
+	for i, event := range events {
+		protoEvents[i] = &proto.AuditEvent{
+			Id:         event.ID,
+			ActorId:    event.ActorID,
+			TenantId:   event.TenantID,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetId:   event.TargetID,
+			Before:     event.Before,
+			After:      event.After,
+			Code:       event.Code,
+			CreatedAt:  Time(event.CreatedAt),
+		}
+	}
+	return protoEvents
+}
+
+// AuditEventModel converts an API audit event back into a model, the inverse of AuditEvents
+func AuditEventModel(event *proto.AuditEvent) (models.AuditEvent, error) {
+	createdAt, err := ParseTime(event.GetCreatedAt())
+	if err != nil {
+		return models.AuditEvent{}, err
+	}
+	return models.AuditEvent{
+		ID:         event.GetId(),
+		ActorID:    event.GetActorId(),
+		TenantID:   event.GetTenantId(),
+		Action:     event.GetAction(),
+		TargetType: event.GetTargetType(),
+		TargetID:   event.GetTargetId(),
+		Before:     event.GetBefore(),
+		After:      event.GetAfter(),
+		Code:       event.GetCode(),
+		CreatedAt:  createdAt,
+	}, nil
+}
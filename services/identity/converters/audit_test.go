@@ -0,0 +1,55 @@
+package converters
+
+import (
+	"testing"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+)
+
+func TestAuditEventRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		event models.AuditEvent
+	}{
+		{
+			name: "update with snapshots",
+			event: models.AuditEvent{
+				ID: "event-1", ActorID: "user-1", TenantID: "acme", Action: "UpdateUser",
+				TargetType: "user", TargetID: "user-2", Before: `{"name":"Ada"}`, After: `{"name":"Ada Lovelace"}`,
+				Code: "OK", CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "platform call without snapshots",
+			event: models.AuditEvent{
+				ID: "event-2", ActorID: "admin", Action: "CreateTenant",
+				TargetType: "tenant", TargetID: "acme", Code: "PermissionDenied", CreatedAt: createdAt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := AuditEvents([]models.AuditEvent{tt.event})
+			if len(messages) != 1 {
+				t.Fatalf("AuditEvents returned %d events, want 1", len(messages))
+			}
+
+			got, err := AuditEventModel(messages[0])
+			if err != nil {
+				t.Fatalf("AuditEventModel: %v", err)
+			}
+			if got != tt.event {
+				t.Fatalf("round trip = %+v, want %+v", got, tt.event)
+			}
+		})
+	}
+}
+
+func TestAuditEventPopulatesEveryField(t *testing.T) {
+	event := models.AuditEvent{
+		ID: "event-1", ActorID: "user-1", TenantID: "acme", Action: "UpdateUser",
+		TargetType: "user", TargetID: "user-2", Before: "{}", After: "{}", Code: "OK", CreatedAt: createdAt,
+	}
+	assertPopulated(t, AuditEvents([]models.AuditEvent{event})[0])
+}
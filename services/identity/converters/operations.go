@@ -0,0 +1,48 @@
+package converters
+
+import (
+	"encoding/json"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Operation converts a long-running operation, decoding its JSON metadata and result
+func Operation(op *models.Operation) (*proto.Operation, error) {
+	metadata, err := jsonToStruct(op.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	result, err := jsonToStruct(op.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.Operation{
+		Id:        op.ID,
+		Kind:      op.Kind,
+		Status:    op.Status,
+		Done:      op.Done(),
+		Total:     op.Total,
+		Processed: op.Processed,
+		Metadata:  metadata,
+		Result:    result,
+		Error:     op.Error,
+		CreatedAt: Time(op.CreatedAt),
+		UpdatedAt: Time(op.UpdatedAt),
+	}, nil
+}
+
+// jsonToStruct converts a stored JSON object into a protobuf Struct
+func jsonToStruct(data string) (*structpb.Struct, error) {
+	if data == "" {
+		return nil, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
@@ -0,0 +1,36 @@
+package converters
+
+import (
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+)
+
+// SLOStatus converts the compliance of every SLO over the budget window
+func SLOStatus(statuses []shared.SLOStatus, window time.Duration) *proto.SLOStatusResponse {
+	resp := &proto.SLOStatusResponse{
+		Window: shared.FormatWindow(window),
+		Slos:   make([]*proto.SLOStatus, len(statuses)),
+	}
+
+	for i, s := range statuses {
+		burnRates := make([]*proto.BurnRate, len(shared.SLOBurnWindows))
+		for j, d := range shared.SLOBurnWindows {
+			burnRates[j] = &proto.BurnRate{Window: shared.FormatWindow(d), Rate: s.BurnRates[d]}
+		}
+
+		resp.Slos[i] = &proto.SLOStatus{
+			Method:               s.Method,
+			Requests:             s.Requests,
+			AvailabilityTarget:   s.SLO.Availability,
+			Availability:         s.Availability,
+			ErrorBudgetRemaining: s.ErrorBudgetRemaining,
+			BurnRates:            burnRates,
+			LatencyThreshold:     s.Latency,
+			LatencyTarget:        s.LatencyTarget,
+			LatencyCompliance:    s.LatencyCompliance,
+		}
+	}
+	return resp
+}
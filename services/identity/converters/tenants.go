@@ -0,0 +1,29 @@
+package converters
+
+import (
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+)
+
+// Tenant converts a tenant model into its API representation
+func Tenant(tenant *models.Tenant) *proto.Tenant {
+	return &proto.Tenant{
+		Id:        tenant.ID,
+		Name:      tenant.Name,
+		CreatedAt: Time(tenant.CreatedAt),
+		UpdatedAt: Time(tenant.UpdatedAt),
+	}
+}
+
+// TenantModel converts an API tenant back into a model, the inverse of Tenant
+func TenantModel(tenant *proto.Tenant) (models.Tenant, error) {
+	createdAt, err := ParseTime(tenant.GetCreatedAt())
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	updatedAt, err := ParseTime(tenant.GetUpdatedAt())
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	return models.Tenant{ID: tenant.GetId(), Name: tenant.GetName(), CreatedAt: createdAt, UpdatedAt: updatedAt}, nil
+}
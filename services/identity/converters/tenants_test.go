@@ -0,0 +1,33 @@
+package converters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+)
+
+func TestTenantRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant models.Tenant
+	}{
+		{name: "new", tenant: models.Tenant{ID: "acme", Name: "Acme", CreatedAt: createdAt, UpdatedAt: createdAt}},
+		{name: "renamed", tenant: models.Tenant{ID: "globex", Name: "Globex Corp.", CreatedAt: createdAt, UpdatedAt: createdAt.Add(48 * time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := Tenant(&tt.tenant)
+			assertPopulated(t, message)
+
+			got, err := TenantModel(message)
+			if err != nil {
+				t.Fatalf("TenantModel: %v", err)
+			}
+			if got != tt.tenant {
+				t.Fatalf("round trip = %+v, want %+v", got, tt.tenant)
+			}
+		})
+	}
+}
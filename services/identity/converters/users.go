@@ -0,0 +1,141 @@
+package converters
+
+import (
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/services"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"gorm.io/gorm"
+)
+
+// TimeLayout is the format used for every timestamp exposed by the identity API
+const TimeLayout = "2006-01-02 15:04:05"
+
+// Time formats a timestamp using TimeLayout
+func Time(t time.Time) string {
+	return t.Format(TimeLayout)
+}
+
+// OptionalTime formats a nullable timestamp, returning nil when unset
+func OptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := Time(*t)
+	return &formatted
+}
+
+// ParseTime parses a timestamp formatted by Time; having no zone, it is read as UTC
+func ParseTime(value string) (time.Time, error) {
+	return time.Parse(TimeLayout, value)
+}
+
+// ParseOptionalTime parses a nullable timestamp formatted by OptionalTime
+func ParseOptionalTime(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := ParseTime(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// DeletedAt formats a soft delete timestamp, returning nil for live records
+func DeletedAt(deletedAt gorm.DeletedAt) *string {
+	if !deletedAt.Valid {
+		return nil
+	}
+	return OptionalTime(&deletedAt.Time)
+}
+
+// User converts a user model into its API representation; Role must be preloaded
+func User(user *models.User) *proto.User {
+	protoUser := &proto.User{}
+	FillUser(protoUser, user)
+	return protoUser
+}
+
+// FillUser populates an existing message, letting callers allocate messages in bulk
+func FillUser(dst *proto.User, user *models.User) {
+	dst.Id = user.ID
+	dst.Name = user.Name
+	dst.Email = user.Email
+	dst.Role = user.Role.Name
+	dst.CreatedAt = Time(user.CreatedAt)
+	dst.ScheduleDeactivationAt = OptionalTime(user.ScheduleDeactivationAt)
+	dst.DeletedAt = DeletedAt(user.DeletedAt)
+	dst.EmailVerifiedAt = OptionalTime(user.EmailVerifiedAt)
+}
+
+// UserModel converts an API user back into a model, the inverse of User. Only
+// the role name is known, and timestamps keep the precision of TimeLayout.
+func UserModel(user *proto.User) (models.User, error) {
+	var err error
+	m := models.User{Name: user.GetName(), Email: user.GetEmail()}
+	m.ID = user.GetId()
+	m.Role.Name = user.GetRole()
+	if m.CreatedAt, err = ParseTime(user.GetCreatedAt()); err != nil {
+		return models.User{}, err
+	}
+	if m.ScheduleDeactivationAt, err = ParseOptionalTime(user.ScheduleDeactivationAt); err != nil {
+		return models.User{}, err
+	}
+	if m.EmailVerifiedAt, err = ParseOptionalTime(user.EmailVerifiedAt); err != nil {
+		return models.User{}, err
+	}
+	deletedAt, err := ParseOptionalTime(user.DeletedAt)
+	if err != nil {
+		return models.User{}, err
+	}
+	if deletedAt != nil {
+		m.DeletedAt = gorm.DeletedAt{Time: *deletedAt, Valid: true}
+	}
+	return m, nil
+}
+
+// Users converts a list of users, allocating every message in one block since
+// this runs for the whole user table
+func Users(users []models.User) []*proto.User {
+	messages := make([]proto.User, len(users))
+	protoUsers := make([]*proto.User, len(users))
+	for i := range users {
+		FillUser(&messages[i], &users[i])
+		protoUsers[i] = &messages[i]
+	}
+	return protoUsers
+}
+
+// UserDetail converts a user with its role and permissions preloaded
+func UserDetail(user *models.User) *proto.GetUserResponse {
+	permissions := make([]string, 0, len(user.Permissions))
+	for _, perm := range user.Permissions {
+		permissions = append(permissions, perm.Name)
+	}
+
+	return &proto.GetUserResponse{
+		Name:                   user.Name,
+		Email:                  user.Email,
+		Role:                   user.Role.Name,
+		RoleId:                 user.Role.ID,
+		Permissions:            permissions,
+		CreatedAt:              Time(user.CreatedAt),
+		ScheduleDeactivationAt: OptionalTime(user.ScheduleDeactivationAt),
+		DeletedAt:              DeletedAt(user.DeletedAt),
+	}
+}
+
+// FieldChanges converts the before/after diff computed by the service layer
+func FieldChanges(changes []services.FieldChange) []*proto.FieldChange {
+	protoChanges := make([]*proto.FieldChange, 0, len(changes))
+	for _, change := range changes {
+		protoChanges = append(protoChanges, &proto.FieldChange{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	return protoChanges
+}
@@ -0,0 +1,164 @@
+package converters
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"gorm.io/gorm"
+)
+
+// Timestamps round trip only at the precision of TimeLayout, in UTC
+var (
+	createdAt  = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	verifiedAt = time.Date(2026, 1, 3, 8, 0, 0, 0, time.UTC)
+	scheduleAt = time.Date(2026, 6, 30, 23, 59, 59, 0, time.UTC)
+	deletedAt  = time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// assertPopulated fails unless every field of msg is set, so a field added to the
+// proto without a mapping is caught
+func assertPopulated(t *testing.T, msg protoreflect.ProtoMessage) {
+	t.Helper()
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if field := fields.Get(i); !m.Has(field) {
+			t.Errorf("%s.%s is not populated", m.Descriptor().Name(), field.Name())
+		}
+	}
+}
+
+func TestUserRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		user models.User
+	}{
+		{
+			name: "active",
+			user: models.User{Name: "Ada Lovelace", Email: "ada@example.com"},
+		},
+		{
+			name: "verified and scheduled for deactivation",
+			user: models.User{Name: "Grace Hopper", Email: "grace@example.com", EmailVerifiedAt: &verifiedAt, ScheduleDeactivationAt: &scheduleAt},
+		},
+		{
+			name: "deactivated",
+			user: models.User{
+				BaseModel:       model.BaseModel{DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+				Name:            "Alan Turing",
+				Email:           "alan@example.com",
+				EmailVerifiedAt: &verifiedAt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.user
+			want.ID = "user-1"
+			want.CreatedAt = createdAt
+			want.Role = models.Role{Name: "member"}
+
+			got, err := UserModel(User(&want))
+			if err != nil {
+				t.Fatalf("UserModel: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUserPopulatesEveryField(t *testing.T) {
+	user := models.User{
+		BaseModel:              model.BaseModel{ID: "user-1", CreatedAt: createdAt, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+		Name:                   "Ada Lovelace",
+		Email:                  "ada@example.com",
+		Role:                   models.Role{Name: "member"},
+		EmailVerifiedAt:        &verifiedAt,
+		ScheduleDeactivationAt: &scheduleAt,
+	}
+
+	assertPopulated(t, User(&user))
+
+	// The bulk conversion of GetUsers must agree with the single one
+	if got := Users([]models.User{user}); !proto.Equal(got[0], User(&user)) {
+		t.Fatalf("Users()[0] = %v, want %v", got[0], User(&user))
+	}
+}
+
+func TestUserDetailPopulatesEveryField(t *testing.T) {
+	role := models.Role{BaseModel: model.BaseModel{ID: "role-1"}, Name: "member"}
+	user := models.User{
+		BaseModel:              model.BaseModel{ID: "user-1", CreatedAt: createdAt, DeletedAt: gorm.DeletedAt{Time: deletedAt, Valid: true}},
+		Name:                   "Ada Lovelace",
+		Email:                  "ada@example.com",
+		RoleID:                 role.ID,
+		Role:                   role,
+		ScheduleDeactivationAt: &scheduleAt,
+		Permissions:            []*models.Permission{{Name: "user.view"}, {Name: "profile.edit"}},
+	}
+
+	detail := UserDetail(&user)
+	assertPopulated(t, detail)
+	if want := []string{"user.view", "profile.edit"}; !reflect.DeepEqual(detail.GetPermissions(), want) {
+		t.Fatalf("permissions = %v, want %v", detail.GetPermissions(), want)
+	}
+}
+
+func TestUserModelRejectsMalformedTimestamps(t *testing.T) {
+	user := User(&models.User{BaseModel: model.BaseModel{CreatedAt: createdAt}})
+	malformed := "2026-13-45"
+	user.EmailVerifiedAt = &malformed
+
+	if _, err := UserModel(user); err == nil {
+		t.Fatal("UserModel accepted a malformed email_verified_at")
+	}
+}
+
+func listedUsers(n int) []models.User {
+	role := models.Role{BaseModel: model.BaseModel{ID: "role-1"}, Name: "member"}
+
+	users := make([]models.User, n)
+	for i := range users {
+		id := strconv.Itoa(i)
+		users[i] = models.User{
+			BaseModel: model.BaseModel{ID: "user-" + id, CreatedAt: createdAt},
+			Name:      "User " + id,
+			Email:     "user" + id + "@example.com",
+			RoleID:    role.ID,
+			Role:      role,
+		}
+	}
+	return users
+}
+
+// TestUsersAllocations enforces the allocation budget of GetUsers: one block of
+// messages, one slice of pointers and the formatted created_at of each user
+func TestUsersAllocations(t *testing.T) {
+	users := listedUsers(100)
+	budget := float64(2 + len(users))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Users(users)
+	})
+	if allocs > budget {
+		t.Fatalf("Users allocated %v times for %d users, budget is %v", allocs, len(users), budget)
+	}
+}
+
+func BenchmarkUsers(b *testing.B) {
+	users := listedUsers(1000)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		Users(users)
+	}
+}
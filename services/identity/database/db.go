@@ -2,15 +2,18 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/model"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 // Database representa a configuração e conexão com o banco de dados
@@ -19,6 +22,8 @@ type Database struct {
 	connection *gorm.DB
 	mu         sync.RWMutex
 	config     *DatabaseConfig
+	failover   failoverState
+	replicas   *replicaSet
 }
 
 // DatabaseConfig contém configurações para o banco de dados
@@ -29,6 +34,14 @@ type DatabaseConfig struct {
 	ConnectionMaxIdleTime time.Duration
 	LogLevel              logger.LogLevel
 	SlowQueryThreshold    time.Duration
+	// Plugins são registrados em cada nova conexão (ex.: contador de statements)
+	Plugins []gorm.Plugin
+	// Resolver descobre o primário gravável após um failover (nil desativa a detecção)
+	Resolver EndpointResolver
+	// OnFailover é chamado após cada tentativa de troca de primário
+	OnFailover func(stats FailoverStats, err error)
+	// ReplicaDSNs são réplicas de leitura usadas por ReadConnWithContext, via dbresolver
+	ReplicaDSNs []string
 }
 
 // DatabaseStats contém estatísticas da conexão com o banco de dados
@@ -71,12 +84,22 @@ func (d *Database) Conn() (*gorm.DB, error) {
 	return d.ConnWithContext(context.Background())
 }
 
-// ConnWithContext retorna a conexão com o banco de dados com contexto
+// ConnWithContext retorna a conexão com o primário com contexto
 func (d *Database) ConnWithContext(ctx context.Context) (*gorm.DB, error) {
+	conn, err := d.connWithContext(ctx)
+	if err != nil || len(d.config.ReplicaDSNs) == 0 {
+		return conn, err
+	}
+	// Sem dbresolver.Write, as leituras fora de transações iriam para as réplicas
+	return conn.Clauses(dbresolver.Write).Session(&gorm.Session{}), nil
+}
+
+// connWithContext retorna a conexão com contexto, sem escolher entre primário e réplicas
+func (d *Database) connWithContext(ctx context.Context) (*gorm.DB, error) {
 	d.mu.RLock()
 	if d.connection != nil {
 		defer d.mu.RUnlock()
-		return d.connection, nil
+		return d.connection.WithContext(ctx), nil
 	}
 	d.mu.RUnlock()
 
@@ -85,30 +108,63 @@ func (d *Database) ConnWithContext(ctx context.Context) (*gorm.DB, error) {
 
 	// Double-check locking pattern
 	if d.connection != nil {
-		return d.connection, nil
+		return d.connection.WithContext(ctx), nil
 	}
 
-	return d.createConnection(ctx)
+	conn, err := d.createConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.WithContext(ctx), nil
 }
 
-// createConnection cria uma nova conexão com o banco de dados
+// createConnection cria uma nova conexão com o primário
 func (d *Database) createConnection(ctx context.Context) (*gorm.DB, error) {
 	if d.DSN == "" {
 		return nil, errors.New("DSN não pode estar vazio")
 	}
 
+	plugins := d.config.Plugins
+	if d.config.Resolver != nil {
+		plugins = append(plugins[:len(plugins):len(plugins)], readOnlyDetector{database: d})
+	}
+	if resolver := d.replicaResolver(ctx); resolver != nil {
+		plugins = append(plugins[:len(plugins):len(plugins)], resolver)
+	}
+
+	db, err := d.openConnection(ctx, d.DSN, plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	d.connection = db
+	return d.connection, nil
+}
+
+// openConnection conecta ao DSN, registra os plugins e configura o pool
+func (d *Database) openConnection(ctx context.Context, dsn string, plugins []gorm.Plugin) (*gorm.DB, error) {
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(d.config.LogLevel),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
+		// O ping é feito abaixo com o contexto; o dbresolver herda esta configuração e
+		// não deve falhar ao registrar uma réplica fora do ar
+		DisableAutomaticPing: true,
 	}
 
-	db, err := gorm.Open(postgres.Open(d.DSN), gormConfig)
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("falha ao conectar ao banco de dados: %w", err)
 	}
 
+	// O validador é sempre registrado para que as tags `validate` valham em qualquer escrita
+	for _, plugin := range append([]gorm.Plugin{model.Validator{}}, plugins...) {
+		if err := db.Use(plugin); err != nil {
+			return nil, fmt.Errorf("falha ao registrar plugin '%s': %w", plugin.Name(), err)
+		}
+	}
+
 	// Configurar pool de conexões
 	if err := d.configureConnectionPool(db); err != nil {
 		return nil, fmt.Errorf("falha ao configurar pool de conexões: %w", err)
@@ -121,11 +177,11 @@ func (d *Database) createConnection(ctx context.Context) (*gorm.DB, error) {
 	}
 
 	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("falha ao fazer ping no banco de dados: %w", err)
 	}
 
-	d.connection = db
-	return d.connection, nil
+	return db, nil
 }
 
 // configureConnectionPool configura o pool de conexões do banco de dados
@@ -135,19 +191,25 @@ func (d *Database) configureConnectionPool(db *gorm.DB) error {
 		return err
 	}
 
+	d.configurePool(sqlDB)
+	return nil
+}
+
+// configurePool aplica os limites do pool configurados, no primário e nas réplicas
+func (d *Database) configurePool(sqlDB *sql.DB) {
 	sqlDB.SetMaxOpenConns(d.config.MaxOpenConnections)
 	sqlDB.SetMaxIdleConns(d.config.MaxIdleConnections)
 	sqlDB.SetConnMaxLifetime(d.config.ConnectionMaxLifetime)
 	sqlDB.SetConnMaxIdleTime(d.config.ConnectionMaxIdleTime)
-
-	return nil
 }
 
-// Close fecha a conexão com o banco de dados
+// Close fecha a conexão com o banco de dados e com as réplicas
 func (d *Database) Close() error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	d.closeReplicas()
+
 	if d.connection == nil {
 		return nil
 	}
@@ -170,27 +232,26 @@ func (d *Database) Migrate() error {
 	return d.MigrateWithContext(context.Background())
 }
 
-// MigrateWithContext executa as migrações do banco de dados com contexto
+// MigrateWithContext aplica todas as migrações SQL pendentes
 func (d *Database) MigrateWithContext(ctx context.Context) error {
-	db, err := d.ConnWithContext(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao conectar para migração: %w", err)
-	}
+	_, err := d.MigrateUp(ctx, 0)
+	return err
+}
 
-	// Lista de modelos para migrar
-	models := []interface{}{
-		&models.Permission{},
-		&models.Role{},
-		&models.User{},
+// PendingMigrations lista as migrações que o Migrate ainda aplicaria
+func (d *Database) PendingMigrations(ctx context.Context) ([]string, error) {
+	states, err := d.MigrationStatus(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, model := range models {
-		if err := db.WithContext(ctx).AutoMigrate(model); err != nil {
-			return fmt.Errorf("falha ao migrar modelo %T: %w", model, err)
+	var pending []string
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%d_%s", state.Version, state.Name))
 		}
 	}
-
-	return nil
+	return pending, nil
 }
 
 // Seeder popula o banco de dados com dados iniciais
@@ -228,6 +289,16 @@ func (d *Database) seedPermissions(tx *gorm.DB) error {
 		"user.delete",
 		"user.store",
 		"user.update",
+		"role.view",
+		"role.manage",
+		"operation.view",
+		"operation.cancel",
+		"diagnostics.view",
+		"audit.view",
+		"artifact.download",
+		"tenant.view",
+		"tenant.manage",
+		"tenant.deprovision",
 	}
 
 	for _, name := range permissions {
@@ -254,6 +325,9 @@ func (d *Database) seedRoles(tx *gorm.DB) error {
 		"admin": {
 			"profile.edit", "profile.view",
 			"user.view", "user.delete", "user.store", "user.update",
+			"role.view", "role.manage", "operation.view", "operation.cancel",
+			"diagnostics.view", "audit.view", "artifact.download",
+			"tenant.view", "tenant.manage", "tenant.deprovision",
 		},
 	}
 
@@ -0,0 +1,275 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// readOnlySQLState é o SQLSTATE retornado ao tentar escrever em um servidor somente leitura
+const readOnlySQLState = "25006"
+
+// EndpointResolver descobre o DSN do primário gravável atual
+type EndpointResolver interface {
+	Resolve(ctx context.Context) (string, error)
+}
+
+// StaticResolver sempre retorna o mesmo DSN. Útil quando o DSN aponta para um
+// endpoint de cluster cujo DNS é atualizado pelo provedor após o failover:
+// reconectar já resolve o novo primário.
+type StaticResolver string
+
+// Resolve implementa EndpointResolver
+func (r StaticResolver) Resolve(ctx context.Context) (string, error) {
+	return string(r), nil
+}
+
+// CandidateResolver testa uma lista de DSNs (uma por região) e retorna o primeiro que aceita escrita
+type CandidateResolver struct {
+	DSNs    []string
+	Timeout time.Duration
+}
+
+// NewCandidateResolver cria um CandidateResolver com timeout padrão por candidato
+func NewCandidateResolver(dsns ...string) *CandidateResolver {
+	return &CandidateResolver{DSNs: dsns, Timeout: 5 * time.Second}
+}
+
+// Resolve implementa EndpointResolver
+func (r *CandidateResolver) Resolve(ctx context.Context) (string, error) {
+	var errs []error
+	for _, dsn := range r.DSNs {
+		probeCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+		readOnly, err := probeReadOnly(probeCtx, dsn)
+		cancel()
+
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !readOnly {
+			return dsn, nil
+		}
+	}
+
+	return "", fmt.Errorf("nenhum primário gravável encontrado entre %d candidatos: %w", len(r.DSNs), errors.Join(errs...))
+}
+
+// probeReadOnly abre uma conexão temporária e verifica se o servidor está em recuperação (réplica)
+func probeReadOnly(ctx context.Context, dsn string) (bool, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return false, fmt.Errorf("falha ao conectar ao candidato: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return false, err
+	}
+	defer sqlDB.Close()
+
+	return isInRecovery(db.WithContext(ctx))
+}
+
+func isInRecovery(db *gorm.DB) (bool, error) {
+	var inRecovery bool
+	if err := db.Raw("SELECT pg_is_in_recovery()").Scan(&inRecovery).Error; err != nil {
+		return false, fmt.Errorf("falha ao verificar pg_is_in_recovery: %w", err)
+	}
+	return inRecovery, nil
+}
+
+// FailoverStats contém as métricas de troca de primário
+type FailoverStats struct {
+	// Failovers é o número de trocas de primário bem-sucedidas
+	Failovers int64
+	// FailedFailovers é o número de tentativas de troca que falharam
+	FailedFailovers int64
+	// ReadOnlyErrors é o número de escritas rejeitadas por um primário somente leitura
+	ReadOnlyErrors int64
+	// LastFailoverAt é o horário da última troca bem-sucedida
+	LastFailoverAt time.Time
+	// LastError é o erro da última tentativa que falhou
+	LastError string
+}
+
+// WriteMetrics exporta os contadores no formato de texto do Prometheus
+func (s FailoverStats) WriteMetrics(w io.Writer) {
+	fmt.Fprint(w, "# HELP database_failovers_total Successful switches to a new writable primary.\n")
+	fmt.Fprint(w, "# TYPE database_failovers_total counter\n")
+	fmt.Fprintf(w, "database_failovers_total %d\n", s.Failovers)
+	fmt.Fprint(w, "# HELP database_failover_failures_total Failed attempts to switch to a new primary.\n")
+	fmt.Fprint(w, "# TYPE database_failover_failures_total counter\n")
+	fmt.Fprintf(w, "database_failover_failures_total %d\n", s.FailedFailovers)
+	fmt.Fprint(w, "# HELP database_read_only_errors_total Writes rejected by a read-only primary.\n")
+	fmt.Fprint(w, "# TYPE database_read_only_errors_total counter\n")
+	fmt.Fprintf(w, "database_read_only_errors_total %d\n", s.ReadOnlyErrors)
+
+	// Sem troca até agora, o gauge fica em 0
+	var lastFailover int64
+	if !s.LastFailoverAt.IsZero() {
+		lastFailover = s.LastFailoverAt.Unix()
+	}
+	fmt.Fprint(w, "# HELP database_last_failover_timestamp_seconds Unix time of the last successful failover.\n")
+	fmt.Fprint(w, "# TYPE database_last_failover_timestamp_seconds gauge\n")
+	fmt.Fprintf(w, "database_last_failover_timestamp_seconds %d\n", lastFailover)
+}
+
+// WriteMetrics exporta as métricas de failover atuais; registre o Database em shared.Metrics
+func (d *Database) WriteMetrics(w io.Writer) {
+	d.FailoverStats().WriteMetrics(w)
+}
+
+// failoverState guarda os contadores de failover da instância
+type failoverState struct {
+	inProgress      atomic.Bool
+	failovers       atomic.Int64
+	failedFailovers atomic.Int64
+	readOnlyErrors  atomic.Int64
+	lastFailoverAt  atomic.Int64
+	lastError       atomic.Value
+}
+
+// FailoverStats retorna as métricas de failover acumuladas
+func (d *Database) FailoverStats() FailoverStats {
+	stats := FailoverStats{
+		Failovers:       d.failover.failovers.Load(),
+		FailedFailovers: d.failover.failedFailovers.Load(),
+		ReadOnlyErrors:  d.failover.readOnlyErrors.Load(),
+	}
+	if at := d.failover.lastFailoverAt.Load(); at != 0 {
+		stats.LastFailoverAt = time.Unix(0, at).UTC()
+	}
+	if lastErr, ok := d.failover.lastError.Load().(string); ok {
+		stats.LastError = lastErr
+	}
+	return stats
+}
+
+// IsReadOnly verifica se o servidor atual deixou de ser primário
+func (d *Database) IsReadOnly(ctx context.Context) (bool, error) {
+	db, err := d.ConnWithContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return isInRecovery(db)
+}
+
+// Failover resolve novamente o primário gravável e troca a conexão atual.
+// Chamadas concorrentes são ignoradas enquanto uma troca está em andamento.
+func (d *Database) Failover(ctx context.Context) error {
+	if d.config.Resolver == nil {
+		return errors.New("nenhum resolver de endpoint configurado")
+	}
+	if !d.failover.inProgress.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer d.failover.inProgress.Store(false)
+
+	err := d.switchEndpoint(ctx)
+	if err != nil {
+		d.failover.failedFailovers.Add(1)
+		d.failover.lastError.Store(err.Error())
+	} else {
+		d.failover.failovers.Add(1)
+		d.failover.lastFailoverAt.Store(time.Now().UnixNano())
+	}
+
+	if d.config.OnFailover != nil {
+		d.config.OnFailover(d.FailoverStats(), err)
+	}
+	return err
+}
+
+// switchEndpoint conecta ao novo primário e só descarta a conexão antiga em caso de sucesso
+func (d *Database) switchEndpoint(ctx context.Context) error {
+	dsn, err := d.config.Resolver.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao resolver primário: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	oldDSN, oldConnection := d.DSN, d.connection
+	d.DSN, d.connection = dsn, nil
+
+	if _, err := d.createConnection(ctx); err != nil {
+		d.DSN, d.connection = oldDSN, oldConnection
+		return fmt.Errorf("falha ao conectar ao novo primário: %w", err)
+	}
+
+	if oldConnection != nil {
+		if sqlDB, err := oldConnection.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+	return nil
+}
+
+// WatchTopology verifica periodicamente se o primário virou réplica e dispara o failover
+func (d *Database) WatchTopology(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			readOnly, err := d.IsReadOnly(checkCtx)
+			if err == nil && readOnly {
+				d.Failover(checkCtx)
+			}
+			cancel()
+		}
+	}
+}
+
+// readOnlyDetector é o plugin GORM que dispara o failover quando uma escrita é rejeitada
+type readOnlyDetector struct {
+	database *Database
+}
+
+func (p readOnlyDetector) Name() string {
+	return "momentum:read_only_detector"
+}
+
+func (p readOnlyDetector) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		name     string
+		register func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().After("gorm:create").Register},
+		{"update", callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().After("gorm:delete").Register},
+		{"raw", callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range registrations {
+		if err := r.register(p.Name()+":"+r.name, p.check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p readOnlyDetector) check(db *gorm.DB) {
+	var pgErr *pgconn.PgError
+	if db.Error == nil || !errors.As(db.Error, &pgErr) || pgErr.Code != readOnlySQLState {
+		return
+	}
+
+	p.database.failover.readOnlyErrors.Add(1)
+	// Em background: a conexão será trocada enquanto o chamador ainda segura a atual
+	go p.database.Failover(context.Background())
+}
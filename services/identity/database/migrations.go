@@ -0,0 +1,249 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID identifica o advisory lock que serializa réplicas migrando ao mesmo tempo
+const migrationLockID = 7_261_034_118
+
+// Migration é uma mudança versionada de esquema, lida de migrations/<versão>_<nome>.{up,down}.sql
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationState indica se uma migração já foi aplicada
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration é a linha de schema_migrations que registra uma versão aplicada
+type schemaMigration struct {
+	Version   int64 `gorm:"primarykey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrations retorna as migrações embutidas, ordenadas por versão
+func Migrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar migrações: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(file, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("nome de migração inválido: %s", file)
+		}
+		versionText, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(versionText, 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("versão de migração inválida: %s", file)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", file))
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler migração %s: %w", file, err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("versão %d usada por mais de uma migração", version)
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migração %d sem arquivo up", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// MigrationStatus lista todas as migrações com a data em que foram aplicadas
+func (d *Database) MigrationStatus(ctx context.Context) ([]MigrationState, error) {
+	db, err := d.ConnWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar para verificar migrações: %w", err)
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, len(migrations))
+	for i, migration := range migrations {
+		states[i] = MigrationState{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			states[i].AppliedAt = &row.AppliedAt
+		}
+	}
+	return states, nil
+}
+
+// MigrateUp aplica as migrações pendentes em ordem, até a versão target (0 aplica todas).
+// Cada migração roda em sua própria transação junto com o registro em schema_migrations.
+func (d *Database) MigrateUp(ctx context.Context, target int64) ([]Migration, error) {
+	db, err := d.ConnWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar para migração: %w", err)
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if target > 0 && migration.Version > target {
+			break
+		}
+
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			applied, err := lockMigrations(tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := applied[migration.Version]; ok {
+				return nil
+			}
+
+			if err := tx.Exec(migration.Up).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("falha ao aplicar migração %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// MigrateDown reverte as últimas steps migrações aplicadas, da mais recente para a mais antiga
+func (d *Database) MigrateDown(ctx context.Context, steps int) ([]Migration, error) {
+	db, err := d.ConnWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar para migração: %w", err)
+	}
+
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			applied, err := lockMigrations(tx)
+			if err != nil {
+				return err
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				return nil
+			}
+			if migration.Down == "" {
+				return errors.New("migração não possui arquivo down")
+			}
+
+			if err := tx.Exec(migration.Down).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Delete(&schemaMigration{Version: migration.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("falha ao reverter migração %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			done = append(done, migration)
+		}
+	}
+
+	return done, nil
+}
+
+// lockMigrations garante a tabela schema_migrations, obtém o advisory lock da
+// transação e retorna as versões já aplicadas
+func lockMigrations(tx *gorm.DB) (map[int64]schemaMigration, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+		return nil, fmt.Errorf("falha ao obter lock de migração: %w", err)
+	}
+	if err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    bigint PRIMARY KEY,
+		name       text NOT NULL,
+		applied_at timestamptz NOT NULL
+	)`).Error; err != nil {
+		return nil, fmt.Errorf("falha ao criar schema_migrations: %w", err)
+	}
+	return appliedMigrations(tx)
+}
+
+// appliedMigrations lê schema_migrations, tratando a tabela ausente como nenhuma migração aplicada
+func appliedMigrations(db *gorm.DB) (map[int64]schemaMigration, error) {
+	applied := make(map[int64]schemaMigration)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return applied, nil
+	}
+
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("falha ao ler schema_migrations: %w", err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,68 @@
+-- Esquema inicial, equivalente ao que o AutoMigrate criava. Usa IF NOT EXISTS para
+-- adotar bancos que já foram criados pelo AutoMigrate.
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id         bigserial PRIMARY KEY,
+    name       text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_permissions_deleted_at ON permissions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id         uuid PRIMARY KEY,
+    tenant_id  text NOT NULL DEFAULT '',
+    version    bigint NOT NULL DEFAULT 0,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name       text
+);
+CREATE INDEX IF NOT EXISTS idx_roles_tenant_id ON roles (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id       uuid REFERENCES roles (id),
+    permission_id bigint REFERENCES permissions (id),
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS users (
+    id                       uuid PRIMARY KEY,
+    tenant_id                text NOT NULL DEFAULT '',
+    version                  bigint NOT NULL DEFAULT 0,
+    created_at               timestamptz,
+    updated_at               timestamptz,
+    deleted_at               timestamptz,
+    name                     text,
+    email                    text,
+    password                 text,
+    role_id                  uuid REFERENCES roles (id),
+    schedule_deactivation_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_users_tenant_id ON users (tenant_id);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_users_schedule_deactivation_at ON users (schedule_deactivation_at);
+
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id       uuid REFERENCES users (id),
+    permission_id bigint REFERENCES permissions (id),
+    PRIMARY KEY (user_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS operations (
+    id               uuid PRIMARY KEY,
+    kind             text,
+    status           text,
+    total            bigint,
+    processed        bigint,
+    metadata         text,
+    result           text,
+    error            text,
+    cancel_requested boolean,
+    created_at       timestamptz,
+    updated_at       timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_operations_kind ON operations (kind);
+CREATE INDEX IF NOT EXISTS idx_operations_status ON operations (status);
@@ -0,0 +1,10 @@
+CREATE TABLE password_resets (
+    id         uuid PRIMARY KEY,
+    user_id    uuid NOT NULL REFERENCES users (id),
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_password_resets_token_hash ON password_resets (token_hash);
+CREATE INDEX idx_password_resets_user_id ON password_resets (user_id);
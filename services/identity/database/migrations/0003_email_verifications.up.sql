@@ -0,0 +1,12 @@
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+
+CREATE TABLE email_verifications (
+    id         uuid PRIMARY KEY,
+    user_id    uuid NOT NULL REFERENCES users (id),
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_email_verifications_token_hash ON email_verifications (token_hash);
+CREATE INDEX idx_email_verifications_user_id ON email_verifications (user_id);
@@ -0,0 +1,16 @@
+CREATE TABLE audit_events (
+    id          uuid PRIMARY KEY,
+    actor_id    text NOT NULL DEFAULT '',
+    tenant_id   text NOT NULL DEFAULT '',
+    action      text NOT NULL,
+    target_type text NOT NULL DEFAULT '',
+    target_id   text NOT NULL DEFAULT '',
+    before      text NOT NULL DEFAULT '',
+    after       text NOT NULL DEFAULT '',
+    code        text NOT NULL,
+    created_at  timestamptz NOT NULL
+);
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_action ON audit_events (action);
+CREATE INDEX idx_audit_events_target_id ON audit_events (target_id);
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
@@ -0,0 +1,12 @@
+CREATE TABLE artifacts (
+    id           uuid PRIMARY KEY,
+    kind         text NOT NULL,
+    name         text NOT NULL,
+    content_type text NOT NULL,
+    digest       text NOT NULL,
+    size         bigint NOT NULL,
+    created_by   text NOT NULL DEFAULT '',
+    created_at   timestamptz NOT NULL
+);
+CREATE INDEX idx_artifacts_kind ON artifacts (kind);
+CREATE INDEX idx_artifacts_digest ON artifacts (digest);
@@ -0,0 +1,9 @@
+CREATE TABLE tenant_offboardings (
+    tenant_id    text PRIMARY KEY,
+    operation_id uuid NOT NULL,
+    archive_id   uuid NOT NULL REFERENCES artifacts (id),
+    purge_after  timestamptz NOT NULL,
+    purged_at    timestamptz,
+    created_at   timestamptz NOT NULL
+);
+CREATE INDEX idx_tenant_offboardings_purge_after ON tenant_offboardings (purge_after);
@@ -0,0 +1,10 @@
+CREATE TABLE outbox_events (
+    id         uuid PRIMARY KEY,
+    type       text NOT NULL,
+    tenant_id  text NOT NULL DEFAULT '',
+    payload    jsonb NOT NULL,
+    attempts   integer NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX idx_outbox_events_created_at ON outbox_events (created_at);
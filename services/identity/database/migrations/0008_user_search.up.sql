@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_users_name_trgm ON users USING gin (name gin_trgm_ops);
+CREATE INDEX idx_users_email_trgm ON users USING gin (email gin_trgm_ops);
+-- Must match the document expression of UserRepository.Search
+CREATE INDEX idx_users_search ON users USING gin (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, '')));
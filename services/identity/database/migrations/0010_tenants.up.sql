@@ -0,0 +1,12 @@
+CREATE TABLE tenants (
+    id         text PRIMARY KEY,
+    name       text NOT NULL,
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+
+-- Tenants already referenced by users or roles
+INSERT INTO tenants (id, name, created_at, updated_at)
+SELECT tenant_id, tenant_id, now(), now()
+FROM (SELECT tenant_id FROM users UNION SELECT tenant_id FROM roles) AS referenced
+WHERE tenant_id <> '';
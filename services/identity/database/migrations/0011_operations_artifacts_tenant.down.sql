@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_artifacts_tenant_id;
+ALTER TABLE artifacts DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_operations_tenant_id;
+ALTER TABLE operations DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,10 @@
+ALTER TABLE operations ADD COLUMN tenant_id text NOT NULL DEFAULT '';
+CREATE INDEX idx_operations_tenant_id ON operations (tenant_id);
+
+ALTER TABLE artifacts ADD COLUMN tenant_id text NOT NULL DEFAULT '';
+CREATE INDEX idx_artifacts_tenant_id ON artifacts (tenant_id);
+
+-- Tenant archives belong to the tenant they export
+UPDATE artifacts SET tenant_id = o.tenant_id
+FROM tenant_offboardings o
+WHERE artifacts.id = o.archive_id;
@@ -0,0 +1,2 @@
+-- Tokens issued before the last password change are revoked
+ALTER TABLE users ADD COLUMN password_changed_at timestamptz;
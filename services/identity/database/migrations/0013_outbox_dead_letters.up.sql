@@ -0,0 +1,11 @@
+-- Events the relay gave up on, kept for inspection and requeueing
+CREATE TABLE outbox_dead_letters (
+    id         uuid PRIMARY KEY,
+    type       text NOT NULL,
+    tenant_id  text NOT NULL DEFAULT '',
+    payload    jsonb NOT NULL,
+    attempts   integer NOT NULL DEFAULT 0,
+    last_error text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL,
+    failed_at  timestamptz NOT NULL
+);
@@ -0,0 +1,3 @@
+-- An email belongs to one active user per tenant; deactivated users keep theirs.
+-- Fails if active duplicates already exist: resolve them before migrating.
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, lower(email)) WHERE deleted_at IS NULL;
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replica é uma réplica de leitura; healthy é atualizado por WatchReplicas
+type replica struct {
+	pool    *sql.DB
+	healthy atomic.Bool
+}
+
+// replicaSet guarda as conexões com as réplicas, abertas uma vez e compartilhadas
+// pelo dbresolver de cada conexão com o primário, inclusive após um failover.
+// Também é a Policy do dbresolver: as leituras são distribuídas em round-robin
+// entre as réplicas saudáveis.
+type replicaSet struct {
+	replicas []*replica
+	byPool   map[gorm.ConnPool]*replica
+	next     atomic.Uint64
+}
+
+// Resolve implementa dbresolver.Policy. Com todas as réplicas fora do ar
+// ReadConnWithContext já usa o primário, então qualquer uma serve.
+func (s *replicaSet) Resolve(pools []gorm.ConnPool) gorm.ConnPool {
+	for range pools {
+		pool := pools[s.next.Add(1)%uint64(len(pools))]
+		if r, ok := s.byPool[pool]; !ok || r.healthy.Load() {
+			return pool
+		}
+	}
+	return pools[0]
+}
+
+// anyHealthy informa se alguma réplica pode receber leituras
+func (s *replicaSet) anyHealthy() bool {
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadConnWithContext retorna uma conexão para consultas que toleram o atraso de
+// replicação: o dbresolver as envia a uma réplica saudável, e as escritas feitas
+// por ela vão para o primário. Sem réplicas configuradas, ou com todas fora do
+// ar, usa o primário.
+func (d *Database) ReadConnWithContext(ctx context.Context) (*gorm.DB, error) {
+	conn, err := d.connWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.RLock()
+	replicas := d.replicas
+	d.mu.RUnlock()
+	if replicas == nil {
+		return conn, nil
+	}
+	if !replicas.anyHealthy() {
+		return d.ConnWithContext(ctx)
+	}
+	// dbresolver.Read também vale para Raw e para o BEGIN de transações, como ReadSnapshot
+	return conn.Clauses(dbresolver.Read).Session(&gorm.Session{}), nil
+}
+
+// replicaResolver retorna o plugin dbresolver com as réplicas configuradas, que
+// são conectadas na primeira chamada; nil sem réplicas
+func (d *Database) replicaResolver(ctx context.Context) gorm.Plugin {
+	if len(d.config.ReplicaDSNs) == 0 {
+		return nil
+	}
+	if d.replicas == nil {
+		d.replicas = d.connectReplicas(ctx)
+	}
+
+	dialectors := make([]gorm.Dialector, len(d.replicas.replicas))
+	for i, r := range d.replicas.replicas {
+		dialectors[i] = postgres.New(postgres.Config{Conn: r.pool})
+	}
+	return dbresolver.Register(dbresolver.Config{Replicas: dialectors, Policy: d.replicas})
+}
+
+// connectReplicas abre um pool por réplica; as que não respondem ficam fora do
+// rodízio até WatchReplicas conseguir conectar
+func (d *Database) connectReplicas(ctx context.Context) *replicaSet {
+	set := &replicaSet{byPool: make(map[gorm.ConnPool]*replica, len(d.config.ReplicaDSNs))}
+	for _, dsn := range d.config.ReplicaDSNs {
+		pool, err := sql.Open("pgx", dsn)
+		if err != nil {
+			continue
+		}
+		d.configurePool(pool)
+
+		r := &replica{pool: pool}
+		r.healthy.Store(pool.PingContext(ctx) == nil)
+		set.replicas = append(set.replicas, r)
+		set.byPool[pool] = r
+	}
+	return set
+}
+
+// WatchReplicas verifica periodicamente as réplicas, retirando do rodízio as que
+// não respondem e devolvendo as que se recuperam
+func (d *Database) WatchReplicas(ctx context.Context, interval time.Duration) {
+	if len(d.config.ReplicaDSNs) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.mu.RLock()
+			replicas := d.replicas
+			d.mu.RUnlock()
+			if replicas == nil {
+				continue
+			}
+
+			for _, r := range replicas.replicas {
+				checkCtx, cancel := context.WithTimeout(ctx, interval)
+				r.healthy.Store(r.pool.PingContext(checkCtx) == nil)
+				cancel()
+			}
+		}
+	}
+}
+
+// closeReplicas fecha as conexões com as réplicas; chamado com d.mu travado
+func (d *Database) closeReplicas() {
+	if d.replicas == nil {
+		return
+	}
+	for _, r := range d.replicas.replicas {
+		r.healthy.Store(false)
+		r.pool.Close()
+	}
+	d.replicas = nil
+}
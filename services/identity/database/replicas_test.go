@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"gorm.io/gorm"
+)
+
+// newReplicatedMockDatabase is newMockDatabase with one replica, also on sqlmock,
+// registered through dbresolver like createConnection does
+func newReplicatedMockDatabase(t *testing.T) (*Database, sqlmock.Sqlmock, *replica, sqlmock.Sqlmock) {
+	t.Helper()
+	db, primary := newMockDatabase(t)
+
+	conn, replicaMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	r := &replica{pool: conn}
+	r.healthy.Store(true)
+	db.config.ReplicaDSNs = []string{"replica"}
+	db.replicas = &replicaSet{replicas: []*replica{r}, byPool: map[gorm.ConnPool]*replica{conn: r}}
+	if err := db.connection.Use(db.replicaResolver(context.Background())); err != nil {
+		t.Fatal(err)
+	}
+	return db, primary, r, replicaMock
+}
+
+func TestReadsAreRoutedToReplicas(t *testing.T) {
+	db, primary, r, replica := newReplicatedMockDatabase(t)
+	ctx := context.Background()
+	rows := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"id"}).AddRow("user-1") }
+
+	replica.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(rows())
+	read, err := db.ReadConnWithContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var users []models.User
+	if err := read.Find(&users).Error; err != nil {
+		t.Fatalf("read from the replica: %v", err)
+	}
+
+	// Writes through a read connection, and reads through ConnWithContext, use the primary
+	primary.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := read.Model(&models.User{}).Where("id = ?", "user-1").Update("name", "Ada").Error; err != nil {
+		t.Fatalf("write through the read connection: %v", err)
+	}
+	primary.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(rows())
+	conn, err := db.ConnWithContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Find(&users).Error; err != nil {
+		t.Fatalf("read from the primary: %v", err)
+	}
+
+	// Without a healthy replica, reads fall back to the primary
+	r.healthy.Store(false)
+	primary.ExpectQuery(`SELECT \* FROM "users"`).WillReturnRows(rows())
+	if read, err = db.ReadConnWithContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := read.Find(&users).Error; err != nil {
+		t.Fatalf("read with the replica down: %v", err)
+	}
+
+	for name, mock := range map[string]sqlmock.Sqlmock{"primary": primary, "replica": replica} {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestReplicaSetSkipsUnhealthyReplicas(t *testing.T) {
+	up, down := &replica{}, &replica{}
+	up.healthy.Store(true)
+	upPool, downPool := gorm.ConnPool(&fakePool{}), gorm.ConnPool(&fakePool{})
+	set := &replicaSet{replicas: []*replica{up, down}, byPool: map[gorm.ConnPool]*replica{upPool: up, downPool: down}}
+
+	for range 4 {
+		if got := set.Resolve([]gorm.ConnPool{upPool, downPool}); got != upPool {
+			t.Fatal("a read was sent to the unhealthy replica")
+		}
+	}
+}
+
+// fakePool is a distinct gorm.ConnPool for the policy, never used to query
+type fakePool struct {
+	gorm.ConnPool
+}
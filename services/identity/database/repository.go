@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/gabehamasaki/momentum/shared/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrEmptyFieldMask é retornado quando Update é chamado sem campos a atualizar
+var ErrEmptyFieldMask = errors.New("máscara de campos vazia")
+
+// DefaultPageSize é o tamanho de página usado quando ListOptions.Limit não é informado
+const DefaultPageSize = 100
+
+// Repository implementa as consultas comuns a qualquer entidade; repositórios
+// específicos o embutem e adicionam apenas as consultas customizadas
+type Repository[T any] struct {
+	db *Database
+}
+
+// NewRepository cria um repositório genérico para a entidade T
+func NewRepository[T any](db *Database) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+// ListOptions define paginação, filtros e ordenação de uma listagem
+type ListOptions struct {
+	Limit  int
+	Offset int
+	// Filters compara colunas por igualdade (nil gera IS NULL)
+	Filters map[string]any
+	// OrderBy é a coluna de ordenação (padrão: created_at)
+	OrderBy string
+	Desc    bool
+	// Preload lista as associações a carregar
+	Preload []string
+	// Scopes são aplicados à consulta (ex.: model.TenantScope)
+	Scopes []func(*gorm.DB) *gorm.DB
+}
+
+// Conn retorna a conexão com contexto usada pelo repositório
+func (r *Repository[T]) Conn(ctx context.Context) (*gorm.DB, error) {
+	return r.db.ConnWithContext(ctx)
+}
+
+// GetByID busca uma entidade pela chave primária
+func (r *Repository[T]) GetByID(ctx context.Context, id any, preload ...string) (*T, error) {
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, association := range preload {
+		conn = conn.Preload(association)
+	}
+
+	var entity T
+	if err := conn.First(&entity, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// List retorna uma página de entidades e o total que satisfaz os filtros
+func (r *Repository[T]) List(ctx context.Context, opts ListOptions) ([]T, int64, error) {
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := filtered[T](conn, opts)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("falha ao contar registros: %w", err)
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	orderBy := opts.OrderBy
+	if orderBy == "" {
+		orderBy = "created_at"
+	}
+
+	query = query.
+		Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: orderBy}, Desc: opts.Desc}).
+		Limit(limit).
+		Offset(opts.Offset)
+	for _, association := range opts.Preload {
+		query = query.Preload(association)
+	}
+
+	var entities []T
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, 0, fmt.Errorf("falha ao listar registros: %w", err)
+	}
+	return entities, total, nil
+}
+
+// Stream percorre as entidades que satisfazem os filtros em lotes ordenados pela
+// chave primária, ignorando Limit, Offset e OrderBy. Como fn é chamada de forma
+// síncrona, um envio bloqueado (ex.: controle de fluxo do stream gRPC) adia a
+// leitura do próximo lote. O slice passado a fn é reutilizado entre lotes.
+func (r *Repository[T]) Stream(ctx context.Context, opts ListOptions, batchSize int, fn func(batch []T) error) error {
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return streamBatches(ctx, conn, opts, batchSize, fn)
+}
+
+// streamBatches implementa Stream sobre uma conexão ou transação
+func streamBatches[T any](ctx context.Context, conn *gorm.DB, opts ListOptions, batchSize int, fn func(batch []T) error) error {
+	if batchSize <= 0 {
+		batchSize = DefaultPageSize
+	}
+
+	query := filtered[T](conn, opts)
+	for _, association := range opts.Preload {
+		query = query.Preload(association)
+	}
+
+	var batch []T
+	return query.FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return fn(batch)
+	}).Error
+}
+
+// filtered aplica escopos e filtros de igualdade de ListOptions
+func filtered[T any](conn *gorm.DB, opts ListOptions) *gorm.DB {
+	query := conn.Model(new(T)).Scopes(opts.Scopes...)
+	for column, value := range opts.Filters {
+		// clause.Column garante que o nome da coluna seja escapado
+		query = query.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Value: value})
+	}
+	return query
+}
+
+// Create insere a entidade, preenchendo os campos gerados pelo banco
+func (r *Repository[T]) Create(ctx context.Context, entity *T) error {
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Create(entity).Error
+}
+
+// Update grava apenas os campos listados na máscara, inclusive valores zero. Em
+// modelos versionados a versão também é gravada, e uma cópia desatualizada da
+// entidade falha com model.ErrVersionConflict.
+func (r *Repository[T]) Update(ctx context.Context, entity *T, fields ...string) error {
+	if len(fields) == 0 {
+		return ErrEmptyFieldMask
+	}
+	if _, versioned := any(entity).(model.Versioned); versioned && !slices.Contains(fields, "version") {
+		fields = append(fields[:len(fields):len(fields)], "version")
+	}
+
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := conn.Model(entity).Select(fields).Updates(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete remove a entidade pela chave primária (soft delete quando o modelo suporta)
+func (r *Repository[T]) Delete(ctx context.Context, id any) error {
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := conn.Delete(new(T), "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"gorm.io/gorm"
+)
+
+func TestRepositoryUpdateConflictsOnStaleCopy(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	users := NewRepository[models.User](db)
+	update := regexp.QuoteMeta(`UPDATE "users" SET "version"=$1,"updated_at"=$2,"name"=$3 WHERE "users"."version" = $4 AND "users"."deleted_at" IS NULL AND "id" = $5`)
+	mock.ExpectExec(update).WithArgs(int64(3), sqlmock.AnyArg(), "Ada Lovelace", int64(2), "user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(int64(3), sqlmock.AnyArg(), "Countess", int64(2), "user-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	first, second := staleUsers()
+	first.Name, second.Name = "Ada Lovelace", "Countess"
+
+	if err := users.Update(context.Background(), first, "name"); err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if err := users.Update(context.Background(), second, "name"); !errors.Is(err, model.ErrVersionConflict) {
+		t.Fatalf("second update error = %v, want %v", err, model.ErrVersionConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRepositoryUpdateUnversionedModel(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	tenants := NewRepository[models.Tenant](db)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "tenants" SET "name"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs("Acme", sqlmock.AnyArg(), "acme").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := tenants.Update(context.Background(), &models.Tenant{ID: "acme", Name: "Acme"}, "name")
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Update error = %v, want the missing row reported as not found", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,385 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrEmailTaken é retornado ao gravar um usuário ativo com o email de outro
+// usuário ativo do mesmo tenant (índice idx_users_tenant_email)
+var ErrEmailTaken = errors.New("email is already used by another user")
+
+const (
+	uniqueViolationSQLState = "23505"
+	userEmailIndex          = "idx_users_tenant_email"
+)
+
+// emailTaken troca a violação do índice único de email por ErrEmailTaken
+func emailTaken(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationSQLState && pgErr.ConstraintName == userEmailIndex {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// UserRepository reúne as consultas de usuários usadas pelo UserService, que
+// pode ser testado com uma implementação em memória
+type UserRepository interface {
+	// Transaction executa fn com um repositório ligado a uma única transação
+	Transaction(ctx context.Context, fn func(users UserRepository) error) error
+	// ReadSnapshot executa fn com um repositório que só lê, ligado a uma transação
+	// REPEATABLE READ: todas as consultas de fn veem o mesmo estado do banco
+	ReadSnapshot(ctx context.Context, fn func(users UserRepository) error) error
+
+	FindAll(ctx context.Context) ([]models.User, error)
+	FindPage(ctx context.Context, page UserPage) ([]models.User, error)
+	Search(ctx context.Context, query string, limit, offset int) ([]models.User, error)
+	FindByID(ctx context.Context, id string) (models.User, error)
+	FindWithDeleted(ctx context.Context, id string) (models.User, error)
+	Restore(ctx context.Context, user *models.User) error
+	Create(ctx context.Context, user *models.User) error
+	CreateBatch(ctx context.Context, users []*models.User, batchSize int) error
+	FindEmails(ctx context.Context, emails []string) (map[string]bool, error)
+	UpdateColumns(ctx context.Context, user *models.User, columns []string) error
+	ReplacePermissions(ctx context.Context, user *models.User, permissions []*models.Permission) error
+	FindRole(ctx context.Context, id string) (models.Role, error)
+	InvalidateEmailVerifications(ctx context.Context, userID string) error
+	Stream(ctx context.Context, opts ListOptions, batchSize int, fn func(batch []models.User) error) error
+
+	// AddEvent grava o evento no outbox, normalmente dentro de Transaction
+	AddEvent(ctx context.Context, event events.Event) error
+	AddEvents(ctx context.Context, batch []events.Event) error
+}
+
+// gormUserRepository implementa UserRepository com GORM. Fora de Transaction usa
+// o pool compartilhado, que nunca é fechado pelas consultas.
+type gormUserRepository struct {
+	*Repository[models.User]
+	tx *gorm.DB
+}
+
+// NewUserRepository cria o repositório de usuários sobre o pool do banco
+func NewUserRepository(db *Database) UserRepository {
+	return &gormUserRepository{Repository: NewRepository[models.User](db)}
+}
+
+// conn retorna a transação em andamento ou uma conexão do pool
+func (r *gormUserRepository) conn(ctx context.Context) (*gorm.DB, error) {
+	if r.tx != nil {
+		return r.tx.WithContext(ctx), nil
+	}
+	return r.Conn(ctx)
+}
+
+// readConn é como conn, mas fora de uma transação lê de uma réplica
+func (r *gormUserRepository) readConn(ctx context.Context) (*gorm.DB, error) {
+	if r.tx != nil {
+		return r.tx.WithContext(ctx), nil
+	}
+	return r.db.ReadConnWithContext(ctx)
+}
+
+func (r *gormUserRepository) Transaction(ctx context.Context, fn func(users UserRepository) error) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormUserRepository{Repository: r.Repository, tx: tx})
+	})
+}
+
+func (r *gormUserRepository) ReadSnapshot(ctx context.Context, fn func(users UserRepository) error) error {
+	conn, err := r.readConn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormUserRepository{Repository: r.Repository, tx: tx})
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
+
+// FindAll retorna todos os usuários com a role carregada, lidos de uma réplica
+func (r *gormUserRepository) FindAll(ctx context.Context) ([]models.User, error) {
+	conn, err := r.readConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.User
+	if err := conn.Preload("Role").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UserPage seleciona uma página de usuários ordenados por (created_at, id)
+type UserPage struct {
+	// IncludeDeleted inclui os usuários desativados (soft delete)
+	IncludeDeleted bool
+
+	// AfterCreatedAt e AfterID são a chave do último usuário da página anterior;
+	// vazios, a página começa pelo primeiro usuário
+	AfterCreatedAt time.Time
+	AfterID        string
+
+	// AsOf fixa o conjunto de usuários nesse instante: os criados depois ficam de
+	// fora e os desativados depois continuam na lista
+	AsOf *time.Time
+
+	Limit int
+}
+
+// FindPage retorna uma página de usuários com a role, continuando depois da chave
+// da página anterior (keyset), o que usa o índice idx_users_created_at_id em vez
+// de percorrer as linhas puladas por um OFFSET. Lê de uma réplica, exceto com
+// AsOf: réplicas atrasadas em relação a AsOf omitiriam usuários.
+func (r *gormUserRepository) FindPage(ctx context.Context, page UserPage) ([]models.User, error) {
+	var conn *gorm.DB
+	var err error
+	if page.AsOf != nil {
+		conn, err = r.conn(ctx)
+	} else {
+		conn, err = r.readConn(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := conn.Preload("Role")
+	switch {
+	case page.AsOf != nil && page.IncludeDeleted:
+		query = query.Unscoped().Where("created_at <= ?", *page.AsOf)
+	case page.AsOf != nil:
+		query = query.Unscoped().Where("created_at <= ? AND (deleted_at IS NULL OR deleted_at > ?)", *page.AsOf, *page.AsOf)
+	case page.IncludeDeleted:
+		query = query.Unscoped()
+	}
+	if page.AfterID != "" {
+		query = query.Where("(created_at, id) > (?, ?)", page.AfterCreatedAt, page.AfterID)
+	}
+
+	var users []models.User
+	if err := query.Order("created_at, id").Limit(page.Limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// userSearchDocument é o documento da busca textual, igual à expressão do índice
+// idx_users_search para que ele seja usado
+const userSearchDocument = "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(email, ''))"
+
+// likeEscaper escapa os curingas de LIKE, para que a busca os trate como texto
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Search busca usuários ativos por palavras do nome ou do email (busca textual),
+// por trechos (ILIKE) e por semelhança (pg_trgm, tolerando erros de digitação),
+// ordenados pela relevância e lidos de uma réplica
+func (r *gormUserRepository) Search(ctx context.Context, query string, limit, offset int) ([]models.User, error) {
+	conn, err := r.readConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+	rank := clause.Expr{
+		SQL:                "ts_rank(" + userSearchDocument + ", plainto_tsquery('simple', ?)) + greatest(similarity(name, ?), similarity(email, ?)) DESC, created_at, id",
+		Vars:               []any{query, query, query},
+		WithoutParentheses: true,
+	}
+
+	var users []models.User
+	err = conn.Preload("Role").
+		Where(userSearchDocument+" @@ plainto_tsquery('simple', ?) OR name ILIKE ? OR email ILIKE ? OR name % ? OR email % ?",
+			query, pattern, pattern, query, query).
+		Clauses(clause.OrderBy{Expression: rank}).
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindByID busca um usuário com a role, as permissões da role e as permissões do
+// usuário. Fora de Transaction lê de uma réplica, que pode estar atrasada.
+func (r *gormUserRepository) FindByID(ctx context.Context, id string) (models.User, error) {
+	conn, err := r.readConn(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	if err := conn.Preload("Role").Preload("Role.Permissions").Preload("Permissions").First(&user, "id = ?", id).Error; err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// FindWithDeleted busca um usuário com a role mesmo que esteja desativado. Fora de
+// Transaction usa o primário, pois serve para restaurar o usuário em seguida.
+func (r *gormUserRepository) FindWithDeleted(ctx context.Context, id string) (models.User, error) {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	if err := conn.Unscoped().Preload("Role").First(&user, "id = ?", id).Error; err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// Restore desfaz o soft delete do usuário e remove a desativação agendada, que o
+// desativaria de novo na próxima execução do job. A versão também é gravada, então
+// uma restauração concorrente com outra alteração falha com model.ErrVersionConflict.
+func (r *gormUserRepository) Restore(ctx context.Context, user *models.User) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	user.DeletedAt = gorm.DeletedAt{}
+	user.ScheduleDeactivationAt = nil
+	return emailTaken(conn.Unscoped().Model(user).Select("deleted_at", "schedule_deactivation_at", "version").Updates(user).Error)
+}
+
+// Create insere o usuário, ou retorna ErrEmailTaken
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return emailTaken(conn.Create(user).Error)
+}
+
+// CreateBatch insere os usuários em lotes de batchSize, com as permissões de cada um
+func (r *gormUserRepository) CreateBatch(ctx context.Context, users []*models.User, batchSize int) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return emailTaken(conn.CreateInBatches(users, batchSize).Error)
+}
+
+// FindEmails retorna quais dos emails (em minúsculas) já pertencem a usuários ativos
+func (r *gormUserRepository) FindEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	taken := make(map[string]bool)
+	if len(emails) == 0 {
+		return taken, nil
+	}
+
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []string
+	if err := conn.Model(&models.User{}).Where("lower(email) IN ?", emails).Pluck("lower(email)", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, email := range found {
+		taken[email] = true
+	}
+	return taken, nil
+}
+
+// UpdateColumns grava apenas as colunas informadas do usuário, ou retorna
+// ErrEmailTaken se o novo email já for de outro usuário ativo do tenant
+func (r *gormUserRepository) UpdateColumns(ctx context.Context, user *models.User, columns []string) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return emailTaken(conn.Model(user).Select(columns).Updates(user).Error)
+}
+
+// ReplacePermissions substitui as permissões diretas do usuário
+func (r *gormUserRepository) ReplacePermissions(ctx context.Context, user *models.User, permissions []*models.Permission) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Model(user).Association("Permissions").Replace(permissions)
+}
+
+// FindRole busca uma role com suas permissões
+func (r *gormUserRepository) FindRole(ctx context.Context, id string) (models.Role, error) {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return models.Role{}, err
+	}
+
+	var role models.Role
+	if err := conn.Preload("Permissions").First(&role, "id = ?", id).Error; err != nil {
+		return models.Role{}, err
+	}
+	return role, nil
+}
+
+// InvalidateEmailVerifications marca como usados os tokens de verificação pendentes do usuário
+func (r *gormUserRepository) InvalidateEmailVerifications(ctx context.Context, userID string) error {
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Model(&models.EmailVerification{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *gormUserRepository) AddEvent(ctx context.Context, event events.Event) error {
+	row, err := models.NewOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Create(row).Error
+}
+
+// AddEvents grava vários eventos no outbox de uma vez
+func (r *gormUserRepository) AddEvents(ctx context.Context, batch []events.Event) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	rows := make([]*models.OutboxEvent, len(batch))
+	for i, event := range batch {
+		row, err := models.NewOutboxEvent(event)
+		if err != nil {
+			return err
+		}
+		rows[i] = row
+	}
+
+	conn, err := r.conn(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.CreateInBatches(rows, DefaultPageSize).Error
+}
+
+// Stream é como Repository.Stream, mas dentro de Transaction ou ReadSnapshot lê
+// pela transação
+func (r *gormUserRepository) Stream(ctx context.Context, opts ListOptions, batchSize int, fn func(batch []models.User) error) error {
+	if r.tx == nil {
+		return r.Repository.Stream(ctx, opts, batchSize, fn)
+	}
+	return streamBatches(ctx, r.tx.WithContext(ctx), opts, batchSize, fn)
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMockDatabase returns a Database whose connection runs on go-sqlmock with the
+// postgres dialect; statements run outside a transaction unless the code opens one
+func newMockDatabase(t *testing.T) (*Database, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Database{connection: db, config: DefaultDatabaseConfig()}, mock
+}
+
+// staleUsers returns two copies of a user loaded at the same version
+func staleUsers() (*models.User, *models.User) {
+	first := &models.User{BaseModel: model.BaseModel{ID: "user-1", Version: 2}, Name: "Ada"}
+	second := *first
+	return first, &second
+}
+
+// TestUpdateColumnsConflictsOnStaleCopy is two concurrent UpdateUser calls: the
+// second copy was loaded before the first update committed
+func TestUpdateColumnsConflictsOnStaleCopy(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	users := NewUserRepository(db)
+	update := regexp.QuoteMeta(`UPDATE "users" SET "version"=$1,"updated_at"=$2,"name"=$3 WHERE "users"."version" = $4 AND "users"."deleted_at" IS NULL AND "id" = $5`)
+	mock.ExpectExec(update).WithArgs(int64(3), sqlmock.AnyArg(), "Ada Lovelace", int64(2), "user-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(int64(3), sqlmock.AnyArg(), "Countess", int64(2), "user-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	first, second := staleUsers()
+	first.Name, second.Name = "Ada Lovelace", "Countess"
+
+	if err := users.UpdateColumns(context.Background(), first, []string{"name", "version"}); err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if err := users.UpdateColumns(context.Background(), second, []string{"name", "version"}); !errors.Is(err, model.ErrVersionConflict) {
+		t.Fatalf("second update error = %v, want %v", err, model.ErrVersionConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreConflictsOnStaleCopy(t *testing.T) {
+	db, mock := newMockDatabase(t)
+	users := NewUserRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "version"=$1,"updated_at"=$2,"deleted_at"=$3,"schedule_deactivation_at"=$4 WHERE "users"."version" = $5 AND "id" = $6`)).
+		WithArgs(int64(3), sqlmock.AnyArg(), nil, nil, int64(2), "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Updated or deleted again since it was loaded for the restore
+	_, stale := staleUsers()
+	if err := users.Restore(context.Background(), stale); !errors.Is(err, model.ErrVersionConflict) {
+		t.Fatalf("Restore error = %v, want %v", err, model.ErrVersionConflict)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateMapsEmailUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantTaken bool
+	}{
+		{"email index", &pgconn.PgError{Code: "23505", ConstraintName: "idx_users_tenant_email"}, true},
+		{"other unique index", &pgconn.PgError{Code: "23505", ConstraintName: "users_pkey"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDatabase(t)
+			mock.ExpectExec(`INSERT INTO "users"`).WillReturnError(tt.err)
+
+			user := &models.User{BaseModel: model.BaseModel{ID: "user-1"}, Email: "ada@example.com"}
+			err := NewUserRepository(db).Create(context.Background(), user)
+			if taken := errors.Is(err, ErrEmailTaken); taken != tt.wantTaken || err == nil {
+				t.Fatalf("Create error = %v, want ErrEmailTaken: %v", err, tt.wantTaken)
+			}
+		})
+	}
+}
@@ -2,51 +2,139 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/gabehamasaki/momentum/services/identity/artifacts"
 	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/operations"
+	"github.com/gabehamasaki/momentum/services/identity/outbox"
 	"github.com/gabehamasaki/momentum/services/identity/server"
 	"github.com/gabehamasaki/momentum/services/identity/services"
 	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/cache"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
 	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/joho/godotenv/autoload"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-const (
-	serviceName    = "identity-service"
-	serviceVersion = "v1.0.0"
-)
+const serviceName = "identity-service"
 
 func main() {
-	// 1. Initialize logger first
-	if err := initializeLogger(); err != nil {
+	// Run CLI subcommands (e.g. "identity apply -f roles.yaml") instead of the server
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
+	// 1. Load configuration and initialize logger; configuration errors are
+	// reported with the startup checks below
+	cfg, cfgErr := loadConfig()
+	errorReporter := cfg.ErrorReporting.Reporter(serviceName, cfg.Server.Environment, shared.Version)
+	loggerConfig := cfg.Logger.LoggerConfig(serviceName, cfg.Server.Environment)
+	if errorReporter != nil {
+		loggerConfig.ErrorReporter = errorReporter
+	}
+	if err := shared.InitLogger(loggerConfig); err != nil {
 		fmt.Printf("Failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
 
 	logger := shared.GetLogger()
-
-	// Log startup
-	port := shared.GetEnv("IDENTITY_GRPC_PORT", "50051")
-	shared.LogStartup(serviceName, serviceVersion, port)
+	shared.LogStartup(serviceName, shared.Version, cfg.Server.GRPCPort)
 
 	// 2. Setup graceful shutdown
 	ctx, cancel := setupGracefulShutdown()
 	defer cancel()
 
-	// 3. Initialize database
-	db, err := initializeDatabase(ctx, logger)
-	if err != nil {
-		logger.Fatal("Failed to initialize database", zap.Error(err))
+	// 3. Validate configuration and initialize database
+	var db *database.Database
+	var sensitiveFields *shared.SensitiveFieldRegistry
+	var accessLogger *zap.Logger
+	var slos []shared.SLO
+	var sloWindow time.Duration
+	var alerts *shared.AlertEngine
+	var artifactBackend *artifacts.FileBackend
+	var appCache cache.Cache
+	var publisher events.Publisher
+	metrics := shared.NewMetrics(shared.DefaultLatencyBuckets)
+	report := shared.RunStartupChecks(ctx, serviceName, shared.Version,
+		shared.StartupCheck{Name: "env", Run: func(ctx context.Context) error { return cfgErr }},
+		checkDSN(cfg),
+		shared.StartupCheck{Name: "sensitive_fields", Run: func(ctx context.Context) (err error) {
+			sensitiveFields, err = shared.LoadSensitiveFields(cfg.SensitiveFieldsFile)
+			if err == nil && errorReporter != nil {
+				errorReporter.UseSensitiveFields(sensitiveFields)
+			}
+			return err
+		}},
+		shared.StartupCheck{Name: "access_log", Run: func(ctx context.Context) (err error) {
+			if cfg.AccessLogPath != "" {
+				accessLogger, err = shared.NewAccessLogger(&shared.AccessLogConfig{Path: cfg.AccessLogPath, ServerName: serviceName, Rotation: cfg.Logger.Rotation})
+			}
+			return err
+		}},
+		shared.StartupCheck{Name: "slo", Run: func(ctx context.Context) (err error) {
+			if cfg.SLOFile != "" {
+				slos, sloWindow, err = shared.LoadSLOs(cfg.SLOFile)
+			}
+			return err
+		}},
+		shared.StartupCheck{Name: "alert_rules", Run: func(ctx context.Context) (err error) {
+			if cfg.AlertRulesFile != "" {
+				alerts, err = shared.NewAlertEngine(&shared.AlertConfig{
+					Logger:     logger,
+					Metrics:    metrics,
+					RulesFile:  cfg.AlertRulesFile,
+					ServerName: serviceName,
+				})
+			}
+			return err
+		}},
+		shared.StartupCheck{Name: "artifacts", Run: func(ctx context.Context) (err error) {
+			artifactBackend, err = artifacts.NewFileBackend(cfg.ArtifactDir)
+			return err
+		}},
+		shared.StartupCheck{Name: "cache", Run: func(ctx context.Context) error {
+			appCache = cfg.Cache.New()
+			if redis, ok := appCache.(*cache.Redis); ok {
+				return redis.Ping(ctx)
+			}
+			return nil
+		}},
+		shared.StartupCheck{Name: "events", Run: func(ctx context.Context) (err error) {
+			publisher, err = cfg.Events.Publisher(logger, serviceName)
+			return err
+		}},
+		shared.CheckPortFree("grpc", cfg.Server.GRPCPort),
+		shared.CheckPortFree("http", cfg.Server.HTTPPort),
+		shared.CheckPortFree("metrics", cfg.Server.MetricsPort),
+		shared.StartupCheck{Name: "database.connect", Run: func(ctx context.Context) (err error) {
+			db, err = initializeDatabase(ctx, logger, cfg)
+			return err
+		}},
+		checkMigrations(&db, cfg.Database.AutoMigrate),
+	)
+	report.Log(logger)
+	if !report.OK {
+		report.WriteError(os.Stderr)
+		shared.Sync()
+		os.Exit(1)
 	}
 	defer func() {
 		if closeErr := db.Close(); closeErr != nil {
@@ -54,8 +142,43 @@ func main() {
 		}
 	}()
 
-	// 4. Setup and start gRPC server
-	grpcServer, listener := setupGRPCServer(logger, db, port)
+	if cfg.Database.AutoMigrate {
+		if err := migrateDatabase(ctx, logger, db); err != nil {
+			logger.Fatal("Failed to migrate database", zap.Error(err))
+		}
+	}
+
+	// 4. Setup and start gRPC, Connect and metrics servers
+	healthServer := health.NewServer()
+	shutdown := shared.NewShutdownManager(&shared.ShutdownConfig{
+		Logger:       logger,
+		DrainTimeout: cfg.Server.ShutdownTimeout,
+		Health:       healthServer,
+	})
+	metricsConfig := setupMetrics(metrics, cfg)
+	sloTracker := shared.NewSLOTracker(metrics, slos, sloWindow)
+	shutdown.Go("slo", func() { sloTracker.Run(ctx) })
+	if alerts != nil {
+		shutdown.Go("alerts", func() { alerts.Run(ctx) })
+	}
+	deprecations := shared.NewDeprecationTracker(logger, metrics, serviceName, cfg.Server.DeprecatedMethods)
+	auditService := services.NewAuditService(db, logger)
+	artifactStore := setupArtifacts(logger, db, artifactBackend, cfg)
+	// Failover counters of the database are exported on /metrics
+	metrics.Register(db)
+	relay := outbox.NewRelay(db, publisher, logger, metrics, cfg.OutboxMaxAttempts)
+	shutdown.Go("outbox", func() { relay.Run(ctx, cfg.OutboxRelayInterval) })
+	identityServer := setupIdentityServer(ctx, logger, db, cfg, sensitiveFields, sloTracker, auditService, artifactStore, appCache, shutdown)
+	validation := identityServer.Validation()
+	interceptors := setupInterceptors(logger, cfg, metricsConfig, sensitiveFields, accessLogger, deprecations, auditService, validation, identityServer.ShadowHandlers(), identityServer.CheckToken, shutdown)
+	streamInterceptors := setupStreamInterceptors(logger, cfg, metricsConfig, deprecations, auditService, validation, identityServer.CheckToken, shutdown)
+	grpcServer, listener := setupGRPCServer(logger, identityServer, healthServer, interceptors, streamInterceptors, metrics, cfg)
+	connectServer := setupConnectServer(logger, identityServer, interceptors, artifactStore, cfg.Server.HTTPPort)
+	setupNATSHandler(ctx, logger, identityServer, interceptors, cfg, shutdown)
+	metricsServer := shared.NewMetricsServer(cfg.Server.MetricsPort, metrics)
+	shutdown.AddGRPCServer(grpcServer)
+	shutdown.AddHTTPServer("connect", connectServer)
+	shutdown.AddHTTPServer("metrics", metricsServer)
 
 	// Start server in goroutine
 	go func() {
@@ -69,36 +192,45 @@ func main() {
 		}
 	}()
 
+	go func() {
+		logger.Info("Starting Connect HTTP server",
+			zap.String("address", connectServer.Addr),
+			zap.String("service", serviceName),
+		)
+
+		if err := connectServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Failed to serve Connect HTTP", zap.Error(err))
+		}
+	}()
+
+	go func() {
+		logger.Info("Starting metrics server",
+			zap.String("address", metricsServer.Addr),
+			zap.String("service", serviceName),
+		)
+
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("Failed to serve metrics", zap.Error(err))
+		}
+	}()
+
 	// 5. Wait for shutdown signal
 	<-ctx.Done()
 
 	// 6. Graceful shutdown
 	shared.LogShutdown(serviceName, "received shutdown signal")
-
-	logger.Info("Shutting down gRPC server...")
-	grpcServer.GracefulStop()
+	shutdown.Shutdown()
 
 	logger.Info("Server shutdown completed")
+	flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer flushCancel()
+	errorReporter.Close(flushCtx)
+	if accessLogger != nil {
+		_ = accessLogger.Sync()
+	}
 	shared.Sync() // Flush logs
 }
 
-// initializeLogger sets up the zap logger with proper configuration
-func initializeLogger() error {
-	loggerConfig := &shared.LoggerConfig{
-		ServerName:       serviceName,
-		Environment:      shared.GetEnv("ENVIRONMENT", "development"),
-		LogLevel:         shared.GetEnv("LOG_LEVEL", "info"),
-		EnableConsole:    true,
-		EnableFile:       shared.GetEnv("ENVIRONMENT", "development") == "production",
-		LogFilePath:      "/var/log/identity-service.log",
-		EnableJSON:       shared.GetEnv("ENVIRONMENT", "development") == "production",
-		EnableCaller:     shared.GetEnv("ENVIRONMENT", "development") != "production",
-		EnableStacktrace: true,
-	}
-
-	return shared.InitLogger(loggerConfig)
-}
-
 // setupGracefulShutdown configures graceful shutdown handling
 func setupGracefulShutdown() (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -116,9 +248,8 @@ func setupGracefulShutdown() (context.Context, context.CancelFunc) {
 }
 
 // initializeDatabase sets up database connection with retries and health checks
-func initializeDatabase(ctx context.Context, logger *zap.Logger) (*database.Database, error) {
-	// Get DSN from environment
-	dsn := os.Getenv("IDENTITY_DSN")
+func initializeDatabase(ctx context.Context, logger *zap.Logger, cfg *serviceConfig) (*database.Database, error) {
+	dsn := cfg.Database.DSN.Reveal()
 	if dsn == "" {
 		return nil, fmt.Errorf("IDENTITY_DSN environment variable is not set")
 	}
@@ -127,6 +258,22 @@ func initializeDatabase(ctx context.Context, logger *zap.Logger) (*database.Data
 
 	// Create database config
 	config := database.DefaultDatabaseConfig()
+	config.Plugins = append(config.Plugins, shared.StatementCounter{}, model.TenantIsolation{Tenant: shared.TenantFromContext})
+	config.Resolver = setupEndpointResolver(dsn, cfg.Database.Candidates)
+	config.ReplicaDSNs = cfg.Database.Replicas
+	config.OnFailover = func(stats database.FailoverStats, err error) {
+		if err != nil {
+			logger.Error("Database failover failed",
+				zap.Int64("failed_failovers", stats.FailedFailovers),
+				zap.Error(err),
+			)
+			return
+		}
+		logger.Warn("Database primary changed, reconnected to new writable endpoint",
+			zap.Int64("failovers", stats.Failovers),
+			zap.Int64("read_only_errors", stats.ReadOnlyErrors),
+		)
+	}
 
 	db := database.NewDBWithConfig(dsn, config)
 
@@ -188,57 +335,294 @@ func initializeDatabase(ctx context.Context, logger *zap.Logger) (*database.Data
 		time.Sleep(retryDelay)
 	}
 
-	// Run migrations
+	// Watch for the primary being demoted to a replica
+	go db.WatchTopology(ctx, cfg.Database.TopologyCheckInterval)
+	// Take replicas that stop responding out of the read rotation
+	go db.WatchReplicas(ctx, cfg.Database.TopologyCheckInterval)
+
+	logger.Info("Database initialization completed successfully")
+	return db, nil
+}
+
+// migrateDatabase runs migrations and seeds the initial data
+func migrateDatabase(ctx context.Context, logger *zap.Logger, db *database.Database) error {
+	migrateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
 	logger.Info("Running database migrations")
-	if err := db.MigrateWithContext(dbCtx); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	if err := db.MigrateWithContext(migrateCtx); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	// Seed database
 	logger.Info("Seeding database with initial data")
-	if err := db.SeederWithContext(dbCtx); err != nil {
-		return nil, fmt.Errorf("failed to seed database: %w", err)
+	if err := db.SeederWithContext(migrateCtx); err != nil {
+		return fmt.Errorf("failed to seed database: %w", err)
 	}
 
-	logger.Info("Database initialization completed successfully")
-	return db, nil
+	return nil
 }
 
-// setupGRPCServer creates and configures the gRPC server
-func setupGRPCServer(logger *zap.Logger, db *database.Database, port string) (*grpc.Server, net.Listener) {
-	// Configure interceptor
+// checkDSN validates that IDENTITY_DSN can be parsed before trying to connect
+func checkDSN(cfg *serviceConfig) shared.StartupCheck {
+	return shared.StartupCheck{Name: "database.dsn", Run: func(ctx context.Context) error {
+		dsn := cfg.Database.DSN.Reveal()
+		if dsn == "" {
+			return errors.New("IDENTITY_DSN is empty")
+		}
+		if _, err := pgconn.ParseConfig(dsn); err != nil {
+			return fmt.Errorf("IDENTITY_DSN is not parseable: %w", err)
+		}
+		return nil
+	}}
+}
+
+// checkMigrations fails when SQL migrations are pending and auto-migration is disabled
+func checkMigrations(db **database.Database, autoMigrate bool) shared.StartupCheck {
+	return shared.StartupCheck{Name: "database.migrations", Run: func(ctx context.Context) error {
+		if *db == nil {
+			return errors.New("skipped: database unavailable")
+		}
+
+		pending, err := (*db).PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 && !autoMigrate {
+			return fmt.Errorf("pending migrations and IDENTITY_AUTO_MIGRATE is disabled: %s", strings.Join(pending, "; "))
+		}
+		return nil
+	}}
+}
+
+// setupEndpointResolver returns the resolver used to find the writable primary after a failover.
+// IDENTITY_DSN_CANDIDATES lists the DSNs of the other regions, separated by ";"
+func setupEndpointResolver(dsn string, candidates []string) database.EndpointResolver {
+	if len(candidates) == 0 {
+		return database.StaticResolver(dsn)
+	}
+	return database.NewCandidateResolver(append([]string{dsn}, candidates...)...)
+}
+
+// setupInterceptors builds the unary interceptor chain shared by the gRPC and Connect servers
+func setupInterceptors(logger *zap.Logger, cfg *serviceConfig, metricsConfig *shared.MetricsConfig, sensitiveFields *shared.SensitiveFieldRegistry, accessLogger *zap.Logger, deprecations *shared.DeprecationTracker, auditService *services.AuditService, validation *shared.ValidationConfig, shadows map[string]shared.ShadowHandler, checkToken func(context.Context, *shared.Claims) error, shutdown *shared.ShutdownManager) []grpc.UnaryServerInterceptor {
 	interceptorConfig := &shared.InterceptorConfig{
 		Logger:               logger,
 		LogLevel:             zapcore.InfoLevel,
-		LogRequests:          shared.GetEnv("LOG_GRPC_REQUESTS", "true") == "true",
-		LogResponses:         shared.GetEnv("LOG_GRPC_RESPONSES", "false") == "true",
-		LogMetadata:          shared.GetEnv("LOG_GRPC_METADATA", "false") == "true",
-		SensitiveFields:      []string{"password", "token", "secret", "authorization", "cookie"},
+		LogRequests:          cfg.Logger.LogRequests,
+		LogResponses:         cfg.Logger.LogResponses,
+		LogMetadata:          cfg.Logger.LogMetadata,
+		SensitiveRegistry:    sensitiveFields,
 		SlowRequestThreshold: 3 * time.Second,
 		ServerName:           serviceName,
+		AccessLogger:         accessLogger,
 	}
 
-	// Create gRPC server with interceptors
-	grpcServer := grpc.NewServer(
-		grpc.UnaryInterceptor(shared.LoggingUnaryInterceptor(interceptorConfig)),
+	// Flags RPCs that run more SQL statements than SQL_STATEMENT_BUDGET (N+1 detector for staging)
+	budgetConfig := &shared.StatementBudgetConfig{
+		Logger:     logger,
+		Budget:     cfg.Database.StatementBudget,
+		ServerName: serviceName,
+	}
+
+	// Accounts statements, bytes and downstream calls per RPC, before logging so the
+	// completion entries carry them
+	costConfig := &shared.CostConfig{
+		Logger:     logger,
+		Metrics:    metricsConfig.Metrics,
+		ServerName: serviceName,
+		Expensive:  cfg.ExpensiveCall,
+		TraceRate:  cfg.ExpensiveCallTraceRate,
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		shutdown.UnaryInterceptor(),
+		shared.VersionUnaryInterceptor(shared.Version),
+		shared.ErrorReasonUnaryInterceptor(),
+		shared.MetricsUnaryInterceptor(metricsConfig),
+		shared.CostUnaryInterceptor(costConfig),
+		shared.LoggingUnaryInterceptor(interceptorConfig),
+		shared.DeprecationUnaryInterceptor(deprecations),
+	}
+
+	if cfg.Server.MinClientVersion != "" {
+		interceptors = append(interceptors, shared.MinClientVersionUnaryInterceptor(&shared.ClientVersionConfig{
+			Logger:     logger,
+			MinVersion: cfg.Server.MinClientVersion,
+		}))
+	}
+
+	// Bearer tokens and permissions are enforced once JWT_SECRET is configured, which
+	// production requires
+	if authConfig, authzConfig := setupAuth(logger, cfg, checkToken); authConfig != nil {
+		interceptors = append(interceptors,
+			shared.AuthUnaryInterceptor(authConfig),
+			shared.PermissionUnaryInterceptor(authzConfig),
+		)
+	} else {
+		logger.Warn("JWT_SECRET is not set, requests are not authenticated")
+	}
+
+	// Requests are validated after authentication, and mutations audited once the actor is known
+	interceptors = append(interceptors, shared.ValidationUnaryInterceptor(validation))
+
+	// Only validated requests are shadowed, with the identity of the caller in the context
+	if cfg.ShadowSampleRate > 0 && len(shadows) > 0 {
+		interceptors = append(interceptors, shared.ShadowUnaryInterceptor(&shared.ShadowConfig{
+			Logger:            logger,
+			Metrics:           metricsConfig.Metrics,
+			ServerName:        serviceName,
+			Handlers:          shadows,
+			SampleRate:        cfg.ShadowSampleRate,
+			SensitiveRegistry: sensitiveFields,
+		}))
+	}
+
+	return append(interceptors,
+		server.AuditUnaryInterceptor(auditService, logger),
+		shared.StatementBudgetUnaryInterceptor(budgetConfig),
+	)
+}
+
+// setupStreamInterceptors builds the streaming interceptor chain of the gRPC server
+func setupStreamInterceptors(logger *zap.Logger, cfg *serviceConfig, metricsConfig *shared.MetricsConfig, deprecations *shared.DeprecationTracker, auditService *services.AuditService, validation *shared.ValidationConfig, checkToken func(context.Context, *shared.Claims) error, shutdown *shared.ShutdownManager) []grpc.StreamServerInterceptor {
+	interceptors := []grpc.StreamServerInterceptor{
+		shutdown.StreamInterceptor(),
+		shared.VersionStreamInterceptor(shared.Version),
+		shared.ErrorReasonStreamInterceptor(),
+		shared.MetricsStreamInterceptor(metricsConfig),
+		shared.DeprecationStreamInterceptor(deprecations),
+	}
+	if cfg.Server.MinClientVersion != "" {
+		interceptors = append(interceptors, shared.MinClientVersionStreamInterceptor(&shared.ClientVersionConfig{
+			Logger:     logger,
+			MinVersion: cfg.Server.MinClientVersion,
+		}))
+	}
+
+	if authConfig, authzConfig := setupAuth(logger, cfg, checkToken); authConfig != nil {
+		interceptors = append(interceptors,
+			shared.AuthStreamInterceptor(authConfig),
+			shared.PermissionStreamInterceptor(authzConfig),
+		)
+	}
+	return append(interceptors,
+		shared.ValidationStreamInterceptor(validation),
+		server.AuditStreamInterceptor(auditService, logger),
 	)
+}
+
+// setupMetrics records RED metrics for every RPC, exposed on IDENTITY_METRICS_PORT and
+// labeled by tenant for at most METRICS_TENANT_LIMIT tenants besides METRICS_TENANTS
+func setupMetrics(metrics *shared.Metrics, cfg *serviceConfig) *shared.MetricsConfig {
+	return &shared.MetricsConfig{
+		Metrics:    metrics,
+		ServerName: serviceName,
+		Tenants:    shared.NewTenantLabeler(cfg.MetricsTenantLimit, cfg.MetricsTenants...),
+	}
+}
+
+// setupAuth returns the token and permission settings, or nil when JWT_SECRET is unset.
+// checkToken revokes the tokens of deleted users.
+func setupAuth(logger *zap.Logger, cfg *serviceConfig, checkToken func(context.Context, *shared.Claims) error) (*shared.AuthConfig, *shared.AuthorizationConfig) {
+	if !cfg.Auth.Enabled() {
+		return nil, nil
+	}
+
+	authConfig := cfg.Auth.AuthConfig()
+	authConfig.Logger = logger
+	authConfig.PublicMethods = server.PublicMethods
+	authConfig.CheckToken = checkToken
+	authzConfig := &shared.AuthorizationConfig{
+		Logger:            logger,
+		MethodPermissions: server.MethodPermissions,
+		PublicMethods:     server.PublicMethods,
+	}
+	return authConfig, authzConfig
+}
 
-	// Initialize services
+// setupIdentityServer initializes the services backing the identity API
+func setupIdentityServer(ctx context.Context, logger *zap.Logger, db *database.Database, cfg *serviceConfig, sensitiveFields *shared.SensitiveFieldRegistry, sloTracker *shared.SLOTracker, auditService *services.AuditService, artifactStore *artifacts.Store, appCache cache.Cache, shutdown *shared.ShutdownManager) *server.IdentityServer {
 	logger.Info("Initializing services")
-	userService := services.NewUserService(db, logger)
-	identityServer := server.NewIdentityServer(userService, logger)
+	userCache := services.NewUserCache(appCache, cfg.UserCacheTTL, logger)
+	userService := services.NewUserService(database.NewUserRepository(db), userCache, logger)
+	configService := services.NewConfigService(db, logger, cfg.ConfigSigningKey.Reveal(), userCache)
+
+	operationManager := operations.NewManager(ctx, db, logger)
+	shutdown.Go("operations", func() {
+		<-ctx.Done()
+		operationManager.Wait()
+	})
+	reassignmentService := services.NewReassignmentService(db, logger, operationManager, userCache)
+	deprovisioningService := services.NewDeprovisioningService(db, logger, operationManager, userCache)
+	shutdown.Go("deprovisioning", func() { deprovisioningService.Run(ctx, cfg.DeprovisioningInterval) })
+	offboardingService := services.NewTenantOffboardingService(db, logger, operationManager, artifactStore, userCache)
+	shutdown.Go("tenant_offboarding", func() { offboardingService.Run(ctx, cfg.DeprovisioningInterval) })
+	tenantService := services.NewTenantService(db, logger)
+
+	// Tokens are posted to PASSWORD_RESET_WEBHOOK and EMAIL_VERIFICATION_WEBHOOK, or only logged when unset
+	var resetNotifier services.ResetNotifier = services.LogNotifier{Logger: logger}
+	if cfg.PasswordResetWebhook != "" {
+		resetNotifier = services.WebhookNotifier{URL: cfg.PasswordResetWebhook}
+	}
+	passwordResetService := services.NewPasswordResetService(db, logger, resetNotifier, cfg.PasswordResetTTL, cfg.PasswordResetLimit, userCache)
 
-	// Register services
+	var verificationNotifier services.VerificationNotifier = services.LogNotifier{Logger: logger}
+	if cfg.EmailVerificationWebhook != "" {
+		verificationNotifier = services.WebhookNotifier{URL: cfg.EmailVerificationWebhook}
+	}
+	verificationService := services.NewEmailVerificationService(db, logger, verificationNotifier, cfg.EmailVerificationTTL, cfg.EmailVerificationLimit, userCache)
+
+	// Operations interrupted by a restart continue in the background
+	if err := operationManager.ResumeAll(ctx); err != nil {
+		logger.Error("Failed to resume operations", zap.Error(err))
+	}
+
+	return server.NewIdentityServer(userService, configService, reassignmentService, deprovisioningService, offboardingService, tenantService, passwordResetService, verificationService, operationManager, auditService, artifactStore, sensitiveFields, sloTracker, shared.GetBuildInfo(serviceName), logger)
+}
+
+// setupArtifacts creates the export store. Without ARTIFACT_SIGNING_KEY (development
+// only) a random key is used, so download URLs stop working after a restart.
+func setupArtifacts(logger *zap.Logger, db *database.Database, backend *artifacts.FileBackend, cfg *serviceConfig) *artifacts.Store {
+	signingKey := []byte(cfg.ArtifactSigningKey.Reveal())
+	if len(signingKey) == 0 {
+		logger.Warn("ARTIFACT_SIGNING_KEY is not set, using a temporary key for download URLs")
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			logger.Fatal("Failed to generate artifact signing key", zap.Error(err))
+		}
+	}
+	return artifacts.NewStore(db, backend, logger, signingKey, cfg.ArtifactBaseURL, cfg.ArtifactURLTTL)
+}
+
+// setupGRPCServer creates and configures the gRPC server
+func setupGRPCServer(logger *zap.Logger, identityServer *server.IdentityServer, healthServer *health.Server, interceptors []grpc.UnaryServerInterceptor, streamInterceptors []grpc.StreamServerInterceptor, metrics *shared.Metrics, cfg *serviceConfig) (*grpc.Server, net.Listener) {
+	// Bytes and message counts are only visible to a stats handler, after serialization
+	trafficHandler := shared.NewTrafficStatsHandler(&shared.TrafficConfig{
+		Logger:            logger,
+		Metrics:           metrics,
+		LargePayloadBytes: cfg.LargePayloadBytes,
+		ServerName:        serviceName,
+	})
+
+	// Create gRPC server with interceptors
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+		grpc.StatsHandler(trafficHandler),
+	)
+
+	// Register services; health reports NOT_SERVING once shutdown starts
 	proto.RegisterIdentityServiceServer(grpcServer, identityServer)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
 
 	// Enable reflection in development
-	if shared.GetEnv("ENVIRONMENT", "development") == "development" {
+	if cfg.Server.Environment == "development" {
 		logger.Info("Enabling gRPC reflection for development")
 		reflection.Register(grpcServer)
 	}
 
 	// Create listener
-	address := fmt.Sprintf(":%s", port)
+	address := fmt.Sprintf(":%s", cfg.Server.GRPCPort)
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
 		logger.Fatal("Failed to create listener",
@@ -249,8 +633,52 @@ func setupGRPCServer(logger *zap.Logger, db *database.Database, port string) (*g
 
 	logger.Info("gRPC server configured",
 		zap.String("address", listener.Addr().String()),
-		zap.Bool("reflection_enabled", shared.GetEnv("ENVIRONMENT", "development") == "development"),
+		zap.Bool("reflection_enabled", cfg.Server.Environment == "development"),
 	)
 
 	return grpcServer, listener
 }
+
+// setupConnectServer exposes the identity API over the Connect protocol for HTTP/1.1 and browser clients
+func setupConnectServer(logger *zap.Logger, identityServer *server.IdentityServer, interceptors []grpc.UnaryServerInterceptor, artifactStore *artifacts.Store, port string) *http.Server {
+	handler := shared.NewConnectHandler(&proto.IdentityService_ServiceDesc, identityServer, interceptors...)
+	connectServer := shared.NewConnectServer(port, handler)
+
+	// Signed artifact downloads share the HTTP port with the Connect API
+	mux := http.NewServeMux()
+	mux.Handle("/", connectServer.Handler)
+	mux.Handle("GET "+artifacts.DownloadPath+"{id}", artifactStore.Handler())
+	connectServer.Handler = mux
+
+	logger.Info("Connect HTTP server configured",
+		zap.String("address", connectServer.Addr),
+		zap.String("path", handler.Path()),
+	)
+
+	return connectServer
+}
+
+// setupNATSHandler serves NATS_RPC_METHODS over NATS request-reply, on a connection of
+// its own so slow calls do not hold up the outbox relay
+func setupNATSHandler(ctx context.Context, logger *zap.Logger, identityServer *server.IdentityServer, interceptors []grpc.UnaryServerInterceptor, cfg *serviceConfig, shutdown *shared.ShutdownManager) {
+	if len(cfg.NATSRPCMethods) == 0 {
+		return
+	}
+
+	conn, err := events.NewNATS(cfg.Events.NATSURL.Reveal(), serviceName+"-rpc")
+	if err != nil {
+		logger.Fatal("Failed to configure NATS RPC", zap.Error(err))
+	}
+	handler, err := shared.NewNATSHandler(conn, &proto.IdentityService_ServiceDesc, identityServer, shared.NATSConfig{
+		Logger:  logger,
+		Methods: cfg.NATSRPCMethods,
+	}, interceptors...)
+	if err != nil {
+		logger.Fatal("Failed to configure NATS RPC", zap.Error(err))
+	}
+
+	for _, method := range cfg.NATSRPCMethods {
+		logger.Info("Serving method over NATS", zap.String("method", method), zap.String("subject", handler.Subject(method)))
+	}
+	shutdown.Go("nats_rpc", func() { handler.Run(ctx) })
+}
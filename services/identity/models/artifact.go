@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Artifact is an exported file, such as a data export or an audit archive. The
+// content is stored once per SHA-256 digest; several artifacts may share it.
+type Artifact struct {
+	ID          string `gorm:"type:uuid;primarykey"`
+	TenantID    string `gorm:"index;not null;default:''"`
+	Kind        string `gorm:"index"`
+	Name        string
+	ContentType string
+	Digest      string `gorm:"index"`
+	Size        int64
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+func (a *Artifact) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.New().String()
+	return
+}
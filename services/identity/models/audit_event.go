@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditEvent records who called a mutating RPC, on which target and what changed.
+// Before and After hold JSON snapshots of the changed fields, when the RPC knows them.
+type AuditEvent struct {
+	ID         string `gorm:"type:uuid;primarykey"`
+	ActorID    string `gorm:"index"`
+	TenantID   string
+	Action     string `gorm:"index"`
+	TargetType string
+	TargetID   string `gorm:"index"`
+	Before     string
+	After      string
+	Code       string
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+func (e *AuditEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.New().String()
+	return
+}
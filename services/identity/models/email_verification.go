@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailVerification is a single-use token confirming the user owns their email
+// address; only the SHA-256 of the token is stored
+type EmailVerification struct {
+	ID        string `gorm:"type:uuid;primarykey"`
+	UserID    string `gorm:"type:uuid;index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (v *EmailVerification) BeforeCreate(tx *gorm.DB) (err error) {
+	v.ID = uuid.New().String()
+	return
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	OperationRunning   = "running"
+	OperationSucceeded = "succeeded"
+	OperationFailed    = "failed"
+	OperationCancelled = "cancelled"
+)
+
+// Operation is a long-running task; it belongs to the tenant of the caller that started it
+type Operation struct {
+	ID              string `gorm:"type:uuid;primarykey"`
+	TenantID        string `gorm:"index;not null;default:''"`
+	Kind            string `gorm:"index"`
+	Status          string `gorm:"index"`
+	Total           int64
+	Processed       int64
+	Metadata        string
+	Result          string
+	Error           string
+	CancelRequested bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (b *Operation) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = uuid.New().String()
+	return
+}
+
+// Done reports whether the operation reached a terminal status
+func (b *Operation) Done() bool {
+	return b.Status != OperationRunning
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// OutboxDeadLetter is an outbox event the relay stopped retrying, kept with the
+// error of its last attempt
+type OutboxDeadLetter struct {
+	ID        string `gorm:"type:uuid;primarykey"`
+	Type      string
+	TenantID  string
+	Payload   string `gorm:"type:jsonb"`
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+	FailedAt  time.Time
+}
+
+// NewOutboxDeadLetter moves row aside after its last failed attempt
+func NewOutboxDeadLetter(row *OutboxEvent, lastErr error) *OutboxDeadLetter {
+	return &OutboxDeadLetter{
+		ID:        row.ID,
+		Type:      row.Type,
+		TenantID:  row.TenantID,
+		Payload:   row.Payload,
+		Attempts:  row.Attempts + 1,
+		LastError: lastErr.Error(),
+		CreatedAt: row.CreatedAt,
+		FailedAt:  time.Now(),
+	}
+}
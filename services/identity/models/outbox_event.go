@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared/events"
+)
+
+// OutboxEvent is an event written in the same transaction as the change it
+// describes and deleted by the relay once published
+type OutboxEvent struct {
+	ID       string `gorm:"type:uuid;primarykey"`
+	Type     string
+	TenantID string
+	// Payload is the JSON-encoded events.Event
+	Payload   string `gorm:"type:jsonb"`
+	Attempts  int
+	LastError string
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// NewOutboxEvent encodes the event for the outbox
+func NewOutboxEvent(event events.Event) (*OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		ID:       event.ID,
+		Type:     event.Type,
+		TenantID: event.TenantID,
+		Payload:  string(payload),
+	}, nil
+}
+
+// Event decodes the stored event
+func (e *OutboxEvent) Event() (events.Event, error) {
+	var event events.Event
+	err := json.Unmarshal([]byte(e.Payload), &event)
+	return event, err
+}
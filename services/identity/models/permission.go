@@ -7,8 +7,8 @@ import (
 )
 
 type Permission struct {
-	ID        uint `gorm:"primarykey"`
-	Name      string
+	ID        uint   `gorm:"primarykey"`
+	Name      string `validate:"required,max=128"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
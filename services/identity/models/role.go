@@ -1,23 +1,13 @@
 package models
 
-import (
-	"time"
-
-	"github.com/google/uuid"
-	"gorm.io/gorm"
-)
+import "github.com/gabehamasaki/momentum/shared/model"
 
 type Role struct {
-	ID        string `gorm:"type:uuid;primarykey"`
-	Name      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	model.BaseModel
+	Name string `validate:"required,max=64"`
 
 	Permissions []*Permission `gorm:"many2many:role_permissions"`
 }
 
-func (b *Role) BeforeCreate(tx *gorm.DB) (err error) {
-	b.ID = uuid.New().String()
-	return
-}
+// TenantShared makes roles without tenant available to every tenant
+func (Role) TenantShared() {}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Tenant is an organization whose users and roles carry its ID in tenant_id
+type Tenant struct {
+	ID        string `gorm:"primarykey" validate:"required,max=64"`
+	Name      string `validate:"required,max=120"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
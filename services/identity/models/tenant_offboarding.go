@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// TenantOffboarding records a deprovisioned tenant: the archive of its data and
+// when its suspended users are permanently deleted
+type TenantOffboarding struct {
+	TenantID    string    `gorm:"primarykey"`
+	OperationID string    `gorm:"type:uuid"`
+	ArchiveID   string    `gorm:"type:uuid"`
+	PurgeAfter  time.Time `gorm:"index"`
+	PurgedAt    *time.Time
+	CreatedAt   time.Time
+}
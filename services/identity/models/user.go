@@ -3,25 +3,23 @@ package models
 import (
 	"time"
 
-	"github.com/google/uuid"
-	"gorm.io/gorm"
+	"github.com/gabehamasaki/momentum/shared/model"
 )
 
 type User struct {
-	ID        string `gorm:"type:uuid;primarykey"`
-	Name      string
-	Email     string
-	Password  string
-	RoleID    string
-	Role      Role
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	model.BaseModel
+	Name     string `validate:"required,max=120"`
+	Email    string `validate:"required,email,max=254"`
+	Password string `validate:"required"`
+	RoleID   string `validate:"required"`
+	Role     Role
 
-	Permissions []*Permission `gorm:"many2many:user_permissions"`
-}
+	EmailVerifiedAt *time.Time
+
+	// PasswordChangedAt revokes the tokens issued before it
+	PasswordChangedAt *time.Time
 
-func (b *User) BeforeCreate(tx *gorm.DB) (err error) {
-	b.ID = uuid.New().String()
-	return
+	ScheduleDeactivationAt *time.Time `gorm:"index"`
+
+	Permissions []*Permission `gorm:"many2many:user_permissions"`
 }
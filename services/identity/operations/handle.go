@@ -0,0 +1,59 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"gorm.io/gorm"
+)
+
+// Handle gives a running operation access to its state
+type Handle struct {
+	Operation *models.Operation
+	manager   *Manager
+}
+
+// Metadata decodes the metadata the operation was started with
+func (h *Handle) Metadata(v any) error {
+	return json.Unmarshal([]byte(h.Operation.Metadata), v)
+}
+
+// AddProgress records processed items within tx, so progress is committed
+// atomically with the work it describes
+func (h *Handle) AddProgress(tx *gorm.DB, delta int64) error {
+	if err := tx.Model(&models.Operation{}).Where("id = ?", h.Operation.ID).
+		Update("processed", gorm.Expr("processed + ?", delta)).Error; err != nil {
+		return err
+	}
+	h.Operation.Processed += delta
+	return nil
+}
+
+// Checkpoint returns ErrCancelled once cancellation was requested, including
+// from another replica, and ctx's error when the operation is being stopped
+func (h *Handle) Checkpoint(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	conn, err := h.manager.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var requested []bool
+	if err := conn.Model(&models.Operation{}).Where("id = ?", h.Operation.ID).
+		Pluck("cancel_requested", &requested).Error; err != nil {
+		return err
+	}
+	if len(requested) > 0 && requested[0] {
+		return ErrCancelled
+	}
+	return nil
+}
+
+// Conn returns a database connection bound to ctx
+func (h *Handle) Conn(ctx context.Context) (*gorm.DB, error) {
+	return h.manager.db.ConnWithContext(ctx)
+}
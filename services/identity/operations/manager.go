@@ -0,0 +1,326 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const watchInterval = 500 * time.Millisecond
+
+var (
+	ErrNotFound    = errors.New("operation not found")
+	ErrUnknownKind = errors.New("unknown operation kind")
+	ErrDone        = errors.New("operation already finished")
+	ErrCancelled   = errors.New("operation cancelled")
+)
+
+// RunFunc executes an operation; the returned value is stored as the operation result.
+// It must be safe to run again after a crash, continuing from the recorded progress.
+type RunFunc func(ctx context.Context, op *Handle) (any, error)
+
+// Manager runs long-running operations in the background and persists their
+// state in the operations table, following google.longrunning semantics:
+// callers poll or watch an operation until it is done, and may cancel it.
+type Manager struct {
+	ctx    context.Context
+	db     *database.Database
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	runners map[string]RunFunc
+	running map[string]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewManager creates a Manager; ctx bounds the lifetime of background operations
+func NewManager(ctx context.Context, db *database.Database, logger *zap.Logger) *Manager {
+	return &Manager{
+		ctx:     ctx,
+		db:      db,
+		logger:  logger,
+		runners: make(map[string]RunFunc),
+		running: make(map[string]context.CancelFunc),
+	}
+}
+
+// Register associates an operation kind with the function that executes it
+func (m *Manager) Register(kind string, run RunFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runners[kind] = run
+}
+
+// Start records a new operation and runs it in the background
+func (m *Manager) Start(ctx context.Context, kind string, total int64, metadata any) (*models.Operation, error) {
+	if m.runner(kind) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode operation metadata: %w", err)
+	}
+
+	conn, err := m.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	op := &models.Operation{
+		Kind:     kind,
+		Status:   models.OperationRunning,
+		Total:    total,
+		Metadata: string(encoded),
+	}
+	if err := conn.Create(op).Error; err != nil {
+		return nil, err
+	}
+
+	m.launch(op.ID, kind)
+	return op, nil
+}
+
+// Get returns the current state of an operation
+func (m *Manager) Get(ctx context.Context, id string) (*models.Operation, error) {
+	conn, err := m.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var op models.Operation
+	if err := conn.First(&op, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &op, nil
+}
+
+// List returns operations, newest first, optionally filtered by kind
+func (m *Manager) List(ctx context.Context, kind string, limit, offset int) ([]models.Operation, error) {
+	conn, err := m.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := conn.Order("created_at DESC, id DESC").Limit(limit).Offset(offset)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var ops []models.Operation
+	if err := query.Find(&ops).Error; err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+// Cancel requests cancellation. It is best effort: the operation stops at its
+// next checkpoint and finishes with the cancelled status.
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	op, err := m.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if op.Done() {
+		return ErrDone
+	}
+
+	conn, err := m.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := conn.Model(op).Update("cancel_requested", true).Error; err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	cancel := m.running[id]
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	return nil
+}
+
+// Resume restarts a failed operation, or one orphaned by a crash, from its recorded progress
+func (m *Manager) Resume(ctx context.Context, id string) (*models.Operation, error) {
+	op, err := m.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status == models.OperationSucceeded || op.Status == models.OperationCancelled {
+		return op, nil
+	}
+	if m.runner(op.Kind) == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, op.Kind)
+	}
+
+	if op.Status == models.OperationFailed {
+		conn, err := m.db.ConnWithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := conn.Model(op).Updates(map[string]any{"status": models.OperationRunning, "error": ""}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	m.launch(op.ID, op.Kind)
+	return op, nil
+}
+
+// ResumeAll relaunches the operations that were running when the process stopped.
+// Call it after every kind has been registered.
+func (m *Manager) ResumeAll(ctx context.Context) error {
+	conn, err := m.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ops []models.Operation
+	if err := conn.Where("status = ?", models.OperationRunning).Find(&ops).Error; err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		if m.runner(op.Kind) == nil {
+			m.logger.Warn("Skipping operation of unknown kind", zap.String("operation_id", op.ID), zap.String("kind", op.Kind))
+			continue
+		}
+		m.logger.Info("Resuming operation",
+			zap.String("operation_id", op.ID),
+			zap.String("kind", op.Kind),
+			zap.Int64("processed", op.Processed),
+		)
+		m.launch(op.ID, op.Kind)
+	}
+	return nil
+}
+
+// Watch calls send whenever the operation changes, until it is done or ctx ends
+func (m *Manager) Watch(ctx context.Context, id string, send func(*models.Operation) error) error {
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var last *models.Operation
+	for {
+		op, err := m.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if last == nil || op.Processed != last.Processed || op.Status != last.Status {
+			if err := send(op); err != nil {
+				return err
+			}
+			last = op
+		}
+		if op.Done() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Wait blocks until the operations run by this process returned, which they do
+// once the context given to NewManager is cancelled
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+func (m *Manager) runner(kind string) RunFunc {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runners[kind]
+}
+
+// launch runs the operation in the background unless this process is already running it
+func (m *Manager) launch(id, kind string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.running[id]; ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.running[id] = cancel
+	run := m.runners[kind]
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer func() {
+			cancel()
+			m.mu.Lock()
+			delete(m.running, id)
+			m.mu.Unlock()
+		}()
+		m.run(ctx, id, run)
+	}()
+}
+
+func (m *Manager) run(ctx context.Context, id string, run RunFunc) {
+	logger := m.logger.With(zap.String("operation_id", id))
+
+	op, err := m.Get(ctx, id)
+	if err != nil {
+		logger.Error("Failed to load operation", zap.Error(err))
+		return
+	}
+	logger = logger.With(zap.String("kind", op.Kind))
+
+	result, err := run(ctx, &Handle{Operation: op, manager: m})
+
+	switch {
+	case m.ctx.Err() != nil:
+		// Shutting down: left as running so ResumeAll picks it up on the next start
+		return
+	case errors.Is(err, ErrCancelled) || errors.Is(err, context.Canceled):
+		m.finish(op, models.OperationCancelled, nil, ErrCancelled.Error())
+		logger.Info("Operation cancelled", zap.Int64("processed", op.Processed))
+	case err != nil:
+		m.finish(op, models.OperationFailed, nil, err.Error())
+		logger.Error("Operation failed", shared.ErrorSource(shared.ErrorSourceJob), zap.Error(err))
+	default:
+		m.finish(op, models.OperationSucceeded, result, "")
+		logger.Info("Operation succeeded", zap.Int64("processed", op.Processed))
+	}
+}
+
+func (m *Manager) finish(op *models.Operation, status string, result any, message string) {
+	updates := map[string]any{"status": status, "error": message}
+	if result != nil {
+		if encoded, err := json.Marshal(result); err == nil {
+			updates["result"] = string(encoded)
+		}
+	}
+	if status == models.OperationSucceeded && op.Processed > op.Total {
+		// Items added while the operation ran were processed too
+		updates["total"] = op.Processed
+	}
+
+	conn, err := m.db.ConnWithContext(context.Background())
+	if err == nil {
+		err = conn.Model(op).Updates(updates).Error
+	}
+	if err != nil {
+		m.logger.Error("Failed to record operation outcome", zap.String("operation_id", op.ID), zap.Error(err))
+	}
+}
@@ -0,0 +1,220 @@
+// Package outbox delivers events reliably: they are written to the outbox_events
+// table in the transaction of the change they describe, and a relay publishes
+// them afterwards. Delivery is at least once; consumers deduplicate by event ID.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultRelayInterval is how often the relay drains the outbox
+	DefaultRelayInterval = time.Second
+
+	// DefaultMaxAttempts is how many times the bus may reject an event before it
+	// is moved to the dead letters
+	DefaultMaxAttempts = 10
+
+	relayBatchSize = 100
+)
+
+// errUndecodable marks rows whose payload cannot be published however often it is retried
+var errUndecodable = errors.New("undecodable outbox event")
+
+// Add writes the event to the outbox within tx
+func Add(tx *gorm.DB, event events.Event) error {
+	row, err := models.NewOutboxEvent(event)
+	if err != nil {
+		return err
+	}
+	return tx.Create(row).Error
+}
+
+// Relay publishes outbox events in creation order and deletes them once the
+// publisher accepted them. Several replicas may run a relay; rows locked by one
+// are skipped by the others. Events the bus rejected maxAttempts times, or that
+// cannot be decoded, are moved to outbox_dead_letters so they stop blocking the
+// ones behind them.
+type Relay struct {
+	db          *database.Database
+	publisher   events.Publisher
+	logger      *zap.Logger
+	maxAttempts int
+
+	published    atomic.Int64
+	failures     atomic.Int64
+	deadLettered atomic.Int64
+	pending      atomic.Int64
+	deadLetters  atomic.Int64
+}
+
+// NewRelay creates a relay and registers its metrics; maxAttempts 0 retries forever
+func NewRelay(db *database.Database, publisher events.Publisher, logger *zap.Logger, metrics *shared.Metrics, maxAttempts int) *Relay {
+	r := &Relay{db: db, publisher: publisher, logger: logger, maxAttempts: maxAttempts}
+	if metrics != nil {
+		metrics.Register(r)
+	}
+	return r
+}
+
+// Run drains the outbox every interval until ctx ends
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.drain(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Error("Failed to relay outbox events", shared.ErrorSource(shared.ErrorSourceJob), zap.Error(err))
+			}
+		}
+	}
+}
+
+// drain publishes batches until the outbox is empty or a publish fails; the
+// failed event is retried first on the next run, keeping the order, unless it
+// is moved to the dead letters
+func (r *Relay) drain(ctx context.Context) error {
+	conn, err := r.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		var pending, deadLetters int64
+		if err := conn.Model(&models.OutboxEvent{}).Count(&pending).Error; err == nil {
+			r.pending.Store(pending)
+		}
+		if err := conn.Model(&models.OutboxDeadLetter{}).Count(&deadLetters).Error; err == nil {
+			r.deadLetters.Store(deadLetters)
+		}
+	}()
+
+	for {
+		var published int
+		var publishErr error
+		var dead []*models.OutboxDeadLetter
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			dead = nil
+			var rows []models.OutboxEvent
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Order("created_at, id").
+				Limit(relayBatchSize).
+				Find(&rows).Error; err != nil {
+				return err
+			}
+
+			var done []string
+			for i := range rows {
+				if publishErr = r.publish(ctx, &rows[i]); publishErr == nil {
+					done = append(done, rows[i].ID)
+					continue
+				}
+
+				if r.exhausted(&rows[i], publishErr) {
+					letter := models.NewOutboxDeadLetter(&rows[i], publishErr)
+					if err := tx.Create(letter).Error; err != nil {
+						return err
+					}
+					if err := tx.Delete(&rows[i]).Error; err != nil {
+						return err
+					}
+					dead = append(dead, letter)
+					publishErr = nil
+					continue
+				}
+
+				// An unreachable bus says nothing about the event, so only the error is recorded
+				updates := map[string]any{"last_error": publishErr.Error()}
+				if !errors.Is(publishErr, events.ErrUnavailable) {
+					updates["attempts"] = gorm.Expr("attempts + 1")
+				}
+				if err := tx.Model(&rows[i]).Updates(updates).Error; err != nil {
+					return err
+				}
+				break
+			}
+
+			published = len(done)
+			if published == 0 {
+				return nil
+			}
+			// Deleted with the lock still held: a crash before commit republishes
+			// the batch, which is why delivery is at least once
+			return tx.Where("id IN ?", done).Delete(&models.OutboxEvent{}).Error
+		})
+		if err != nil {
+			return err
+		}
+
+		r.published.Add(int64(published))
+		r.failures.Add(int64(len(dead)))
+		r.deadLettered.Add(int64(len(dead)))
+		for _, letter := range dead {
+			r.logger.Error("Moved outbox event to dead letters",
+				shared.ErrorSource(shared.ErrorSourceJob),
+				zap.String("event_id", letter.ID),
+				zap.String("event_type", letter.Type),
+				zap.Int("attempts", letter.Attempts),
+				zap.String("error", letter.LastError),
+			)
+		}
+		if publishErr != nil {
+			r.failures.Add(1)
+			return fmt.Errorf("failed to publish event: %w", publishErr)
+		}
+		if published+len(dead) < relayBatchSize {
+			return nil
+		}
+	}
+}
+
+// exhausted reports whether the failed row should stop being retried
+func (r *Relay) exhausted(row *models.OutboxEvent, err error) bool {
+	if errors.Is(err, errUndecodable) {
+		return true
+	}
+	return r.maxAttempts > 0 && !errors.Is(err, events.ErrUnavailable) && row.Attempts+1 >= r.maxAttempts
+}
+
+func (r *Relay) publish(ctx context.Context, row *models.OutboxEvent) error {
+	event, err := row.Event()
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUndecodable, err)
+	}
+	return r.publisher.Publish(ctx, event)
+}
+
+// WriteMetrics renders the relay counters in the Prometheus text format
+func (r *Relay) WriteMetrics(w io.Writer) {
+	fmt.Fprint(w, "# HELP outbox_events_published_total Events published from the outbox.\n")
+	fmt.Fprint(w, "# TYPE outbox_events_published_total counter\n")
+	fmt.Fprintf(w, "outbox_events_published_total %d\n", r.published.Load())
+	fmt.Fprint(w, "# HELP outbox_publish_failures_total Failed attempts to publish an outbox event.\n")
+	fmt.Fprint(w, "# TYPE outbox_publish_failures_total counter\n")
+	fmt.Fprintf(w, "outbox_publish_failures_total %d\n", r.failures.Load())
+	fmt.Fprint(w, "# HELP outbox_events_pending Events waiting in the outbox after the last relay run.\n")
+	fmt.Fprint(w, "# TYPE outbox_events_pending gauge\n")
+	fmt.Fprintf(w, "outbox_events_pending %d\n", r.pending.Load())
+	fmt.Fprint(w, "# HELP outbox_events_dead_lettered_total Events moved to the dead letters after their last attempt.\n")
+	fmt.Fprint(w, "# TYPE outbox_events_dead_lettered_total counter\n")
+	fmt.Fprintf(w, "outbox_events_dead_lettered_total %d\n", r.deadLettered.Load())
+	fmt.Fprint(w, "# HELP outbox_dead_letters Events waiting in the dead letters after the last relay run.\n")
+	fmt.Fprint(w, "# TYPE outbox_dead_letters gauge\n")
+	fmt.Fprintf(w, "outbox_dead_letters %d\n", r.deadLetters.Load())
+}
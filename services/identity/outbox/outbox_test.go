@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/events"
+)
+
+func TestRelayExhausted(t *testing.T) {
+	rejected := errors.New("maximum payload violation")
+	unavailable := fmt.Errorf("%w: nats: connection refused", events.ErrUnavailable)
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		attempts    int
+		err         error
+		want        bool
+	}{
+		{"first rejection", 3, 0, rejected, false},
+		{"last rejection", 3, 2, rejected, true},
+		{"bus down past the limit", 3, 5, unavailable, false},
+		{"retried forever", 0, 100, rejected, false},
+		{"undecodable payload", 0, 0, fmt.Errorf("%w: unexpected end of JSON input", errUndecodable), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Relay{maxAttempts: tt.maxAttempts}
+			if got := r.exhausted(&models.OutboxEvent{Attempts: tt.attempts}, tt.err); got != tt.want {
+				t.Fatalf("exhausted = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
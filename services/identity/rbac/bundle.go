@@ -0,0 +1,114 @@
+package rbac
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BundleVersion is the format version of exported configuration bundles
+const BundleVersion = 1
+
+var (
+	// ErrInvalidBundleSignature is returned when a bundle was not signed with the expected key
+	ErrInvalidBundleSignature = errors.New("invalid configuration bundle signature")
+
+	// ErrInvalidBundle is returned for bundles that cannot be decoded or are inconsistent
+	ErrInvalidBundle = errors.New("invalid configuration bundle")
+)
+
+// Bundle is the portable form of the access-control configuration. User
+// assignments and credentials are deliberately left out.
+type Bundle struct {
+	Version     int              `json:"version"`
+	ExportedAt  time.Time        `json:"exported_at"`
+	Permissions []string         `json:"permissions"`
+	Roles       []RoleDefinition `json:"roles"`
+}
+
+// Definition converts the bundle into a definition that can be planned and applied
+func (b *Bundle) Definition() *Definition {
+	return &Definition{
+		Permissions: b.Permissions,
+		Roles:       b.Roles,
+	}
+}
+
+// Export builds a bundle from the live database state
+func Export(ctx context.Context, db *gorm.DB) (*Bundle, error) {
+	state, err := loadState(db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		Version:     BundleVersion,
+		ExportedAt:  time.Now().UTC(),
+		Permissions: make([]string, 0, len(state.permissions)),
+		Roles:       make([]RoleDefinition, 0, len(state.roles)),
+	}
+	for name := range state.permissions {
+		bundle.Permissions = append(bundle.Permissions, name)
+	}
+	sort.Strings(bundle.Permissions)
+
+	for name, role := range state.roles {
+		def := RoleDefinition{Name: name, Permissions: make([]string, 0, len(role.Permissions))}
+		for _, perm := range role.Permissions {
+			def.Permissions = append(def.Permissions, perm.Name)
+		}
+		sort.Strings(def.Permissions)
+		bundle.Roles = append(bundle.Roles, def)
+	}
+	sort.Slice(bundle.Roles, func(i, j int) bool { return bundle.Roles[i].Name < bundle.Roles[j].Name })
+
+	return bundle, nil
+}
+
+// SignBundle serializes the bundle and returns the payload with its HMAC-SHA256 signature
+func SignBundle(bundle *Bundle, key []byte) ([]byte, string, error) {
+	payload, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	return payload, bundleSignature(payload, key), nil
+}
+
+// VerifyBundle checks the signature and decodes a signed payload
+func VerifyBundle(payload []byte, signature string, key []byte) (*Bundle, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, bundleMAC(payload, key)) {
+		return nil, ErrInvalidBundleSignature
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(payload, &bundle); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBundle, bundle.Version)
+	}
+	if err := bundle.Definition().Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBundle, err)
+	}
+
+	return &bundle, nil
+}
+
+func bundleSignature(payload, key []byte) string {
+	return hex.EncodeToString(bundleMAC(payload, key))
+}
+
+func bundleMAC(payload, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,116 @@
+package rbac
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is the declarative description of the access-control state
+type Definition struct {
+	Permissions []string         `json:"permissions" yaml:"permissions"`
+	Roles       []RoleDefinition `json:"roles" yaml:"roles"`
+	Assignments []Assignment     `json:"assignments" yaml:"assignments"`
+}
+
+// RoleDefinition declares a role and the exact set of permissions it grants
+type RoleDefinition struct {
+	Name        string   `json:"name" yaml:"name"`
+	Permissions []string `json:"permissions" yaml:"permissions"`
+}
+
+// Assignment declares the role a user (identified by email) must have
+type Assignment struct {
+	Email string `json:"email" yaml:"email"`
+	Role  string `json:"role" yaml:"role"`
+}
+
+// LoadDefinition reads and validates a definition file, decoded as YAML for
+// .yaml and .yml files and as JSON otherwise
+func LoadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read definition: %w", err)
+	}
+
+	unmarshal := json.Unmarshal
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	}
+
+	var def Definition
+	if err := unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("failed to parse definition: %w", err)
+	}
+
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &def, nil
+}
+
+// Validate checks the definition is self-consistent
+func (d *Definition) Validate() error {
+	permissions := make(map[string]bool, len(d.Permissions))
+	for _, name := range d.Permissions {
+		if name == "" {
+			return errors.New("permission with empty name")
+		}
+		if permissions[name] {
+			return fmt.Errorf("permission %q declared more than once", name)
+		}
+		permissions[name] = true
+	}
+
+	roles := make(map[string]bool, len(d.Roles))
+	for _, role := range d.Roles {
+		if role.Name == "" {
+			return errors.New("role with empty name")
+		}
+		if roles[role.Name] {
+			return fmt.Errorf("role %q declared more than once", role.Name)
+		}
+		roles[role.Name] = true
+
+		for _, perm := range role.Permissions {
+			if !permissions[perm] {
+				return fmt.Errorf("role %q references undeclared permission %q", role.Name, perm)
+			}
+		}
+	}
+
+	assigned := make(map[string]bool, len(d.Assignments))
+	for _, assignment := range d.Assignments {
+		if assignment.Email == "" {
+			return errors.New("assignment without email")
+		}
+		if assigned[assignment.Email] {
+			return fmt.Errorf("user %q assigned more than once", assignment.Email)
+		}
+		assigned[assignment.Email] = true
+
+		if !roles[assignment.Role] {
+			return fmt.Errorf("user %q assigned to undeclared role %q", assignment.Email, assignment.Role)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of a set in a stable order for deterministic plans
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
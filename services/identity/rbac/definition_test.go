@@ -0,0 +1,74 @@
+package rbac
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const yamlDefinition = `
+permissions:
+  - profile.view
+  - user.view
+roles:
+  - name: member
+    permissions: [profile.view]
+  - name: admin
+    permissions:
+      - profile.view
+      - user.view
+assignments:
+  - email: admin@momentum.dev
+    role: admin
+`
+
+func writeDefinition(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDefinitionByExtension(t *testing.T) {
+	example, err := LoadDefinition("../rbac.example.json")
+	if err != nil {
+		t.Fatalf("LoadDefinition(rbac.example.json): %v", err)
+	}
+	if len(example.Roles) == 0 || len(example.Assignments) == 0 {
+		t.Fatalf("JSON example loaded empty: %+v", example)
+	}
+
+	for _, name := range []string{"roles.yaml", "roles.YML"} {
+		t.Run(name, func(t *testing.T) {
+			def, err := LoadDefinition(writeDefinition(t, name, yamlDefinition))
+			if err != nil {
+				t.Fatalf("LoadDefinition: %v", err)
+			}
+
+			expected := &Definition{
+				Permissions: []string{"profile.view", "user.view"},
+				Roles: []RoleDefinition{
+					{Name: "member", Permissions: []string{"profile.view"}},
+					{Name: "admin", Permissions: []string{"profile.view", "user.view"}},
+				},
+				Assignments: []Assignment{{Email: "admin@momentum.dev", Role: "admin"}},
+			}
+			if !reflect.DeepEqual(def, expected) {
+				t.Fatalf("definition = %+v, want %+v", def, expected)
+			}
+		})
+	}
+}
+
+func TestLoadDefinitionValidatesYAML(t *testing.T) {
+	invalid := strings.Replace(yamlDefinition, "role: admin", "role: owner", 1)
+
+	_, err := LoadDefinition(writeDefinition(t, "roles.yaml", invalid))
+	if err == nil || !strings.Contains(err.Error(), `undeclared role "owner"`) {
+		t.Fatalf("LoadDefinition error = %v, want the undeclared role reported", err)
+	}
+}
@@ -0,0 +1,441 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"gorm.io/gorm"
+)
+
+// ErrPlanChanged is returned when the live state changed between planning and applying
+var ErrPlanChanged = errors.New("live state changed since the plan was computed")
+
+// Action is the kind of change a plan entry performs
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Resource is the kind of object a plan entry targets
+type Resource string
+
+const (
+	ResourcePermission Resource = "permission"
+	ResourceRole       Resource = "role"
+	ResourceAssignment Resource = "assignment"
+)
+
+// Change is a single step needed to converge the live state to the definition
+type Change struct {
+	Action   Action
+	Resource Resource
+	Name     string
+
+	// Add and Remove list the permissions granted/revoked by a role change
+	Add    []string
+	Remove []string
+
+	// From and To are the previous and new role of an assignment change
+	From string
+	To   string
+}
+
+// String renders the change in a terraform-like notation
+func (c Change) String() string {
+	symbol := map[Action]string{ActionCreate: "+", ActionUpdate: "~", ActionDelete: "-"}[c.Action]
+
+	var details []string
+	for _, perm := range c.Add {
+		details = append(details, "+"+perm)
+	}
+	for _, perm := range c.Remove {
+		details = append(details, "-"+perm)
+	}
+	if c.Resource == ResourceAssignment {
+		from := c.From
+		if from == "" {
+			from = "(none)"
+		}
+		details = append(details, from+" -> "+c.To)
+	}
+
+	line := fmt.Sprintf("%s %s %s", symbol, c.Resource, c.Name)
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	return line
+}
+
+// Plan is the ordered list of changes to apply
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the live state already matches the definition
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Print writes a human readable plan summary
+func (p *Plan) Print(w io.Writer) {
+	if p.Empty() {
+		fmt.Fprintln(w, "No changes. Live state matches the definition.")
+		return
+	}
+
+	counts := map[Action]int{}
+	for _, change := range p.Changes {
+		fmt.Fprintln(w, "  "+change.String())
+		counts[change.Action]++
+	}
+	fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete])
+}
+
+// equal reports whether two plans contain the same changes
+func (p *Plan) equal(other *Plan) bool {
+	if len(p.Changes) != len(other.Changes) {
+		return false
+	}
+	for i := range p.Changes {
+		if p.Changes[i].String() != other.Changes[i].String() {
+			return false
+		}
+	}
+	return true
+}
+
+// Options controls how the definition is reconciled
+type Options struct {
+	// Prune deletes permissions and roles that exist live but are absent from the definition
+	Prune bool
+}
+
+// ComputePlan diffs the definition against the live database state
+func ComputePlan(ctx context.Context, db *gorm.DB, def *Definition, opts Options) (*Plan, error) {
+	state, err := loadState(db.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return diff(state, def, opts)
+}
+
+// Apply recomputes the plan inside a transaction and applies it, failing with
+// ErrPlanChanged if it no longer matches the plan that was shown to the operator.
+// It returns the IDs of the users whose role or permissions changed, for callers
+// caching users to invalidate them.
+func Apply(ctx context.Context, db *gorm.DB, def *Definition, opts Options, expected *Plan) ([]string, error) {
+	var affected []string
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state, err := loadState(tx)
+		if err != nil {
+			return err
+		}
+
+		plan, err := diff(state, def, opts)
+		if err != nil {
+			return err
+		}
+		if expected != nil && !plan.equal(expected) {
+			return ErrPlanChanged
+		}
+
+		for _, change := range plan.Changes {
+			if err := applyChange(tx, state, change); err != nil {
+				return fmt.Errorf("failed to apply %q: %w", change.String(), err)
+			}
+		}
+
+		affected = sortedKeys(state.affected)
+		return nil
+	})
+	return affected, err
+}
+
+// liveState is the subset of the database relevant to access control
+type liveState struct {
+	permissions map[string]*models.Permission
+	roles       map[string]*models.Role
+	users       map[string]*models.User
+
+	// affected collects the IDs of users changed by applied changes
+	affected map[string]bool
+}
+
+func loadState(tx *gorm.DB) (*liveState, error) {
+	var permissions []*models.Permission
+	if err := tx.Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load permissions: %w", err)
+	}
+
+	var roles []*models.Role
+	if err := tx.Preload("Permissions").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to load roles: %w", err)
+	}
+
+	var users []*models.User
+	if err := tx.Preload("Role").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to load users: %w", err)
+	}
+
+	state := &liveState{
+		permissions: make(map[string]*models.Permission, len(permissions)),
+		roles:       make(map[string]*models.Role, len(roles)),
+		users:       make(map[string]*models.User, len(users)),
+		affected:    make(map[string]bool),
+	}
+	for _, perm := range permissions {
+		state.permissions[perm.Name] = perm
+	}
+	for _, role := range roles {
+		state.roles[role.Name] = role
+	}
+	for _, user := range users {
+		state.users[user.Email] = user
+	}
+
+	return state, nil
+}
+
+// diff computes the changes in dependency order: permissions, roles, assignments, then deletions
+func diff(state *liveState, def *Definition, opts Options) (*Plan, error) {
+	plan := &Plan{}
+
+	desiredPermissions := make(map[string]bool, len(def.Permissions))
+	for _, name := range def.Permissions {
+		desiredPermissions[name] = true
+	}
+	for _, name := range sortedKeys(desiredPermissions) {
+		if _, ok := state.permissions[name]; !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Resource: ResourcePermission, Name: name})
+		}
+	}
+
+	desiredRoles := make(map[string]bool, len(def.Roles))
+	for _, role := range def.Roles {
+		desiredRoles[role.Name] = true
+
+		want := make(map[string]bool, len(role.Permissions))
+		for _, perm := range role.Permissions {
+			want[perm] = true
+		}
+
+		live, ok := state.roles[role.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Resource: ResourceRole, Name: role.Name, Add: sortedKeys(want)})
+			continue
+		}
+
+		have := make(map[string]bool, len(live.Permissions))
+		for _, perm := range live.Permissions {
+			have[perm.Name] = true
+		}
+
+		var add, remove []string
+		for _, perm := range sortedKeys(want) {
+			if !have[perm] {
+				add = append(add, perm)
+			}
+		}
+		for _, perm := range sortedKeys(have) {
+			if !want[perm] {
+				remove = append(remove, perm)
+			}
+		}
+		if len(add) > 0 || len(remove) > 0 {
+			plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Resource: ResourceRole, Name: role.Name, Add: add, Remove: remove})
+		}
+	}
+
+	for _, assignment := range def.Assignments {
+		user, ok := state.users[assignment.Email]
+		if !ok {
+			return nil, fmt.Errorf("user %q does not exist", assignment.Email)
+		}
+		if user.Role.Name != assignment.Role {
+			plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Resource: ResourceAssignment, Name: assignment.Email, From: user.Role.Name, To: assignment.Role})
+		}
+	}
+
+	if !opts.Prune {
+		return plan, nil
+	}
+
+	liveRoles := make(map[string]bool, len(state.roles))
+	for name := range state.roles {
+		liveRoles[name] = true
+	}
+	for _, name := range sortedKeys(liveRoles) {
+		if desiredRoles[name] {
+			continue
+		}
+		for _, user := range state.users {
+			if user.Role.Name == name && !isReassigned(def, user.Email) {
+				return nil, fmt.Errorf("cannot prune role %q: user %q is still assigned to it", name, user.Email)
+			}
+		}
+		plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Resource: ResourceRole, Name: name})
+	}
+
+	livePermissions := make(map[string]bool, len(state.permissions))
+	for name := range state.permissions {
+		livePermissions[name] = true
+	}
+	for _, name := range sortedKeys(livePermissions) {
+		if !desiredPermissions[name] {
+			plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Resource: ResourcePermission, Name: name})
+		}
+	}
+
+	return plan, nil
+}
+
+// isReassigned reports whether the definition moves the user to another role
+func isReassigned(def *Definition, email string) bool {
+	for _, assignment := range def.Assignments {
+		if assignment.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// applyChange executes a single change, keeping state up to date for later changes
+func applyChange(tx *gorm.DB, state *liveState, change Change) error {
+	switch change.Resource {
+	case ResourcePermission:
+		switch change.Action {
+		case ActionCreate:
+			perm := &models.Permission{Name: change.Name}
+			if err := tx.Create(perm).Error; err != nil {
+				return err
+			}
+			state.permissions[change.Name] = perm
+		case ActionDelete:
+			perm := state.permissions[change.Name]
+			if err := affectUsers(tx, state, `SELECT user_id FROM user_permissions WHERE permission_id = ?
+				UNION SELECT u.id FROM users u JOIN role_permissions rp ON rp.role_id = u.role_id WHERE rp.permission_id = ?`, perm.ID, perm.ID); err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM role_permissions WHERE permission_id = ?", perm.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM user_permissions WHERE permission_id = ?", perm.ID).Error; err != nil {
+				return err
+			}
+			return tx.Delete(perm).Error
+		}
+
+	case ResourceRole:
+		switch change.Action {
+		case ActionCreate:
+			role := &models.Role{Name: change.Name}
+			if err := tx.Create(role).Error; err != nil {
+				return err
+			}
+			state.roles[change.Name] = role
+			if err := tx.Model(role).Association("Permissions").Append(lookupPermissions(state, change.Add)); err != nil {
+				return err
+			}
+			return propagateRolePermissions(tx, role, lookupPermissions(state, change.Add), nil)
+		case ActionUpdate:
+			role := state.roles[change.Name]
+			if err := affectUsers(tx, state, "SELECT id FROM users WHERE role_id = ?", role.ID); err != nil {
+				return err
+			}
+			add, remove := lookupPermissions(state, change.Add), lookupPermissions(state, change.Remove)
+			if len(add) > 0 {
+				if err := tx.Model(role).Association("Permissions").Append(add); err != nil {
+					return err
+				}
+			}
+			if len(remove) > 0 {
+				if err := tx.Model(role).Association("Permissions").Delete(remove); err != nil {
+					return err
+				}
+			}
+			return propagateRolePermissions(tx, role, add, remove)
+		case ActionDelete:
+			role := state.roles[change.Name]
+			if err := affectUsers(tx, state, "SELECT id FROM users WHERE role_id = ?", role.ID); err != nil {
+				return err
+			}
+			if err := tx.Model(role).Association("Permissions").Clear(); err != nil {
+				return err
+			}
+			return tx.Delete(role).Error
+		}
+
+	case ResourceAssignment:
+		user := state.users[change.Name]
+		role := state.roles[change.To]
+
+		var rolePermissions []*models.Permission
+		if err := tx.Model(role).Association("Permissions").Find(&rolePermissions); err != nil {
+			return err
+		}
+
+		if err := tx.Model(user).Update("role_id", role.ID).Error; err != nil {
+			return err
+		}
+		state.affected[user.ID] = true
+		// Users carry a copy of their role's permissions, as done by StoreUser
+		return tx.Model(user).Association("Permissions").Replace(rolePermissions)
+	}
+
+	return nil
+}
+
+// propagateRolePermissions grants and revokes permissions of a role's users, who
+// carry a copy of their role's permissions as done by StoreUser
+func propagateRolePermissions(tx *gorm.DB, role *models.Role, add, remove []*models.Permission) error {
+	if len(add) > 0 {
+		if err := tx.Exec(`INSERT INTO user_permissions (user_id, permission_id)
+			SELECT u.id, p.id FROM users u CROSS JOIN permissions p WHERE u.role_id = ? AND p.id IN ?
+			ON CONFLICT DO NOTHING`, role.ID, permissionIDs(add)).Error; err != nil {
+			return fmt.Errorf("failed to grant role permissions to users: %w", err)
+		}
+	}
+	if len(remove) > 0 {
+		if err := tx.Exec("DELETE FROM user_permissions WHERE permission_id IN ? AND user_id IN (SELECT id FROM users WHERE role_id = ?)",
+			permissionIDs(remove), role.ID).Error; err != nil {
+			return fmt.Errorf("failed to revoke role permissions from users: %w", err)
+		}
+	}
+	return nil
+}
+
+func permissionIDs(permissions []*models.Permission) []uint {
+	ids := make([]uint, len(permissions))
+	for i, perm := range permissions {
+		ids[i] = perm.ID
+	}
+	return ids
+}
+
+// affectUsers records the user IDs selected by query as affected
+func affectUsers(tx *gorm.DB, state *liveState, query string, args ...any) error {
+	var ids []string
+	if err := tx.Raw(query, args...).Scan(&ids).Error; err != nil {
+		return err
+	}
+	for _, id := range ids {
+		state.affected[id] = true
+	}
+	return nil
+}
+
+func lookupPermissions(state *liveState, names []string) []*models.Permission {
+	permissions := make([]*models.Permission, 0, len(names))
+	for _, name := range names {
+		permissions = append(permissions, state.permissions[name])
+	}
+	return permissions
+}
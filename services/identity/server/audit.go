@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/services"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// AuditedMethods declares the mutating RPCs recorded in the audit trail and the type of their target
+var AuditedMethods = map[string]string{
+	proto.IdentityService_StoreUser_FullMethodName:            "user",
+	proto.IdentityService_UpdateUser_FullMethodName:           "user",
+	proto.IdentityService_DeleteUser_FullMethodName:           "user",
+	proto.IdentityService_ScheduleDeactivation_FullMethodName: "user",
+	proto.IdentityService_CancelDeactivation_FullMethodName:   "user",
+	proto.IdentityService_RestoreUser_FullMethodName:          "user",
+	proto.IdentityService_ConfirmPasswordReset_FullMethodName: "user",
+	proto.IdentityService_ChangePassword_FullMethodName:       "user",
+	proto.IdentityService_VerifyEmail_FullMethodName:          "user",
+
+	proto.IdentityService_StoreRole_FullMethodName:        "role",
+	proto.IdentityService_UpdateRole_FullMethodName:       "role",
+	proto.IdentityService_DeleteRole_FullMethodName:       "role",
+	proto.IdentityService_ReassignRole_FullMethodName:     "role",
+	proto.IdentityService_StorePermission_FullMethodName:  "permission",
+	proto.IdentityService_UpdatePermission_FullMethodName: "permission",
+	proto.IdentityService_DeletePermission_FullMethodName: "permission",
+
+	proto.IdentityService_ImportConfig_FullMethodName:          "config",
+	proto.IdentityService_UpdateSensitiveFields_FullMethodName: "sensitive_fields",
+	proto.IdentityService_CancelOperation_FullMethodName:       "operation",
+	proto.IdentityService_CreateTenant_FullMethodName:          "tenant",
+	proto.IdentityService_UpdateTenant_FullMethodName:          "tenant",
+	proto.IdentityService_DeprovisionTenant_FullMethodName:     "tenant",
+	proto.IdentityService_BulkImportUsers_FullMethodName:       "tenant",
+}
+
+// auditRecord collects what a handler knows about its mutation
+type auditRecord struct {
+	targetID      string
+	before, after any
+}
+
+type auditRecordKey struct{}
+
+// auditTarget sets the target of the audited call, for handlers whose request has no id
+func auditTarget(ctx context.Context, targetID string) {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		record.targetID = targetID
+	}
+}
+
+// auditChange attaches the state before and after the mutation to the audited call
+func auditChange(ctx context.Context, before, after any) {
+	if record, ok := ctx.Value(auditRecordKey{}).(*auditRecord); ok {
+		record.before, record.after = before, after
+	}
+}
+
+// auditor writes one audit event per call of an AuditedMethods RPC
+type auditor struct {
+	audit  *services.AuditService
+	logger *zap.Logger
+}
+
+// AuditUnaryInterceptor records every call of an AuditedMethods RPC with its caller and
+// outcome. It must run after authentication so the actor is known.
+func AuditUnaryInterceptor(audit *services.AuditService, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	a := auditor{audit: audit, logger: logger}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		targetType, ok := AuditedMethods[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		record := &auditRecord{}
+		if withID, ok := req.(interface{ GetId() string }); ok {
+			record.targetID = withID.GetId()
+		}
+		ctx = context.WithValue(ctx, auditRecordKey{}, record)
+
+		resp, err := handler(ctx, req)
+		a.record(ctx, info.FullMethod, targetType, record, err)
+		return resp, err
+	}
+}
+
+// AuditStreamInterceptor is the streaming counterpart of AuditUnaryInterceptor; stream
+// handlers set the target with auditTarget
+func AuditStreamInterceptor(audit *services.AuditService, logger *zap.Logger) grpc.StreamServerInterceptor {
+	a := auditor{audit: audit, logger: logger}
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		targetType, ok := AuditedMethods[info.FullMethod]
+		if !ok {
+			return handler(srv, ss)
+		}
+
+		record := &auditRecord{}
+		ctx := context.WithValue(ss.Context(), auditRecordKey{}, record)
+
+		err := handler(srv, &auditServerStream{ServerStream: ss, ctx: ctx})
+		a.record(ctx, info.FullMethod, targetType, record, err)
+		return err
+	}
+}
+
+func (a auditor) record(ctx context.Context, method, targetType string, record *auditRecord, err error) {
+	event := models.AuditEvent{
+		TenantID:   shared.TenantFromContext(ctx),
+		Action:     path.Base(method),
+		TargetType: targetType,
+		TargetID:   record.targetID,
+		Before:     auditJSON(record.before),
+		After:      auditJSON(record.after),
+		Code:       status.Code(err).String(),
+	}
+	if user, ok := shared.UserFromContext(ctx); ok {
+		event.ActorID = user.ID
+	}
+
+	if err := a.audit.Record(event); err != nil {
+		a.logger.Error("Failed to record audit event",
+			zap.String("grpc.method", method),
+			zap.String("target_id", event.TargetID),
+			zap.Error(err),
+		)
+	}
+}
+
+func auditJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+type auditServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *auditServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// userPageToken is the position of a ListUsers page: the key of the last user
+// returned, and the instant fixed by a snapshot listing
+type userPageToken struct {
+	CreatedAt time.Time  `json:"created_at"`
+	ID        string     `json:"id"`
+	AsOf      *time.Time `json:"as_of,omitempty"`
+}
+
+// encodeUserPageToken makes the token opaque so clients don't depend on its contents
+func encodeUserPageToken(token userPageToken) (string, error) {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeUserPageToken(s string) (userPageToken, error) {
+	var token userPageToken
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return token, err
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return token, err
+	}
+	if token.ID == "" {
+		return token, errors.New("page token without id")
+	}
+	return token, nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// PublicMethods are callable without a token, for users who cannot sign in yet,
+// for clients checking the server version and for health probes
+var PublicMethods = []string{
+	proto.IdentityService_RequestPasswordReset_FullMethodName,
+	proto.IdentityService_ConfirmPasswordReset_FullMethodName,
+	proto.IdentityService_SendVerificationEmail_FullMethodName,
+	proto.IdentityService_VerifyEmail_FullMethodName,
+	proto.IdentityService_GetVersion_FullMethodName,
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+}
+
+// MethodPermissions declares the permission required by each identity RPC
+var MethodPermissions = map[string]string{
+	proto.IdentityService_GetUsers_FullMethodName:             "user.view",
+	proto.IdentityService_ListUsers_FullMethodName:            "user.view",
+	proto.IdentityService_SearchUsers_FullMethodName:          "user.view",
+	proto.IdentityService_GetUser_FullMethodName:              "user.view",
+	proto.IdentityService_ExportUsers_FullMethodName:          "user.view",
+	proto.IdentityService_StoreUser_FullMethodName:            "user.store",
+	proto.IdentityService_BulkImportUsers_FullMethodName:      "user.store",
+	proto.IdentityService_UpdateUser_FullMethodName:           "user.update",
+	proto.IdentityService_DeleteUser_FullMethodName:           "user.delete",
+	proto.IdentityService_ScheduleDeactivation_FullMethodName: "user.delete",
+	proto.IdentityService_CancelDeactivation_FullMethodName:   "user.delete",
+	proto.IdentityService_RestoreUser_FullMethodName:          "user.delete",
+	proto.IdentityService_ChangePassword_FullMethodName:       "profile.edit",
+
+	proto.IdentityService_GetRoles_FullMethodName:              "role.view",
+	proto.IdentityService_GetRole_FullMethodName:               "role.view",
+	proto.IdentityService_GetPermissions_FullMethodName:        "role.view",
+	proto.IdentityService_GetPermission_FullMethodName:         "role.view",
+	proto.IdentityService_StoreRole_FullMethodName:             "role.manage",
+	proto.IdentityService_UpdateRole_FullMethodName:            "role.manage",
+	proto.IdentityService_DeleteRole_FullMethodName:            "role.manage",
+	proto.IdentityService_StorePermission_FullMethodName:       "role.manage",
+	proto.IdentityService_UpdatePermission_FullMethodName:      "role.manage",
+	proto.IdentityService_DeletePermission_FullMethodName:      "role.manage",
+	proto.IdentityService_ExportConfig_FullMethodName:          "role.manage",
+	proto.IdentityService_ImportConfig_FullMethodName:          "role.manage",
+	proto.IdentityService_GetSensitiveFields_FullMethodName:    "role.manage",
+	proto.IdentityService_UpdateSensitiveFields_FullMethodName: "role.manage",
+	proto.IdentityService_ReassignRole_FullMethodName:          "role.manage",
+
+	proto.IdentityService_GetTenant_FullMethodName:         "tenant.view",
+	proto.IdentityService_ListTenants_FullMethodName:       "tenant.view",
+	proto.IdentityService_CreateTenant_FullMethodName:      "tenant.manage",
+	proto.IdentityService_UpdateTenant_FullMethodName:      "tenant.manage",
+	proto.IdentityService_DeprovisionTenant_FullMethodName: "tenant.deprovision",
+
+	proto.IdentityService_GetOperation_FullMethodName:    "operation.view",
+	proto.IdentityService_ListOperations_FullMethodName:  "operation.view",
+	proto.IdentityService_CancelOperation_FullMethodName: "operation.cancel",
+
+	proto.IdentityService_ListAuditEvents_FullMethodName: "audit.view",
+	proto.IdentityService_GetArtifact_FullMethodName:     "artifact.download",
+
+	proto.IdentityService_GetSLOStatus_FullMethodName: "diagnostics.view",
+}
+
+// CheckToken rejects the tokens of users that were deleted since they were issued,
+// see shared.AuthConfig.CheckToken
+func (s *IdentityServer) CheckToken(ctx context.Context, claims *shared.Claims) error {
+	return s.userService.CheckToken(ctx, claims)
+}
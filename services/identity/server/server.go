@@ -2,23 +2,65 @@ package server
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"time"
 
+	"github.com/gabehamasaki/momentum/services/identity/artifacts"
+	"github.com/gabehamasaki/momentum/services/identity/converters"
+	"github.com/gabehamasaki/momentum/services/identity/database"
 	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/operations"
+	"github.com/gabehamasaki/momentum/services/identity/rbac"
 	"github.com/gabehamasaki/momentum/services/identity/services"
 	"github.com/gabehamasaki/momentum/services/identity/utils"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/model"
 	"github.com/gabehamasaki/momentum/shared/v1/proto"
 	"github.com/golang/protobuf/ptypes/empty"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type IdentityServer struct {
 	proto.UnimplementedIdentityServiceServer
-	logger      *zap.Logger
-	userService *services.UserService
+	logger                *zap.Logger
+	userService           *services.UserService
+	configService         *services.ConfigService
+	reassignmentService   *services.ReassignmentService
+	deprovisioningService *services.DeprovisioningService
+	offboardingService    *services.TenantOffboardingService
+	tenantService         *services.TenantService
+	passwordResetService  *services.PasswordResetService
+	verificationService   *services.EmailVerificationService
+	operations            *operations.Manager
+	audit                 *services.AuditService
+	artifacts             *artifacts.Store
+	sensitiveFields       *shared.SensitiveFieldRegistry
+	slo                   *shared.SLOTracker
+	build                 shared.BuildInfo
 }
 
-func NewIdentityServer(userService *services.UserService, logger *zap.Logger) *IdentityServer {
-	return &IdentityServer{userService: userService}
+func NewIdentityServer(userService *services.UserService, configService *services.ConfigService, reassignmentService *services.ReassignmentService, deprovisioningService *services.DeprovisioningService, offboardingService *services.TenantOffboardingService, tenantService *services.TenantService, passwordResetService *services.PasswordResetService, verificationService *services.EmailVerificationService, operationManager *operations.Manager, auditService *services.AuditService, artifactStore *artifacts.Store, sensitiveFields *shared.SensitiveFieldRegistry, slo *shared.SLOTracker, build shared.BuildInfo, logger *zap.Logger) *IdentityServer {
+	return &IdentityServer{
+		userService:           userService,
+		configService:         configService,
+		reassignmentService:   reassignmentService,
+		deprovisioningService: deprovisioningService,
+		offboardingService:    offboardingService,
+		tenantService:         tenantService,
+		passwordResetService:  passwordResetService,
+		verificationService:   verificationService,
+		operations:            operationManager,
+		audit:                 auditService,
+		artifacts:             artifactStore,
+		sensitiveFields:       sensitiveFields,
+		slo:                   slo,
+		build:                 build,
+		logger:                logger,
+	}
 }
 
 func (s *IdentityServer) GetUsers(ctx context.Context, empty *empty.Empty) (*proto.GetUsersResponse, error) {
@@ -27,40 +69,85 @@ func (s *IdentityServer) GetUsers(ctx context.Context, empty *empty.Empty) (*pro
 		return nil, err
 	}
 
-	var protoUsers []*proto.User
-	for _, user := range users {
-		protoUser := &proto.User{
-			Id:        user.ID,
-			Name:      user.Name,
-			Email:     user.Email,
-			Role:      user.Role.Name,
-			CreatedAt: user.CreatedAt.Format("2006-01-02 15:04:05"),
+	return &proto.GetUsersResponse{Users: converters.Users(users)}, nil
+}
+
+// ListUsers pages through the users; deactivated users are only listed with
+// include_deleted. A snapshot listing keeps the users of its first page's instant
+// across all pages, which the token carries.
+func (s *IdentityServer) ListUsers(ctx context.Context, req *proto.ListUsersRequest) (*proto.ListUsersResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	page := database.UserPage{IncludeDeleted: req.GetIncludeDeleted(), Limit: pageSize + 1}
+	if req.GetPageToken() != "" {
+		token, err := decodeUserPageToken(req.GetPageToken())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
 		}
-		protoUsers = append(protoUsers, protoUser)
+		page.AfterCreatedAt, page.AfterID, page.AsOf = token.CreatedAt, token.ID, token.AsOf
+	} else if req.GetSnapshot() {
+		asOf := time.Now().UTC()
+		page.AsOf = &asOf
 	}
 
-	return &proto.GetUsersResponse{Users: protoUsers}, nil
+	// Fetch one extra row to know whether there is a next page
+	users, err := s.userService.ListUsers(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ListUsersResponse{}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		last := users[len(users)-1]
+		if resp.NextPageToken, err = encodeUserPageToken(userPageToken{CreatedAt: last.CreatedAt, ID: last.ID, AsOf: page.AsOf}); err != nil {
+			return nil, err
+		}
+	}
+	resp.Users = converters.Users(users)
+	return resp, nil
 }
 
-func (s *IdentityServer) GetUser(ctx context.Context, req *proto.GetUserRequest) (*proto.GetUserResponse, error) {
-	user, err := s.userService.FindUserByID(ctx, req.GetId())
+// SearchUsers finds active users by name or email, ranked by relevance
+func (s *IdentityServer) SearchUsers(ctx context.Context, req *proto.SearchUsersRequest) (*proto.SearchUsersResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.GetPageToken()); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	users, err := s.userService.SearchUsers(ctx, req.GetQuery(), pageSize+1, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	var permissions []string
-	for _, perm := range user.Permissions {
-		permissions = append(permissions, perm.Name)
+	resp := &proto.SearchUsersResponse{}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
 	}
+	resp.Users = converters.Users(users)
+	return resp, nil
+}
 
-	return &proto.GetUserResponse{
-		Name:        user.Name,
-		Email:       user.Email,
-		Role:        user.Role.Name,
-		RoleId:      user.Role.ID,
-		Permissions: permissions,
-		CreatedAt:   user.CreatedAt.Format("2006-01-02 15:04:05"),
-	}, nil
+func (s *IdentityServer) GetUser(ctx context.Context, req *proto.GetUserRequest) (*proto.GetUserResponse, error) {
+	user, err := s.userService.FindUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	return converters.UserDetail(&user), nil
 }
 
 func (s *IdentityServer) StoreUser(ctx context.Context, req *proto.StoreUserRequest) (*proto.StoreUserResponse, error) {
@@ -78,16 +165,636 @@ func (s *IdentityServer) StoreUser(ctx context.Context, req *proto.StoreUserRequ
 
 	storedUser, err := s.userService.StoreUser(ctx, userToStore)
 	if err != nil {
-		return nil, err
+		return nil, userError(err)
+	}
+	auditTarget(ctx, storedUser.ID)
+	auditChange(ctx, nil, map[string]string{"name": storedUser.Name, "email": storedUser.Email, "role_id": storedUser.RoleID})
+
+	// The account is created even when the email cannot be sent; SendVerificationEmail retries
+	if err := s.verificationService.SendTo(ctx, storedUser); err != nil {
+		s.logger.Warn("Failed to send verification email", zap.String("user_id", storedUser.ID), zap.Error(err))
+	}
+
+	return &proto.StoreUserResponse{User: converters.User(&storedUser)}, nil
+}
+
+func (s *IdentityServer) UpdateUser(ctx context.Context, req *proto.UpdateUserRequest) (*proto.UpdateUserResponse, error) {
+	user, changes, err := s.userService.UpdateUser(ctx, req.GetId(), services.UserUpdate{
+		Name:   req.Name,
+		Email:  req.Email,
+		RoleID: req.RoleId,
+	})
+	if err != nil {
+		return nil, userError(err)
+	}
+	before, after := make(map[string]string, len(changes)), make(map[string]string, len(changes))
+	for _, change := range changes {
+		before[change.Field], after[change.Field] = change.Before, change.After
+	}
+	auditChange(ctx, before, after)
+
+	return &proto.UpdateUserResponse{
+		User:    converters.User(&user),
+		Changes: converters.FieldChanges(changes),
+	}, nil
+}
+
+// RestoreUser reactivates a deactivated user, e.g. after an accidental deactivation
+func (s *IdentityServer) RestoreUser(ctx context.Context, req *proto.RestoreUserRequest) (*proto.RestoreUserResponse, error) {
+	user, err := s.userService.RestoreUser(ctx, req.GetId())
+	if err != nil {
+		return nil, userError(err)
+	}
+	auditChange(ctx, map[string]string{"status": "deactivated"}, map[string]string{"status": "active"})
+
+	return &proto.RestoreUserResponse{User: converters.User(&user)}, nil
+}
+
+// userError maps user write errors to gRPC status codes
+func userError(err error) error {
+	var validationErr *model.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return shared.ValidationStatus(err)
+	case errors.Is(err, services.ErrUserNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_USER_NOT_FOUND, err.Error())
+	case errors.Is(err, services.ErrRoleNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_ROLE_NOT_FOUND, err.Error())
+	case errors.Is(err, services.ErrUserNotDeleted):
+		return shared.ReasonError(codes.FailedPrecondition, proto.ErrorReason_USER_NOT_DELETED, err.Error())
+	case errors.Is(err, database.ErrEmailTaken):
+		return shared.ReasonError(codes.AlreadyExists, proto.ErrorReason_EMAIL_ALREADY_EXISTS, err.Error())
+	case errors.Is(err, model.ErrVersionConflict):
+		return shared.ReasonError(codes.Aborted, proto.ErrorReason_VERSION_CONFLICT, err.Error())
+	case errors.Is(err, model.ErrTenantMismatch):
+		return shared.ReasonError(codes.PermissionDenied, proto.ErrorReason_TENANT_MISMATCH, err.Error())
+	default:
+		return err
 	}
+}
+
+func (s *IdentityServer) ExportUsers(req *proto.ExportUsersRequest, stream grpc.ServerStreamingServer[proto.User]) error {
+	// Send blocks while the client's flow-control window is full, which holds
+	// back reading the next batch from the database
+	return s.userService.ExportUsers(stream.Context(), req.GetTenantId(), int(req.GetBatchSize()), req.GetSnapshot(), func(users []models.User) error {
+		for i := range users {
+			if err := stream.Send(converters.User(&users[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-	return &proto.StoreUserResponse{
-		User: &proto.User{
-			Id:        storedUser.ID,
-			Name:      storedUser.Name,
-			Email:     storedUser.Email,
-			Role:      storedUser.Role.Name,
-			CreatedAt: storedUser.CreatedAt.Format("2006-01-02 15:04:05"),
+// BulkImportUsers creates the valid users of the request, reporting the violations
+// of the others, to migrate tenants from other systems
+func (s *IdentityServer) BulkImportUsers(ctx context.Context, req *proto.BulkImportUsersRequest) (*proto.BulkImportUsersResponse, error) {
+	rows := make([]services.ImportRow, len(req.GetUsers()))
+	for i, user := range req.GetUsers() {
+		rows[i].User = models.User{
+			Name:     user.GetName(),
+			Email:    user.GetEmail(),
+			Password: user.GetPasswordHash(),
+			RoleID:   user.GetRoleId(),
+		}
+		rows[i].User.TenantID = req.GetTenantId()
+
+		var validationErr *model.ValidationError
+		if errors.As(user.Validate(), &validationErr) {
+			rows[i].Violations = validationErr.Fields
+		}
+	}
+
+	imported, err := s.userService.ImportUsers(ctx, rows, req.GetDryRun())
+	if err != nil {
+		return nil, userError(err)
+	}
+	auditTarget(ctx, req.GetTenantId())
+	if !req.GetDryRun() {
+		auditChange(ctx, nil, map[string]int{"imported": imported, "rejected": len(rows) - imported})
+	}
+
+	resp := &proto.BulkImportUsersResponse{
+		Results:  make([]*proto.ImportUserResult, len(rows)),
+		Imported: int32(imported),
+	}
+	for i, row := range rows {
+		result := &proto.ImportUserResult{Index: int32(i)}
+		if len(row.Violations) > 0 {
+			resp.Rejected++
+			for _, violation := range row.Violations {
+				result.Violations = append(result.Violations, &proto.ImportViolation{Field: violation.Field, Rule: violation.Rule})
+			}
+		} else if !req.GetDryRun() {
+			result.Id = row.User.ID
+		}
+		resp.Results[i] = result
+	}
+	return resp, nil
+}
+
+func (s *IdentityServer) ScheduleDeactivation(ctx context.Context, req *proto.ScheduleDeactivationRequest) (*proto.ScheduleDeactivationResponse, error) {
+	deactivateAt, err := time.Parse(time.RFC3339, req.GetDeactivateAt())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "deactivate_at must be an RFC 3339 timestamp")
+	}
+
+	user, err := s.deprovisioningService.Schedule(ctx, req.GetId(), deactivateAt)
+	if err != nil {
+		return nil, deprovisioningError(err)
+	}
+	auditChange(ctx, nil, map[string]string{"deactivate_at": req.GetDeactivateAt()})
+
+	return &proto.ScheduleDeactivationResponse{User: converters.User(&user)}, nil
+}
+
+func (s *IdentityServer) CancelDeactivation(ctx context.Context, req *proto.CancelDeactivationRequest) (*empty.Empty, error) {
+	if err := s.deprovisioningService.Cancel(ctx, req.GetId()); err != nil {
+		return nil, deprovisioningError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// RequestPasswordReset sends a reset token to the user owning the email. It succeeds
+// for unknown emails too, so callers cannot probe which accounts exist.
+func (s *IdentityServer) RequestPasswordReset(ctx context.Context, req *proto.RequestPasswordResetRequest) (*empty.Empty, error) {
+	if err := s.passwordResetService.Request(ctx, req.GetEmail()); err != nil {
+		return nil, passwordResetError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *IdentityServer) ConfirmPasswordReset(ctx context.Context, req *proto.ConfirmPasswordResetRequest) (*empty.Empty, error) {
+	userID, err := s.passwordResetService.Confirm(ctx, req.GetToken(), req.GetNewPassword())
+	if err != nil {
+		return nil, passwordResetError(err)
+	}
+	auditTarget(ctx, userID)
+
+	return &empty.Empty{}, nil
+}
+
+// ChangePassword changes the password of the authenticated user
+func (s *IdentityServer) ChangePassword(ctx context.Context, req *proto.ChangePasswordRequest) (*empty.Empty, error) {
+	user, ok := shared.UserFromContext(ctx)
+	if !ok {
+		return nil, shared.ReasonError(codes.Unauthenticated, proto.ErrorReason_TOKEN_MISSING, shared.ErrTokenMissing.Error())
+	}
+
+	if err := s.passwordResetService.Change(ctx, user.ID, req.GetCurrentPassword(), req.GetNewPassword()); err != nil {
+		return nil, passwordResetError(err)
+	}
+	auditTarget(ctx, user.ID)
+
+	return &empty.Empty{}, nil
+}
+
+// passwordResetError maps password reset errors to gRPC status codes
+func passwordResetError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrResetRateLimited):
+		return shared.ReasonError(codes.ResourceExhausted, proto.ErrorReason_RESET_RATE_LIMITED, err.Error())
+	case errors.Is(err, services.ErrResetTokenInvalid):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_RESET_TOKEN_INVALID, err.Error())
+	case errors.Is(err, services.ErrPasswordTooShort):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_PASSWORD_TOO_SHORT, err.Error())
+	case errors.Is(err, services.ErrSamePassword):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_SAME_PASSWORD, err.Error())
+	case errors.Is(err, services.ErrChangeRateLimited):
+		return shared.ReasonError(codes.ResourceExhausted, proto.ErrorReason_PASSWORD_CHANGE_RATE_LIMITED, err.Error())
+	case errors.Is(err, services.ErrWrongPassword):
+		return shared.ReasonError(codes.PermissionDenied, proto.ErrorReason_WRONG_PASSWORD, err.Error())
+	case errors.Is(err, services.ErrUserNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_USER_NOT_FOUND, err.Error())
+	default:
+		return err
+	}
+}
+
+// SendVerificationEmail sends a new verification token to the email. It succeeds for
+// unknown and already verified emails too, so callers cannot probe which accounts exist.
+func (s *IdentityServer) SendVerificationEmail(ctx context.Context, req *proto.SendVerificationEmailRequest) (*empty.Empty, error) {
+	if err := s.verificationService.Send(ctx, req.GetEmail()); err != nil {
+		return nil, verificationError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *IdentityServer) VerifyEmail(ctx context.Context, req *proto.VerifyEmailRequest) (*empty.Empty, error) {
+	userID, err := s.verificationService.Verify(ctx, req.GetToken())
+	if err != nil {
+		return nil, verificationError(err)
+	}
+	auditTarget(ctx, userID)
+
+	return &empty.Empty{}, nil
+}
+
+// verificationError maps email verification errors to gRPC status codes
+func verificationError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrVerificationRateLimited):
+		return shared.ReasonError(codes.ResourceExhausted, proto.ErrorReason_VERIFICATION_RATE_LIMITED, err.Error())
+	case errors.Is(err, services.ErrVerificationTokenInvalid):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_VERIFICATION_TOKEN_INVALID, err.Error())
+	default:
+		return err
+	}
+}
+
+// deprovisioningError maps scheduled deactivation errors to gRPC status codes
+func deprovisioningError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_USER_NOT_FOUND, err.Error())
+	case errors.Is(err, services.ErrDeactivationInPast):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_DEACTIVATION_IN_PAST, err.Error())
+	case errors.Is(err, services.ErrNoDeactivationScheduled):
+		return shared.ReasonError(codes.FailedPrecondition, proto.ErrorReason_NO_DEACTIVATION_SCHEDULED, err.Error())
+	default:
+		return err
+	}
+}
+
+func (s *IdentityServer) ExportConfig(ctx context.Context, empty *empty.Empty) (*proto.ExportConfigResponse, error) {
+	bundle, payload, signature, err := s.configService.ExportConfig(ctx)
+	if err != nil {
+		return nil, configError(err)
+	}
+
+	return &proto.ExportConfigResponse{
+		Bundle: &proto.ConfigBundle{
+			Payload:    payload,
+			Signature:  signature,
+			ExportedAt: converters.Time(bundle.ExportedAt),
 		},
 	}, nil
 }
+
+func (s *IdentityServer) ImportConfig(ctx context.Context, req *proto.ImportConfigRequest) (*proto.ImportConfigResponse, error) {
+	bundle := req.GetBundle()
+	if bundle == nil {
+		return nil, status.Error(codes.InvalidArgument, "bundle is required")
+	}
+
+	plan, err := s.configService.ImportConfig(ctx, bundle.GetPayload(), bundle.GetSignature(), req.GetDryRun(), req.GetPrune())
+	if err != nil {
+		return nil, configError(err)
+	}
+
+	changes := make([]string, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+	auditChange(ctx, nil, map[string]any{"changes": changes, "dry_run": req.GetDryRun(), "prune": req.GetPrune()})
+
+	return &proto.ImportConfigResponse{
+		Changes: changes,
+		Applied: !req.GetDryRun() && !plan.Empty(),
+	}, nil
+}
+
+func (s *IdentityServer) GetSensitiveFields(ctx context.Context, req *proto.GetSensitiveFieldsRequest) (*proto.SensitiveFieldsResponse, error) {
+	return &proto.SensitiveFieldsResponse{Fields: s.sensitiveFields.ForTenant(sensitiveFieldsTenant(ctx, req.GetTenantId()))}, nil
+}
+
+// UpdateSensitiveFields changes the registry used for log redaction. Changes are
+// kept in memory; persist them in SENSITIVE_FIELDS_FILE to survive restarts.
+func (s *IdentityServer) UpdateSensitiveFields(ctx context.Context, req *proto.UpdateSensitiveFieldsRequest) (*proto.SensitiveFieldsResponse, error) {
+	tenantID := sensitiveFieldsTenant(ctx, req.GetTenantId())
+	before := s.sensitiveFields.ForTenant(tenantID)
+	s.sensitiveFields.Add(tenantID, req.GetAdd()...)
+	s.sensitiveFields.Remove(tenantID, req.GetRemove()...)
+	after := s.sensitiveFields.ForTenant(tenantID)
+	auditTarget(ctx, tenantID)
+	auditChange(ctx, before, after)
+
+	s.logger.Info("Sensitive fields updated",
+		zap.String("tenant_id", tenantID),
+		zap.Strings("added", req.GetAdd()),
+		zap.Strings("removed", req.GetRemove()),
+	)
+	return &proto.SensitiveFieldsResponse{Fields: after}, nil
+}
+
+// sensitiveFieldsTenant confines callers of a tenant to their own fields; only
+// callers without tenant choose the tenant, or every tenant when empty
+func sensitiveFieldsTenant(ctx context.Context, requested string) string {
+	if tenantID := shared.TenantFromContext(ctx); tenantID != "" {
+		return tenantID
+	}
+	return requested
+}
+
+// GetSLOStatus reports error budgets and burn rates of the SLOs declared in SLO_FILE
+func (s *IdentityServer) GetSLOStatus(ctx context.Context, empty *empty.Empty) (*proto.SLOStatusResponse, error) {
+	return converters.SLOStatus(s.slo.Status(), s.slo.Window()), nil
+}
+
+// GetVersion reports the build of the running server, so clients can check compatibility
+func (s *IdentityServer) GetVersion(ctx context.Context, empty *empty.Empty) (*proto.VersionResponse, error) {
+	return &proto.VersionResponse{
+		Service:   s.build.Service,
+		Version:   s.build.Version,
+		Commit:    s.build.Commit,
+		BuildTime: s.build.BuildTime,
+		GoVersion: s.build.GoVersion,
+	}, nil
+}
+
+// configError maps configuration bundle errors to gRPC status codes
+func configError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrConfigSigningKeyMissing):
+		return shared.ReasonError(codes.FailedPrecondition, proto.ErrorReason_CONFIG_SIGNING_KEY_MISSING, err.Error())
+	case errors.Is(err, rbac.ErrInvalidBundleSignature):
+		return shared.ReasonError(codes.PermissionDenied, proto.ErrorReason_BUNDLE_SIGNATURE_INVALID, err.Error())
+	case errors.Is(err, rbac.ErrInvalidBundle):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_BUNDLE_INVALID, err.Error())
+	case errors.Is(err, rbac.ErrPlanChanged):
+		return shared.ReasonError(codes.Aborted, proto.ErrorReason_PLAN_CHANGED, err.Error())
+	default:
+		return err
+	}
+}
+
+// CreateTenant registers a tenant; only callers without a tenant may create one
+func (s *IdentityServer) CreateTenant(ctx context.Context, req *proto.CreateTenantRequest) (*proto.Tenant, error) {
+	tenant, err := s.tenantService.Create(ctx, models.Tenant{ID: req.GetId(), Name: req.GetName()})
+	if err != nil {
+		return nil, tenantError(err)
+	}
+	auditChange(ctx, nil, map[string]string{"name": tenant.Name})
+
+	return converters.Tenant(&tenant), nil
+}
+
+func (s *IdentityServer) GetTenant(ctx context.Context, req *proto.GetTenantRequest) (*proto.Tenant, error) {
+	tenant, err := s.tenantService.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, tenantError(err)
+	}
+
+	return converters.Tenant(&tenant), nil
+}
+
+// ListTenants pages through the tenants; callers of a tenant only get their own
+func (s *IdentityServer) ListTenants(ctx context.Context, req *proto.ListTenantsRequest) (*proto.ListTenantsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.GetPageToken()); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	tenants, err := s.tenantService.List(ctx, pageSize+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ListTenantsResponse{}
+	if len(tenants) > pageSize {
+		tenants = tenants[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	for i := range tenants {
+		resp.Tenants = append(resp.Tenants, converters.Tenant(&tenants[i]))
+	}
+	return resp, nil
+}
+
+func (s *IdentityServer) UpdateTenant(ctx context.Context, req *proto.UpdateTenantRequest) (*proto.Tenant, error) {
+	tenant, previous, err := s.tenantService.Rename(ctx, req.GetId(), req.GetName())
+	if err != nil {
+		return nil, tenantError(err)
+	}
+	auditChange(ctx, map[string]string{"name": previous}, map[string]string{"name": tenant.Name})
+
+	return converters.Tenant(&tenant), nil
+}
+
+// tenantError maps tenant management errors to gRPC status codes
+func tenantError(err error) error {
+	var validationErr *model.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return shared.ValidationStatus(err)
+	case errors.Is(err, services.ErrTenantNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_TENANT_NOT_FOUND, err.Error())
+	case errors.Is(err, services.ErrTenantExists):
+		return shared.ReasonError(codes.AlreadyExists, proto.ErrorReason_TENANT_ALREADY_EXISTS, err.Error())
+	case errors.Is(err, model.ErrTenantMismatch):
+		return shared.ReasonError(codes.PermissionDenied, proto.ErrorReason_TENANT_MISMATCH, err.Error())
+	default:
+		return err
+	}
+}
+
+func (s *IdentityServer) ReassignRole(req *proto.ReassignRoleRequest, stream grpc.ServerStreamingServer[proto.Operation]) error {
+	ctx := stream.Context()
+
+	var op *models.Operation
+	var err error
+	if req.GetOperationId() != "" {
+		op, err = s.operations.Resume(ctx, req.GetOperationId())
+	} else {
+		op, err = s.reassignmentService.Start(ctx, req.GetFromRoleId(), req.GetToRoleId(), int(req.GetBatchSize()))
+	}
+	if err != nil {
+		return operationError(err)
+	}
+	auditTarget(ctx, req.GetFromRoleId())
+	auditChange(ctx, nil, map[string]string{"to_role_id": req.GetToRoleId(), "operation_id": op.ID})
+
+	// The operation keeps running in the background if the client disconnects
+	err = s.operations.Watch(ctx, op.ID, func(op *models.Operation) error {
+		protoOp, err := converters.Operation(op)
+		if err != nil {
+			return err
+		}
+		return stream.Send(protoOp)
+	})
+	return operationError(err)
+}
+
+func (s *IdentityServer) DeprovisionTenant(req *proto.DeprovisionTenantRequest, stream grpc.ServerStreamingServer[proto.Operation]) error {
+	ctx := stream.Context()
+
+	var op *models.Operation
+	var err error
+	if req.GetOperationId() != "" {
+		op, err = s.operations.Resume(ctx, req.GetOperationId())
+	} else {
+		retention := time.Duration(req.GetRetentionDays()) * 24 * time.Hour
+		op, err = s.offboardingService.Start(ctx, req.GetTenantId(), retention)
+	}
+	if err != nil {
+		return operationError(err)
+	}
+	auditTarget(ctx, req.GetTenantId())
+	auditChange(ctx, nil, map[string]string{"operation_id": op.ID})
+
+	err = s.operations.Watch(ctx, op.ID, func(op *models.Operation) error {
+		protoOp, err := converters.Operation(op)
+		if err != nil {
+			return err
+		}
+		return stream.Send(protoOp)
+	})
+	return operationError(err)
+}
+
+func (s *IdentityServer) GetOperation(ctx context.Context, req *proto.GetOperationRequest) (*proto.Operation, error) {
+	op, err := s.operations.Get(ctx, req.GetId())
+	if err != nil {
+		return nil, operationError(err)
+	}
+
+	return converters.Operation(op)
+}
+
+func (s *IdentityServer) ListOperations(ctx context.Context, req *proto.ListOperationsRequest) (*proto.ListOperationsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.GetPageToken()); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	ops, err := s.operations.List(ctx, req.GetKind(), pageSize+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ListOperationsResponse{}
+	if len(ops) > pageSize {
+		ops = ops[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	for i := range ops {
+		protoOp, err := converters.Operation(&ops[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Operations = append(resp.Operations, protoOp)
+	}
+
+	return resp, nil
+}
+
+func (s *IdentityServer) CancelOperation(ctx context.Context, req *proto.CancelOperationRequest) (*empty.Empty, error) {
+	if err := s.operations.Cancel(ctx, req.GetId()); err != nil {
+		return nil, operationError(err)
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (s *IdentityServer) ListAuditEvents(ctx context.Context, req *proto.ListAuditEventsRequest) (*proto.ListAuditEventsResponse, error) {
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := 0
+	if req.GetPageToken() != "" {
+		var err error
+		if offset, err = strconv.Atoi(req.GetPageToken()); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	filter := services.AuditFilter{
+		ActorID:  req.GetActorId(),
+		TargetID: req.GetTargetId(),
+		Action:   req.GetAction(),
+	}
+	var err error
+	if filter.Since, err = parseTimestamp(req.GetSince()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "since must be an RFC 3339 timestamp")
+	}
+	if filter.Until, err = parseTimestamp(req.GetUntil()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "until must be an RFC 3339 timestamp")
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	events, err := s.audit.List(ctx, filter, pageSize+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ListAuditEventsResponse{}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		resp.NextPageToken = strconv.Itoa(offset + pageSize)
+	}
+	resp.Events = converters.AuditEvents(events)
+
+	return resp, nil
+}
+
+func (s *IdentityServer) GetArtifact(ctx context.Context, req *proto.GetArtifactRequest) (*proto.Artifact, error) {
+	artifact, err := s.artifacts.Get(ctx, req.GetId())
+	if errors.Is(err, artifacts.ErrNotFound) {
+		return nil, shared.ReasonError(codes.NotFound, proto.ErrorReason_ARTIFACT_NOT_FOUND, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	url, expiresAt := s.artifacts.URL(artifact)
+	return converters.Artifact(artifact, url, expiresAt), nil
+}
+
+// parseTimestamp parses an optional RFC 3339 timestamp; empty returns the zero time
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// operationError maps operation errors to gRPC status codes
+func operationError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, services.ErrSameRole):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_SAME_ROLE, err.Error())
+	case errors.Is(err, services.ErrTenantRequired):
+		return shared.ReasonError(codes.InvalidArgument, proto.ErrorReason_TENANT_REQUIRED, err.Error())
+	case errors.Is(err, services.ErrRoleNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_ROLE_NOT_FOUND, err.Error())
+	case errors.Is(err, operations.ErrNotFound):
+		return shared.ReasonError(codes.NotFound, proto.ErrorReason_OPERATION_NOT_FOUND, err.Error())
+	case errors.Is(err, operations.ErrDone):
+		return shared.ReasonError(codes.FailedPrecondition, proto.ErrorReason_OPERATION_DONE, err.Error())
+	case errors.Is(err, services.ErrTenantAlreadyOffboarded):
+		return shared.ReasonError(codes.FailedPrecondition, proto.ErrorReason_TENANT_ALREADY_OFFBOARDED, err.Error())
+	case errors.Is(err, model.ErrTenantMismatch):
+		return shared.ReasonError(codes.PermissionDenied, proto.ErrorReason_TENANT_MISMATCH, err.Error())
+	case errors.Is(err, operations.ErrUnknownKind):
+		return shared.ReasonError(codes.Unimplemented, proto.ErrorReason_OPERATION_KIND_UNKNOWN, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return err
+	}
+}
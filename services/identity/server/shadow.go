@@ -0,0 +1,13 @@
+package server
+
+import (
+	"github.com/gabehamasaki/momentum/shared"
+)
+
+// ShadowHandlers returns the alternate implementations compared against the live
+// handlers when SHADOW_SAMPLE_RATE is set, keyed by full method name. A rewrite of
+// a read path is registered here while it is rolled out, and removed once it
+// replaces the live handler; mutations must never be shadowed.
+func (s *IdentityServer) ShadowHandlers() map[string]shared.ShadowHandler {
+	return map[string]shared.ShadowHandler{}
+}
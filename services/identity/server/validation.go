@@ -0,0 +1,37 @@
+package server
+
+import (
+	"context"
+
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+)
+
+// Validation returns the request checks needing the database, so that a missing
+// role is reported together with the other invalid fields of the request
+func (s *IdentityServer) Validation() *shared.ValidationConfig {
+	return &shared.ValidationConfig{Checks: map[string]shared.RequestCheck{
+		proto.IdentityService_StoreUser_FullMethodName: func(ctx context.Context, req any, fields *model.Fields) error {
+			return s.checkRole(ctx, "role_id", req.(*proto.StoreUserRequest).GetRoleId(), fields)
+		},
+		proto.IdentityService_UpdateUser_FullMethodName: func(ctx context.Context, req any, fields *model.Fields) error {
+			return s.checkRole(ctx, "role_id", req.(*proto.UpdateUserRequest).GetRoleId(), fields)
+		},
+	}}
+}
+
+// checkRole adds an "exists" violation when the role is set but unknown
+func (s *IdentityServer) checkRole(ctx context.Context, field, id string, fields *model.Fields) error {
+	if id == "" {
+		return nil
+	}
+	exists, err := s.userService.RoleExists(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		*fields = append(*fields, model.FieldError{Field: field, Rule: "exists"})
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"go.uber.org/zap"
+)
+
+// AuditFilter narrows ListAuditEvents; empty fields match every event
+type AuditFilter struct {
+	ActorID  string
+	TargetID string
+	Action   string
+	Since    time.Time
+	Until    time.Time
+}
+
+// AuditService stores and queries the audit trail of identity mutations
+type AuditService struct {
+	db     *database.Database
+	logger *zap.Logger
+}
+
+func NewAuditService(db *database.Database, logger *zap.Logger) *AuditService {
+	return &AuditService{db: db, logger: logger}
+}
+
+// Record stores an audit event. It uses its own context so the event is kept even
+// when the caller has already gone away.
+func (s *AuditService) Record(event models.AuditEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	return conn.Create(&event).Error
+}
+
+// List returns events matching the filter, newest first
+func (s *AuditService) List(ctx context.Context, filter AuditFilter, limit, offset int) ([]models.AuditEvent, error) {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := conn.Order("created_at DESC, id DESC").Limit(limit).Offset(offset)
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+
+	var events []models.AuditEvent
+	if err := query.Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/rbac"
+	"go.uber.org/zap"
+)
+
+// ErrConfigSigningKeyMissing is returned when export/import is attempted without a signing key
+var ErrConfigSigningKeyMissing = errors.New("configuration signing key is not configured")
+
+type ConfigService struct {
+	db         *database.Database
+	logger     *zap.Logger
+	signingKey []byte
+	cache      *UserCache
+}
+
+func NewConfigService(db *database.Database, logger *zap.Logger, signingKey string, userCache *UserCache) *ConfigService {
+	return &ConfigService{db: db, logger: logger, signingKey: []byte(signingKey), cache: userCache}
+}
+
+// ExportConfig produces a signed bundle of roles and permissions
+func (s *ConfigService) ExportConfig(ctx context.Context) (*rbac.Bundle, []byte, string, error) {
+	if len(s.signingKey) == 0 {
+		return nil, nil, "", ErrConfigSigningKeyMissing
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	bundle, err := rbac.Export(ctx, conn)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	payload, signature, err := rbac.SignBundle(bundle, s.signingKey)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return bundle, payload, signature, nil
+}
+
+// ImportConfig verifies a signed bundle and reconciles the live configuration with it
+func (s *ConfigService) ImportConfig(ctx context.Context, payload []byte, signature string, dryRun, prune bool) (*rbac.Plan, error) {
+	if len(s.signingKey) == 0 {
+		return nil, ErrConfigSigningKeyMissing
+	}
+
+	bundle, err := rbac.VerifyBundle(payload, signature, s.signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := rbac.Options{Prune: prune}
+	plan, err := rbac.ComputePlan(ctx, conn, bundle.Definition(), opts)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || plan.Empty() {
+		return plan, nil
+	}
+
+	affected, err := rbac.Apply(ctx, conn, bundle.Definition(), opts, plan)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Invalidate(ctx, affected...)
+
+	s.logger.Info("Imported configuration bundle",
+		zap.Time("exported_at", bundle.ExportedAt),
+		zap.Int("changes", len(plan.Changes)),
+	)
+
+	return plan, nil
+}
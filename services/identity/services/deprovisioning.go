@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/operations"
+	"github.com/gabehamasaki/momentum/services/identity/outbox"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// OperationUserDeactivation is the operation kind that deactivates users whose schedule is due
+	OperationUserDeactivation = "user_deactivation"
+
+	deactivationBatchSize = 100
+)
+
+var (
+	ErrUserNotFound            = errors.New("user not found")
+	ErrDeactivationInPast      = errors.New("deactivation must be scheduled in the future")
+	ErrNoDeactivationScheduled = errors.New("no deactivation scheduled for this user")
+	ErrUserNotDeleted          = errors.New("user is not deactivated")
+)
+
+// DeprovisioningService schedules user deactivations and executes them through
+// the operations subsystem once they are due
+type DeprovisioningService struct {
+	db         *database.Database
+	logger     *zap.Logger
+	operations *operations.Manager
+	cache      *UserCache
+}
+
+// NewDeprovisioningService creates the service and registers its operation kind
+func NewDeprovisioningService(db *database.Database, logger *zap.Logger, manager *operations.Manager, userCache *UserCache) *DeprovisioningService {
+	s := &DeprovisioningService{db: db, logger: logger, operations: manager, cache: userCache}
+	manager.Register(OperationUserDeactivation, s.run)
+	return s
+}
+
+// Schedule sets the time at which the user will be deactivated, replacing any previous schedule
+func (s *DeprovisioningService) Schedule(ctx context.Context, userID string, at time.Time) (models.User, error) {
+	if !at.After(time.Now()) {
+		return models.User{}, ErrDeactivationInPast
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	if err := conn.Preload("Role").First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, err
+	}
+
+	at = at.UTC()
+	if err := conn.Model(&user).Update("schedule_deactivation_at", at).Error; err != nil {
+		return models.User{}, err
+	}
+	user.ScheduleDeactivationAt = &at
+	s.cache.Invalidate(ctx, user.ID)
+
+	s.logger.Info("User deactivation scheduled", zap.String("user_id", user.ID), zap.Time("deactivate_at", at))
+	return user, nil
+}
+
+// Cancel removes a pending deactivation
+func (s *DeprovisioningService) Cancel(ctx context.Context, userID string) error {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := conn.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if user.ScheduleDeactivationAt == nil {
+		return ErrNoDeactivationScheduled
+	}
+
+	if err := conn.Model(&user).Update("schedule_deactivation_at", nil).Error; err != nil {
+		return err
+	}
+	s.cache.Invalidate(ctx, user.ID)
+
+	s.logger.Info("User deactivation cancelled", zap.String("user_id", user.ID))
+	return nil
+}
+
+// Run periodically starts a deactivation operation when schedules are due, until ctx ends
+func (s *DeprovisioningService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.startDue(ctx); err != nil {
+				s.logger.Error("Failed to start scheduled deactivations", shared.ErrorSource(shared.ErrorSourceJob), zap.Error(err))
+			}
+		}
+	}
+}
+
+// startDue starts an operation for the due users unless one is already running
+func (s *DeprovisioningService) startDue(ctx context.Context) error {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var running int64
+	if err := conn.Model(&models.Operation{}).
+		Where("kind = ? AND status = ?", OperationUserDeactivation, models.OperationRunning).
+		Count(&running).Error; err != nil {
+		return err
+	}
+	if running > 0 {
+		return nil
+	}
+
+	var due int64
+	if err := conn.Model(&models.User{}).Where("schedule_deactivation_at <= ?", time.Now().UTC()).Count(&due).Error; err != nil {
+		return err
+	}
+	if due == 0 {
+		return nil
+	}
+
+	_, err = s.operations.Start(ctx, OperationUserDeactivation, due, nil)
+	return err
+}
+
+// run deactivates due users in batches
+func (s *DeprovisioningService) run(ctx context.Context, op *operations.Handle) (any, error) {
+	for {
+		if err := op.Checkpoint(ctx); err != nil {
+			return nil, err
+		}
+
+		conn, err := op.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		var users []models.User
+		var userIDs []string
+		err = conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Select("id", "tenant_id").
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("schedule_deactivation_at <= ?", time.Now().UTC()).
+				Order("schedule_deactivation_at").
+				Limit(deactivationBatchSize).
+				Find(&users).Error; err != nil {
+				return fmt.Errorf("failed to select due users: %w", err)
+			}
+			if len(users) == 0 {
+				return nil
+			}
+
+			userIDs = make([]string, len(users))
+			for i, user := range users {
+				userIDs[i] = user.ID
+			}
+
+			if err := tx.Where("id IN ?", userIDs).Delete(&models.User{}).Error; err != nil {
+				return fmt.Errorf("failed to deactivate users: %w", err)
+			}
+			for _, user := range users {
+				event, err := userEvent(events.UserDeleted, user.TenantID, events.UserData{UserID: user.ID})
+				if err != nil {
+					return err
+				}
+				if err := outbox.Add(tx, event); err != nil {
+					return err
+				}
+			}
+			return op.AddProgress(tx, int64(len(userIDs)))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(userIDs) == 0 {
+			return map[string]any{"deactivated": op.Operation.Processed}, nil
+		}
+		s.cache.Invalidate(ctx, userIDs...)
+
+		for _, user := range users {
+			s.logger.Info("User deactivated by schedule", zap.String("user_id", user.ID))
+		}
+	}
+}
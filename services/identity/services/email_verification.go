@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultEmailVerificationTTL is how long a verification token stays valid
+	DefaultEmailVerificationTTL = 24 * time.Hour
+
+	// DefaultEmailVerificationLimit is the number of verification emails sent per address and hour
+	DefaultEmailVerificationLimit = 3
+)
+
+var (
+	ErrVerificationRateLimited  = errors.New("too many verification emails, try again later")
+	ErrVerificationTokenInvalid = errors.New("email verification token is invalid or expired")
+)
+
+// VerificationNotifier delivers email verification tokens to users
+type VerificationNotifier interface {
+	SendEmailVerification(ctx context.Context, user models.User, token string, expiresAt time.Time) error
+}
+
+// EmailVerificationService issues and redeems single-use email verification tokens
+type EmailVerificationService struct {
+	db       *database.Database
+	logger   *zap.Logger
+	notifier VerificationNotifier
+	ttl      time.Duration
+	limiter  *emailLimiter
+	cache    *UserCache
+}
+
+// NewEmailVerificationService creates the service; limit is the number of emails
+// sent per address each hour
+func NewEmailVerificationService(db *database.Database, logger *zap.Logger, notifier VerificationNotifier, ttl time.Duration, limit int, userCache *UserCache) *EmailVerificationService {
+	if ttl <= 0 {
+		ttl = DefaultEmailVerificationTTL
+	}
+	if limit <= 0 {
+		limit = DefaultEmailVerificationLimit
+	}
+	return &EmailVerificationService{
+		db:       db,
+		logger:   logger,
+		notifier: notifier,
+		ttl:      ttl,
+		limiter:  newEmailLimiter(limit, time.Hour),
+		cache:    userCache,
+	}
+}
+
+// Send emails a verification token to the address. Unknown and already verified
+// addresses succeed silently so the RPC cannot be used to discover accounts.
+func (s *EmailVerificationService) Send(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !s.limiter.Allow(email) {
+		return ErrVerificationRateLimited
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var user models.User
+	if err := conn.First(&user, "lower(email) = ?", email).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return s.SendTo(ctx, user)
+}
+
+// SendTo issues a token for the user, e.g. right after signup
+func (s *EmailVerificationService) SendTo(ctx context.Context, user models.User) error {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return err
+	}
+	verification := models.EmailVerification{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := conn.Create(&verification).Error; err != nil {
+		return err
+	}
+
+	if err := s.notifier.SendEmailVerification(ctx, user, token, verification.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to send email verification: %w", err)
+	}
+	return nil
+}
+
+// Verify marks the email of the token owner as verified and invalidates every
+// pending token of the user, returning the user ID
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) (string, error) {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var userID string
+	err = conn.Transaction(func(tx *gorm.DB) error {
+		var verification models.EmailVerification
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&verification, "token_hash = ?", hashToken(token)).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrVerificationTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+			return ErrVerificationTokenInvalid
+		}
+
+		now := time.Now()
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND email_verified_at IS NULL", verification.UserID).
+			Update("email_verified_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if err := tx.Model(&models.EmailVerification{}).
+			Where("user_id = ? AND used_at IS NULL", verification.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		s.logger.Info("Email verified", zap.String("user_id", verification.UserID))
+		userID = verification.UserID
+		return nil
+	})
+	if err == nil {
+		s.cache.Invalidate(ctx, userID)
+	}
+	return userID, err
+}
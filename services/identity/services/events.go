@@ -0,0 +1,14 @@
+package services
+
+import (
+	"github.com/gabehamasaki/momentum/shared/events"
+)
+
+// EventSource is the source of the events published by identity
+const EventSource = "identity"
+
+// userEvent builds a user event, to be written to the outbox in the transaction
+// of the change it describes
+func userEvent(eventType, tenantID string, data events.UserData) (events.Event, error) {
+	return events.New(EventSource, eventType, tenantID, data)
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"gorm.io/gorm"
+)
+
+var errEventsUnavailable = errors.New("outbox unavailable")
+
+// fakeUserRepository is an in-memory UserRepository. Transactions roll the users
+// and events back when fn fails; methods the tests do not use panic through the
+// nil embedded interface.
+type fakeUserRepository struct {
+	database.UserRepository
+
+	users  map[string]models.User
+	roles  map[string]models.Role
+	events []events.Event
+
+	// invalidated lists the users whose email verifications were invalidated
+	invalidated []string
+	// failEvents makes AddEvent and AddEvents fail, to test rollbacks
+	failEvents bool
+	nextID     int
+}
+
+func newFakeUserRepository(roles ...models.Role) *fakeUserRepository {
+	f := &fakeUserRepository{users: make(map[string]models.User), roles: make(map[string]models.Role)}
+	for _, role := range roles {
+		f.roles[role.ID] = role
+	}
+	return f
+}
+
+// add stores a user as is, for setting up a test
+func (f *fakeUserRepository) add(user models.User) {
+	f.users[user.ID] = user
+}
+
+// stored returns the user as persisted, including deleted ones
+func (f *fakeUserRepository) stored(t *testing.T, id string) models.User {
+	t.Helper()
+	user, ok := f.users[id]
+	if !ok {
+		t.Fatalf("user %s was not stored", id)
+	}
+	return user
+}
+
+func (f *fakeUserRepository) Transaction(ctx context.Context, fn func(users database.UserRepository) error) error {
+	users, eventCount := maps.Clone(f.users), len(f.events)
+	if err := fn(f); err != nil {
+		f.users, f.events = users, f.events[:eventCount]
+		return err
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) FindByID(ctx context.Context, id string) (models.User, error) {
+	user, err := f.FindWithDeleted(ctx, id)
+	if err != nil || user.DeletedAt.Valid {
+		return models.User{}, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (f *fakeUserRepository) FindWithDeleted(ctx context.Context, id string) (models.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return models.User{}, gorm.ErrRecordNotFound
+	}
+	user.Role = f.roles[user.RoleID]
+	return user, nil
+}
+
+func (f *fakeUserRepository) Restore(ctx context.Context, user *models.User) error {
+	if f.emailTaken(user) {
+		return database.ErrEmailTaken
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	user.ScheduleDeactivationAt = nil
+	f.users[user.ID] = *user
+	return nil
+}
+
+func (f *fakeUserRepository) Create(ctx context.Context, user *models.User) error {
+	if user.ID == "" {
+		f.nextID++
+		user.ID = "user-" + strconv.Itoa(f.nextID)
+	}
+	if f.emailTaken(user) {
+		return database.ErrEmailTaken
+	}
+	f.users[user.ID] = *user
+	return nil
+}
+
+func (f *fakeUserRepository) CreateBatch(ctx context.Context, users []*models.User, batchSize int) error {
+	for _, user := range users {
+		if err := f.Create(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) FindEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	taken := make(map[string]bool)
+	for _, user := range f.users {
+		if email := strings.ToLower(user.Email); !user.DeletedAt.Valid && slices.Contains(emails, email) {
+			taken[email] = true
+		}
+	}
+	return taken, nil
+}
+
+// emailTaken reports whether another active user of the tenant has the email,
+// like the idx_users_tenant_email unique index
+func (f *fakeUserRepository) emailTaken(user *models.User) bool {
+	for _, other := range f.users {
+		if other.ID != user.ID && !other.DeletedAt.Valid && other.TenantID == user.TenantID && strings.EqualFold(other.Email, user.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateColumns only persists the listed columns, like the GORM repository
+func (f *fakeUserRepository) UpdateColumns(ctx context.Context, user *models.User, columns []string) error {
+	stored := f.users[user.ID]
+	for _, column := range columns {
+		switch column {
+		case "name":
+			stored.Name = user.Name
+		case "email":
+			if f.emailTaken(user) {
+				return database.ErrEmailTaken
+			}
+			stored.Email = user.Email
+		case "email_verified_at":
+			stored.EmailVerifiedAt = user.EmailVerifiedAt
+		case "role_id":
+			stored.RoleID = user.RoleID
+		case "version":
+			if stored.Version != user.Version {
+				return model.ErrVersionConflict
+			}
+			user.Version++
+			stored.Version = user.Version
+		default:
+			return errors.New("unexpected column " + column)
+		}
+	}
+	f.users[user.ID] = stored
+	return nil
+}
+
+func (f *fakeUserRepository) ReplacePermissions(ctx context.Context, user *models.User, permissions []*models.Permission) error {
+	stored := f.users[user.ID]
+	stored.Permissions = permissions
+	f.users[user.ID] = stored
+	user.Permissions = permissions
+	return nil
+}
+
+func (f *fakeUserRepository) FindRole(ctx context.Context, id string) (models.Role, error) {
+	role, ok := f.roles[id]
+	if !ok {
+		return models.Role{}, gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+func (f *fakeUserRepository) InvalidateEmailVerifications(ctx context.Context, userID string) error {
+	f.invalidated = append(f.invalidated, userID)
+	return nil
+}
+
+func (f *fakeUserRepository) AddEvent(ctx context.Context, event events.Event) error {
+	return f.AddEvents(ctx, []events.Event{event})
+}
+
+func (f *fakeUserRepository) AddEvents(ctx context.Context, batch []events.Event) error {
+	if f.failEvents {
+		return errEventsUnavailable
+	}
+	f.events = append(f.events, batch...)
+	return nil
+}
+
+// eventData decodes the payloads of the recorded events of eventType
+func (f *fakeUserRepository) eventData(t *testing.T, eventType string) []events.UserData {
+	t.Helper()
+	var data []events.UserData
+	for _, event := range f.events {
+		if event.Type != eventType {
+			continue
+		}
+		var d events.UserData
+		if err := json.Unmarshal(event.Data, &d); err != nil {
+			t.Fatalf("decoding %s event: %v", eventType, err)
+		}
+		data = append(data, d)
+	}
+	return data
+}
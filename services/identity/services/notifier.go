@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"go.uber.org/zap"
+)
+
+// LogNotifier only logs that a token was issued; meant for development, where the
+// token can be read from the debug log
+type LogNotifier struct {
+	Logger *zap.Logger
+}
+
+func (n LogNotifier) SendPasswordReset(ctx context.Context, user models.User, token string, expiresAt time.Time) error {
+	n.Logger.Info("Password reset requested", zap.String("user_id", user.ID), zap.Time("expires_at", expiresAt))
+	n.Logger.Debug("Password reset token issued", zap.String("user_id", user.ID), zap.String("reset_token", token))
+	return nil
+}
+
+func (n LogNotifier) SendEmailVerification(ctx context.Context, user models.User, token string, expiresAt time.Time) error {
+	n.Logger.Info("Email verification requested", zap.String("user_id", user.ID), zap.Time("expires_at", expiresAt))
+	n.Logger.Debug("Email verification token issued", zap.String("user_id", user.ID), zap.String("verification_token", token))
+	return nil
+}
+
+// WebhookNotifier posts tokens as JSON to an external sender (email service, automation);
+// the type field tells password resets from email verifications
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func (n WebhookNotifier) SendPasswordReset(ctx context.Context, user models.User, token string, expiresAt time.Time) error {
+	return n.post(ctx, "password_reset", user, token, expiresAt)
+}
+
+func (n WebhookNotifier) SendEmailVerification(ctx context.Context, user models.User, token string, expiresAt time.Time) error {
+	return n.post(ctx, "email_verification", user, token, expiresAt)
+}
+
+func (n WebhookNotifier) post(ctx context.Context, kind string, user models.User, token string, expiresAt time.Time) error {
+	body, err := json.Marshal(map[string]any{
+		"type":       kind,
+		"user_id":    user.ID,
+		"name":       user.Name,
+		"email":      user.Email,
+		"token":      token,
+		"expires_at": expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook returned status %d", kind, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/utils"
+	"github.com/gabehamasaki/momentum/shared"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// DefaultPasswordResetTTL is how long a reset token stays valid
+	DefaultPasswordResetTTL = 30 * time.Minute
+
+	// DefaultPasswordResetLimit is the number of reset requests accepted per email and window
+	DefaultPasswordResetLimit = 3
+
+	// maxPasswordChangeFailures is the number of wrong current passwords accepted per user and window
+	maxPasswordChangeFailures = 5
+
+	passwordResetWindow = time.Hour
+	minPasswordLength   = 8
+	tokenBytes          = 32
+)
+
+var (
+	ErrResetRateLimited  = errors.New("too many password reset requests, try again later")
+	ErrResetTokenInvalid = errors.New("password reset token is invalid or expired")
+	ErrPasswordTooShort  = fmt.Errorf("password must have at least %d characters", minPasswordLength)
+	ErrWrongPassword     = errors.New("current password is incorrect")
+	ErrSamePassword      = errors.New("new password must differ from the current one")
+	ErrChangeRateLimited = errors.New("too many wrong passwords, try again later")
+)
+
+// ResetNotifier delivers password reset tokens to users (e.g. by email or webhook)
+type ResetNotifier interface {
+	SendPasswordReset(ctx context.Context, user models.User, token string, expiresAt time.Time) error
+}
+
+// PasswordResetService issues and redeems single-use password reset tokens
+type PasswordResetService struct {
+	db       *database.Database
+	logger   *zap.Logger
+	notifier ResetNotifier
+	ttl      time.Duration
+	limiter  *emailLimiter
+	failures *emailLimiter
+	cache    *UserCache
+}
+
+// NewPasswordResetService creates the service; limit is the number of requests
+// accepted per email each hour
+func NewPasswordResetService(db *database.Database, logger *zap.Logger, notifier ResetNotifier, ttl time.Duration, limit int, userCache *UserCache) *PasswordResetService {
+	if ttl <= 0 {
+		ttl = DefaultPasswordResetTTL
+	}
+	return &PasswordResetService{
+		db:       db,
+		logger:   logger,
+		notifier: notifier,
+		ttl:      ttl,
+		limiter:  newEmailLimiter(limit, passwordResetWindow),
+		failures: newEmailLimiter(maxPasswordChangeFailures, passwordResetWindow),
+		cache:    userCache,
+	}
+}
+
+// Request issues a reset token and sends it to the user. Unknown emails succeed
+// silently so the RPC cannot be used to discover accounts.
+func (s *PasswordResetService) Request(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !s.limiter.Allow(email) {
+		return ErrResetRateLimited
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Emails are unique per tenant only; without x-tenant-id the same email may
+	// match accounts of several tenants, and none of them is picked
+	var users []models.User
+	if err := conn.Limit(2).Find(&users, "lower(email) = ?", email).Error; err != nil {
+		return err
+	}
+	if len(users) != 1 {
+		if len(users) > 1 {
+			s.logger.Warn("Password reset refused, email matches several accounts", zap.String("tenant_id", shared.TenantFromContext(ctx)))
+		}
+		return nil
+	}
+	user := users[0]
+
+	token, tokenHash, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	reset := models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := conn.Create(&reset).Error; err != nil {
+		return err
+	}
+
+	if err := s.notifier.SendPasswordReset(ctx, user, token, reset.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to send password reset: %w", err)
+	}
+	return nil
+}
+
+// Confirm sets a new password using a reset token and invalidates every pending
+// reset token and every bearer token of the user, returning the user ID
+func (s *PasswordResetService) Confirm(ctx context.Context, token, password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", ErrPasswordTooShort
+	}
+	passwordHash, err := utils.Bcrypt(password)
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var userID string
+	err = conn.Transaction(func(tx *gorm.DB) error {
+		var reset models.PasswordReset
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reset, "token_hash = ?", hashToken(token)).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrResetTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+		if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+			return ErrResetTokenInvalid
+		}
+
+		result := tx.Model(&models.User{}).Where("id = ?", reset.UserID).Updates(passwordChange(passwordHash))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrResetTokenInvalid
+		}
+
+		if err := tx.Model(&models.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		s.logger.Info("Password reset completed", zap.String("user_id", reset.UserID))
+		userID = reset.UserID
+		return nil
+	})
+	if err == nil {
+		s.cache.Invalidate(ctx, userID)
+	}
+	return userID, err
+}
+
+// Change sets a new password for a signed-in user after checking the current one,
+// and invalidates every pending reset token and every bearer token of the user.
+// Users giving too many wrong current passwords are locked out for the window.
+func (s *PasswordResetService) Change(ctx context.Context, userID, current, password string) error {
+	if len(password) < minPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if current == password {
+		return ErrSamePassword
+	}
+	if s.failures.Exceeded(userID) {
+		return ErrChangeRateLimited
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = conn.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "password").First(&user, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if err := utils.CompareHashAndPassword(user.Password, current); err != nil {
+			s.failures.Allow(userID)
+			return ErrWrongPassword
+		}
+
+		passwordHash, err := utils.Bcrypt(password)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(passwordChange(passwordHash)).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", userID).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		s.logger.Info("Password changed", zap.String("user_id", userID))
+		return nil
+	})
+	if err == nil {
+		s.cache.Invalidate(ctx, userID)
+	}
+	return err
+}
+
+// passwordChange returns the columns updated by a new password; the change time
+// revokes the tokens issued before it, see UserService.CheckToken
+func passwordChange(passwordHash string) map[string]any {
+	return map[string]any{"password": passwordHash, "password_changed_at": time.Now()}
+}
+
+// newToken returns a random URL-safe token and the hash stored in its place
+func newToken() (token, hash string, err error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// emailLimiter is an in-memory sliding window limiter keyed by email; each replica
+// keeps its own counts
+type emailLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	requests  map[string][]time.Time
+	lastSweep time.Time
+}
+
+func newEmailLimiter(limit int, window time.Duration) *emailLimiter {
+	if limit <= 0 {
+		limit = DefaultPasswordResetLimit
+	}
+	return &emailLimiter{limit: limit, window: window, requests: make(map[string][]time.Time), lastSweep: time.Now()}
+}
+
+// Allow records a request and reports whether it is within the limit
+func (l *emailLimiter) Allow(key string) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	times := l.prune(key, now)
+	if len(times) >= l.limit {
+		return false
+	}
+	l.requests[key] = append(times, now)
+	return true
+}
+
+// Exceeded reports whether the key reached the limit, without recording a request
+func (l *emailLimiter) Exceeded(key string) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	return len(l.prune(key, now)) >= l.limit
+}
+
+// prune drops the expired entries of key and returns the ones left
+func (l *emailLimiter) prune(key string, now time.Time) []time.Time {
+	times := l.requests[key]
+	cutoff := now.Add(-l.window)
+	for len(times) > 0 && times[0].Before(cutoff) {
+		times = times[1:]
+	}
+	if len(times) == 0 {
+		delete(l.requests, key)
+		return nil
+	}
+	l.requests[key] = times
+	return times
+}
+
+// sweep drops the keys that were not seen for a whole window, at most once per
+// window, so the map does not grow without bound and a call costs O(n) only then
+func (l *emailLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	cutoff := now.Add(-l.window)
+	for key, times := range l.requests {
+		if times[len(times)-1].Before(cutoff) {
+			delete(l.requests, key)
+		}
+	}
+}
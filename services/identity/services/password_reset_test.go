@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmailLimiterPrunesOnlyTheCheckedKey(t *testing.T) {
+	l := newEmailLimiter(2, time.Hour)
+	expired := time.Now().Add(-2 * time.Hour)
+	l.requests["old@example.com"] = []time.Time{expired}
+	l.requests["ada@example.com"] = []time.Time{expired, expired}
+
+	if !l.Allow("ada@example.com") {
+		t.Fatal("expired requests still count against the limit")
+	}
+	if got := len(l.requests["ada@example.com"]); got != 1 {
+		t.Fatalf("ada has %d requests, want the new one only", got)
+	}
+	if _, ok := l.requests["old@example.com"]; !ok {
+		t.Fatal("a key other than the checked one was pruned before the sweep was due")
+	}
+}
+
+func TestEmailLimiterSweepsIdleKeys(t *testing.T) {
+	l := newEmailLimiter(2, time.Hour)
+	l.lastSweep = time.Now().Add(-time.Hour)
+	l.requests["old@example.com"] = []time.Time{time.Now().Add(-2 * time.Hour)}
+	l.requests["recent@example.com"] = []time.Time{time.Now().Add(-time.Minute)}
+
+	if l.Exceeded("ada@example.com") {
+		t.Fatal("a new key exceeded the limit")
+	}
+	if _, ok := l.requests["old@example.com"]; ok {
+		t.Fatal("the idle key was not swept")
+	}
+	if _, ok := l.requests["recent@example.com"]; !ok {
+		t.Fatal("a key inside the window was swept")
+	}
+}
+
+func TestEmailLimiterLimit(t *testing.T) {
+	l := newEmailLimiter(2, time.Hour)
+	for i := range 2 {
+		if !l.Allow("ada@example.com") {
+			t.Fatalf("request %d was limited", i+1)
+		}
+	}
+	if l.Allow("ada@example.com") || !l.Exceeded("ada@example.com") {
+		t.Fatal("the third request within the window was allowed")
+	}
+	if l.Exceeded("bob@example.com") {
+		t.Fatal("the limit is shared between keys")
+	}
+}
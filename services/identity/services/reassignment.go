@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/operations"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// OperationRoleReassignment is the operation kind of bulk role reassignments
+	OperationRoleReassignment = "role_reassignment"
+
+	// DefaultReassignmentBatchSize is the number of users moved per transaction
+	DefaultReassignmentBatchSize = 100
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrSameRole     = errors.New("source and target roles must differ")
+)
+
+// reassignmentMetadata is stored with each role reassignment operation
+type reassignmentMetadata struct {
+	FromRoleID string `json:"from_role_id"`
+	ToRoleID   string `json:"to_role_id"`
+	BatchSize  int    `json:"batch_size"`
+}
+
+// ReassignmentService moves users between roles as long-running operations. Each
+// batch is committed together with the operation's progress, so an operation
+// interrupted by a crash resumes from where it stopped.
+type ReassignmentService struct {
+	db         *database.Database
+	logger     *zap.Logger
+	operations *operations.Manager
+	cache      *UserCache
+}
+
+// NewReassignmentService creates the service and registers its operation kind
+func NewReassignmentService(db *database.Database, logger *zap.Logger, manager *operations.Manager, userCache *UserCache) *ReassignmentService {
+	s := &ReassignmentService{db: db, logger: logger, operations: manager, cache: userCache}
+	manager.Register(OperationRoleReassignment, s.run)
+	return s
+}
+
+// Start validates the roles and starts an operation moving every user of fromRoleID to toRoleID
+func (s *ReassignmentService) Start(ctx context.Context, fromRoleID, toRoleID string, batchSize int) (*models.Operation, error) {
+	if fromRoleID == toRoleID {
+		return nil, ErrSameRole
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultReassignmentBatchSize
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var roles int64
+	if err := conn.Model(&models.Role{}).Where("id IN ?", []string{fromRoleID, toRoleID}).Count(&roles).Error; err != nil {
+		return nil, err
+	}
+	if roles != 2 {
+		return nil, ErrRoleNotFound
+	}
+
+	var total int64
+	if err := conn.Model(&models.User{}).Where("role_id = ?", fromRoleID).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	return s.operations.Start(ctx, OperationRoleReassignment, total, reassignmentMetadata{
+		FromRoleID: fromRoleID,
+		ToRoleID:   toRoleID,
+		BatchSize:  batchSize,
+	})
+}
+
+// run moves users in batches until none is left in the source role
+func (s *ReassignmentService) run(ctx context.Context, op *operations.Handle) (any, error) {
+	var meta reassignmentMetadata
+	if err := op.Metadata(&meta); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := op.Checkpoint(ctx); err != nil {
+			return nil, err
+		}
+
+		moved, err := s.moveBatch(ctx, op, meta)
+		if err != nil {
+			return nil, err
+		}
+		if moved == 0 {
+			return map[string]any{"moved": op.Operation.Processed}, nil
+		}
+	}
+}
+
+// moveBatch moves one batch of users and records the progress in the same
+// transaction, then removes the moved users from the cache
+func (s *ReassignmentService) moveBatch(ctx context.Context, op *operations.Handle, meta reassignmentMetadata) (int, error) {
+	conn, err := op.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var userIDs []string
+	err = conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("role_id = ?", meta.FromRoleID).
+			Order("id").
+			Limit(meta.BatchSize).
+			Pluck("id", &userIDs).Error; err != nil {
+			return fmt.Errorf("failed to select users: %w", err)
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		var permissionIDs []uint
+		if err := tx.Table("role_permissions").Where("role_id = ?", meta.ToRoleID).Pluck("permission_id", &permissionIDs).Error; err != nil {
+			return fmt.Errorf("failed to load role permissions: %w", err)
+		}
+
+		if err := tx.Model(&models.User{}).Where("id IN ?", userIDs).Update("role_id", meta.ToRoleID).Error; err != nil {
+			return fmt.Errorf("failed to update users: %w", err)
+		}
+
+		// Users carry a copy of their role's permissions, as done by StoreUser
+		if err := tx.Exec("DELETE FROM user_permissions WHERE user_id IN ?", userIDs).Error; err != nil {
+			return fmt.Errorf("failed to clear user permissions: %w", err)
+		}
+		if len(permissionIDs) > 0 {
+			rows := make([]map[string]any, 0, len(userIDs)*len(permissionIDs))
+			for _, userID := range userIDs {
+				for _, permissionID := range permissionIDs {
+					rows = append(rows, map[string]any{"user_id": userID, "permission_id": permissionID})
+				}
+			}
+			if err := tx.Table("user_permissions").Create(&rows).Error; err != nil {
+				return fmt.Errorf("failed to assign user permissions: %w", err)
+			}
+		}
+
+		if err := op.AddProgress(tx, int64(len(userIDs))); err != nil {
+			return fmt.Errorf("failed to record progress: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.cache.Invalidate(ctx, userIDs...)
+	return len(userIDs), nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/artifacts"
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/operations"
+	"github.com/gabehamasaki/momentum/services/identity/outbox"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// OperationTenantOffboarding is the operation kind that archives and suspends a tenant
+	OperationTenantOffboarding = "tenant_offboarding"
+
+	// ArtifactTenantArchive is the artifact kind of tenant data exports
+	ArtifactTenantArchive = "tenant_archive"
+
+	// DefaultTenantRetention is how long suspended users are kept before being purged
+	DefaultTenantRetention = 30 * 24 * time.Hour
+
+	offboardingBatchSize = 100
+)
+
+var (
+	ErrTenantRequired          = errors.New("tenant_id is required")
+	ErrTenantAlreadyOffboarded = errors.New("tenant is already being deprovisioned")
+)
+
+// offboardingMetadata is stored with each tenant offboarding operation
+type offboardingMetadata struct {
+	TenantID  string        `json:"tenant_id"`
+	Retention time.Duration `json:"retention"`
+}
+
+// archivedUser is one line of a tenant archive
+type archivedUser struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+// TenantOffboardingService deprovisions tenants as long-running operations: the
+// tenant's users are exported to an archive, then suspended in batches, and
+// permanently deleted once the retention window has passed
+type TenantOffboardingService struct {
+	db         *database.Database
+	logger     *zap.Logger
+	operations *operations.Manager
+	artifacts  *artifacts.Store
+	cache      *UserCache
+}
+
+// NewTenantOffboardingService creates the service and registers its operation kind
+func NewTenantOffboardingService(db *database.Database, logger *zap.Logger, manager *operations.Manager, artifactStore *artifacts.Store, userCache *UserCache) *TenantOffboardingService {
+	s := &TenantOffboardingService{db: db, logger: logger, operations: manager, artifacts: artifactStore, cache: userCache}
+	manager.Register(OperationTenantOffboarding, s.run)
+	return s
+}
+
+// Start begins deprovisioning the tenant; its users are purged retention after
+// suspension. Callers of a tenant may only deprovision their own, since the
+// operation itself runs without the caller's tenant.
+func (s *TenantOffboardingService) Start(ctx context.Context, tenantID string, retention time.Duration) (*models.Operation, error) {
+	if tenantID == "" {
+		return nil, ErrTenantRequired
+	}
+	if caller := shared.TenantFromContext(ctx); caller != "" && caller != tenantID {
+		return nil, model.ErrTenantMismatch
+	}
+	if retention <= 0 {
+		retention = DefaultTenantRetention
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing int64
+	if err := conn.Model(&models.TenantOffboarding{}).Where("tenant_id = ?", tenantID).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, ErrTenantAlreadyOffboarded
+	}
+
+	var total int64
+	if err := conn.Model(&models.User{}).Where("tenant_id = ?", tenantID).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	return s.operations.Start(ctx, OperationTenantOffboarding, total, offboardingMetadata{
+		TenantID:  tenantID,
+		Retention: retention,
+	})
+}
+
+// run archives the tenant once, then suspends its users in batches
+func (s *TenantOffboardingService) run(ctx context.Context, op *operations.Handle) (any, error) {
+	var meta offboardingMetadata
+	if err := op.Metadata(&meta); err != nil {
+		return nil, err
+	}
+
+	conn, err := op.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A resumed operation already has its archive and must not export the
+	// partially suspended tenant again
+	var offboarding models.TenantOffboarding
+	err = conn.First(&offboarding, "tenant_id = ?", meta.TenantID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		archive, err := s.archive(ctx, meta.TenantID, op.Operation.ID)
+		if err != nil {
+			return nil, err
+		}
+		offboarding = models.TenantOffboarding{
+			TenantID:    meta.TenantID,
+			OperationID: op.Operation.ID,
+			ArchiveID:   archive.ID,
+			PurgeAfter:  time.Now().Add(meta.Retention).UTC(),
+		}
+		if err := conn.Create(&offboarding).Error; err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := op.Checkpoint(ctx); err != nil {
+			return nil, err
+		}
+
+		var userIDs []string
+		err = conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.User{}).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("tenant_id = ?", meta.TenantID).
+				Limit(offboardingBatchSize).
+				Pluck("id", &userIDs).Error; err != nil {
+				return fmt.Errorf("failed to select tenant users: %w", err)
+			}
+			if len(userIDs) == 0 {
+				return nil
+			}
+
+			if err := tx.Where("id IN ?", userIDs).Delete(&models.User{}).Error; err != nil {
+				return fmt.Errorf("failed to suspend users: %w", err)
+			}
+			for _, id := range userIDs {
+				event, err := userEvent(events.UserDeleted, meta.TenantID, events.UserData{UserID: id})
+				if err != nil {
+					return err
+				}
+				if err := outbox.Add(tx, event); err != nil {
+					return err
+				}
+			}
+			return op.AddProgress(tx, int64(len(userIDs)))
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Invalidate(ctx, userIDs...)
+		if len(userIDs) == 0 {
+			s.logger.Info("Tenant deprovisioned",
+				zap.String("tenant_id", meta.TenantID),
+				zap.String("archive_id", offboarding.ArchiveID),
+				zap.Time("purge_after", offboarding.PurgeAfter),
+			)
+			return map[string]any{
+				"archive_id":  offboarding.ArchiveID,
+				"suspended":   op.Operation.Processed,
+				"purge_after": offboarding.PurgeAfter,
+			}, nil
+		}
+	}
+}
+
+// archive exports every user of the tenant, including already deactivated ones,
+// as JSON lines
+func (s *TenantOffboardingService) archive(ctx context.Context, tenantID, operationID string) (*models.Artifact, error) {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		encoder := json.NewEncoder(writer)
+		var users []models.User
+		err := conn.Unscoped().Preload("Role").
+			Where("tenant_id = ?", tenantID).
+			Order("created_at, id").
+			FindInBatches(&users, offboardingBatchSize, func(tx *gorm.DB, batch int) error {
+				for _, user := range users {
+					line := archivedUser{
+						ID:              user.ID,
+						Name:            user.Name,
+						Email:           user.Email,
+						Role:            user.Role.Name,
+						EmailVerifiedAt: user.EmailVerifiedAt,
+						CreatedAt:       user.CreatedAt,
+					}
+					if user.DeletedAt.Valid {
+						line.DeletedAt = &user.DeletedAt.Time
+					}
+					if err := encoder.Encode(line); err != nil {
+						return err
+					}
+				}
+				return nil
+			}).Error
+		writer.CloseWithError(err)
+	}()
+
+	return s.artifacts.Put(ctx, models.Artifact{
+		TenantID:    tenantID,
+		Kind:        ArtifactTenantArchive,
+		Name:        fmt.Sprintf("tenant-%s.jsonl", tenantID),
+		ContentType: "application/x-ndjson",
+		CreatedBy:   "operation:" + operationID,
+	}, reader)
+}
+
+// Run periodically purges tenants whose retention window has passed, until ctx ends
+func (s *TenantOffboardingService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.purgeDue(ctx); err != nil {
+				s.logger.Error("Failed to purge deprovisioned tenants", shared.ErrorSource(shared.ErrorSourceJob), zap.Error(err))
+			}
+		}
+	}
+}
+
+// purgeDue permanently deletes the users of tenants past their retention window,
+// along with the rows referencing them. The archive is kept.
+func (s *TenantOffboardingService) purgeDue(ctx context.Context) error {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var due []models.TenantOffboarding
+	if err := conn.Where("purge_after <= ? AND purged_at IS NULL", time.Now().UTC()).Find(&due).Error; err != nil {
+		return err
+	}
+
+	for _, offboarding := range due {
+		var purged int64
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			// Users created after the offboarding operation finished are left alone
+			users := tx.Unscoped().Model(&models.User{}).Select("id").
+				Where("tenant_id = ? AND deleted_at IS NOT NULL", offboarding.TenantID)
+
+			for _, dependent := range []any{&models.PasswordReset{}, &models.EmailVerification{}} {
+				if err := tx.Where("user_id IN (?)", users).Delete(dependent).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec("DELETE FROM user_permissions WHERE user_id IN (?)", users).Error; err != nil {
+				return err
+			}
+
+			result := tx.Unscoped().Where("tenant_id = ? AND deleted_at IS NOT NULL", offboarding.TenantID).Delete(&models.User{})
+			if result.Error != nil {
+				return result.Error
+			}
+			purged = result.RowsAffected
+
+			return tx.Model(&models.TenantOffboarding{}).
+				Where("tenant_id = ?", offboarding.TenantID).
+				Update("purged_at", time.Now().UTC()).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to purge tenant %s: %w", offboarding.TenantID, err)
+		}
+
+		s.logger.Info("Tenant purged", zap.String("tenant_id", offboarding.TenantID), zap.Int64("users", purged))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+)
+
+// TenantService manages the tenants. Callers authenticated for a tenant only see
+// and update their own, and only platform callers create tenants.
+type TenantService struct {
+	db     *database.Database
+	logger *zap.Logger
+}
+
+func NewTenantService(db *database.Database, logger *zap.Logger) *TenantService {
+	return &TenantService{db: db, logger: logger}
+}
+
+// Create stores a new tenant; its ID is then used as the tenant_id of its records
+func (s *TenantService) Create(ctx context.Context, tenant models.Tenant) (models.Tenant, error) {
+	if shared.TenantFromContext(ctx) != "" {
+		return models.Tenant{}, model.ErrTenantMismatch
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	result := conn.Clauses(clause.OnConflict{DoNothing: true}).Create(&tenant)
+	if result.Error != nil {
+		return models.Tenant{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.Tenant{}, ErrTenantExists
+	}
+
+	s.logger.Info("Tenant created", zap.String("tenant_id", tenant.ID))
+	return tenant, nil
+}
+
+func (s *TenantService) Get(ctx context.Context, id string) (models.Tenant, error) {
+	if caller := shared.TenantFromContext(ctx); caller != "" && caller != id {
+		return models.Tenant{}, ErrTenantNotFound
+	}
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return models.Tenant{}, err
+	}
+	var tenant models.Tenant
+	if err := conn.First(&tenant, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Tenant{}, ErrTenantNotFound
+		}
+		return models.Tenant{}, err
+	}
+	return tenant, nil
+}
+
+// List returns a page of the tenants visible to the caller, ordered by ID
+func (s *TenantService) List(ctx context.Context, limit, offset int) ([]models.Tenant, error) {
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := conn.Order("id").Limit(limit).Offset(offset)
+	if caller := shared.TenantFromContext(ctx); caller != "" {
+		query = query.Where("id = ?", caller)
+	}
+
+	var tenants []models.Tenant
+	if err := query.Find(&tenants).Error; err != nil {
+		return nil, err
+	}
+	return tenants, nil
+}
+
+// Rename changes the tenant's name and returns the tenant with its previous name
+func (s *TenantService) Rename(ctx context.Context, id, name string) (models.Tenant, string, error) {
+	tenant, err := s.Get(ctx, id)
+	if err != nil {
+		return models.Tenant{}, "", err
+	}
+	previous := tenant.Name
+
+	conn, err := s.db.ConnWithContext(ctx)
+	if err != nil {
+		return models.Tenant{}, "", err
+	}
+	if err := conn.Model(&tenant).Update("name", name).Error; err != nil {
+		return models.Tenant{}, "", err
+	}
+	return tenant, previous, nil
+}
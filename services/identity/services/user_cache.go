@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared/cache"
+	"go.uber.org/zap"
+)
+
+// DefaultUserCacheTTL is how long users loaded by FindUserByID stay cached
+const DefaultUserCacheTTL = time.Minute
+
+// UserCache caches users with their role and permissions. Services that change a
+// user invalidate its entry once their transaction commits; writes made outside
+// them show up after the TTL. A nil *UserCache disables caching.
+type UserCache struct {
+	cache  cache.Cache
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewUserCache returns nil, disabling caching, when ttl is not positive
+func NewUserCache(c cache.Cache, ttl time.Duration, logger *zap.Logger) *UserCache {
+	if c == nil || ttl <= 0 {
+		return nil
+	}
+	return &UserCache{cache: c, ttl: ttl, logger: logger}
+}
+
+func userCacheKey(id string) string {
+	return "identity:user:" + id
+}
+
+// Get returns the cached user; cache errors are logged and reported as a miss
+func (c *UserCache) Get(ctx context.Context, id string) (models.User, bool) {
+	if c == nil {
+		return models.User{}, false
+	}
+
+	data, ok, err := c.cache.Get(ctx, userCacheKey(id))
+	if err != nil {
+		c.logger.Warn("Failed to read user cache", zap.String("user_id", id), zap.Error(err))
+		return models.User{}, false
+	}
+	if !ok {
+		return models.User{}, false
+	}
+
+	var user models.User
+	if err := json.Unmarshal(data, &user); err != nil {
+		c.logger.Warn("Failed to decode cached user", zap.String("user_id", id), zap.Error(err))
+		return models.User{}, false
+	}
+	return user, true
+}
+
+// Set caches the user for the TTL, without its password hash
+func (c *UserCache) Set(ctx context.Context, user models.User) {
+	if c == nil {
+		return
+	}
+
+	user.Password = ""
+	data, err := json.Marshal(user)
+	if err != nil {
+		c.logger.Warn("Failed to encode user for cache", zap.String("user_id", user.ID), zap.Error(err))
+		return
+	}
+	if err := c.cache.Set(ctx, userCacheKey(user.ID), data, c.ttl); err != nil {
+		c.logger.Warn("Failed to write user cache", zap.String("user_id", user.ID), zap.Error(err))
+	}
+}
+
+// Invalidate removes the cached users
+func (c *UserCache) Invalidate(ctx context.Context, ids ...string) {
+	if c == nil || len(ids) == 0 {
+		return
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = userCacheKey(id)
+	}
+	if err := c.cache.Delete(ctx, keys...); err != nil {
+		c.logger.Warn("Failed to invalidate user cache", zap.Strings("user_ids", ids), zap.Error(err))
+	}
+}
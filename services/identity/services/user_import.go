@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/utils"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// importBatchSize is how many users are inserted per statement
+const importBatchSize = 100
+
+// ImportRow is one user of a bulk import. Rows given with violations, e.g. from
+// request validation, are reported back without further checks.
+type ImportRow struct {
+	User       models.User
+	Violations []model.FieldError
+}
+
+// ImportUsers creates the users of the valid rows in one transaction, each with a
+// copy of its role's permissions as done by StoreUser, and returns how many were
+// created. Rows are rejected with an "exists" violation for unknown roles, "unique"
+// for emails already used or repeated in rows, and "bcrypt" for password hashes
+// of another format. Users without a password hash get one no password matches.
+// With dryRun the rows are checked but nothing is created.
+func (s *UserService) ImportUsers(ctx context.Context, rows []ImportRow, dryRun bool) (int, error) {
+	emails := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if len(row.Violations) == 0 {
+			emails = append(emails, strings.ToLower(row.User.Email))
+		}
+	}
+
+	var created []*models.User
+	err := s.users.Transaction(ctx, func(users database.UserRepository) error {
+		taken, err := users.FindEmails(ctx, emails)
+		if err != nil {
+			return err
+		}
+
+		roles := make(map[string]*models.Role)
+		for i := range rows {
+			row := &rows[i]
+			if len(row.Violations) > 0 {
+				continue
+			}
+			user := &row.User
+
+			email := strings.ToLower(user.Email)
+			if taken[email] {
+				row.Violations = append(row.Violations, model.FieldError{Field: "email", Rule: "unique"})
+			}
+
+			role, ok := roles[user.RoleID]
+			if !ok {
+				found, err := users.FindRole(ctx, user.RoleID)
+				if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if err == nil {
+					role = &found
+				}
+				roles[user.RoleID] = role
+			}
+			if role == nil {
+				row.Violations = append(row.Violations, model.FieldError{Field: "role_id", Rule: "exists"})
+			}
+
+			if user.Password != "" && !utils.IsBcryptHash(user.Password) {
+				row.Violations = append(row.Violations, model.FieldError{Field: "password_hash", Rule: "bcrypt"})
+			}
+
+			if len(row.Violations) == 0 {
+				taken[email] = true
+				user.Permissions = role.Permissions
+				created = append(created, user)
+			}
+		}
+		if dryRun || len(created) == 0 {
+			return nil
+		}
+
+		if err := setUnusablePasswords(created); err != nil {
+			return err
+		}
+		if err := users.CreateBatch(ctx, created, importBatchSize); err != nil {
+			return err
+		}
+
+		batch := make([]events.Event, len(created))
+		for i, user := range created {
+			if batch[i], err = userEvent(events.UserCreated, user.TenantID, events.UserData{
+				UserID: user.ID,
+				Name:   user.Name,
+				Email:  user.Email,
+				RoleID: user.RoleID,
+			}); err != nil {
+				return err
+			}
+		}
+		return users.AddEvents(ctx, batch)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if dryRun {
+		return 0, nil
+	}
+
+	s.logger.Info("Users imported", zap.Int("imported", len(created)), zap.Int("rejected", len(rows)-len(created)))
+	return len(created), nil
+}
+
+// setUnusablePasswords gives users without a password the hash of a random secret
+// that is thrown away, shared by all of them since bcrypt is slow by design
+func setUnusablePasswords(users []*models.User) error {
+	var hash string
+	for _, user := range users {
+		if user.Password != "" {
+			continue
+		}
+		if hash == "" {
+			secret, _, err := newToken()
+			if err != nil {
+				return err
+			}
+			if hash, err = utils.Bcrypt(secret); err != nil {
+				return err
+			}
+		}
+		user.Password = hash
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/services/identity/utils"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func importRows(t *testing.T) []ImportRow {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("imported"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := func(email, roleID, password string) ImportRow {
+		return ImportRow{User: models.User{Name: email, Email: email, RoleID: roleID, Password: password}}
+	}
+	return []ImportRow{
+		row("ada@example.com", "member", string(hash)),
+		row("TAKEN@example.com", "member", string(hash)),
+		row("grace@example.com", "admin", ""),
+		row("Ada@Example.com", "member", string(hash)),
+		row("alan@example.com", "owner", string(hash)),
+		row("edsger@example.com", "member", "plain-text"),
+		{User: models.User{Email: "invalid"}, Violations: []model.FieldError{{Field: "email", Rule: "email"}}},
+	}
+}
+
+// wantImportViolations are the violations of importRows, by row
+var wantImportViolations = [][]model.FieldError{
+	nil,
+	{{Field: "email", Rule: "unique"}},
+	nil,
+	{{Field: "email", Rule: "unique"}},
+	{{Field: "role_id", Rule: "exists"}},
+	{{Field: "password_hash", Rule: "bcrypt"}},
+	{{Field: "email", Rule: "email"}},
+}
+
+func newImportService() (*UserService, *fakeUserRepository) {
+	service, users := newTestUserService()
+	users.add(models.User{BaseModel: model.BaseModel{ID: "existing"}, Email: "taken@example.com", RoleID: "member"})
+	return service, users
+}
+
+func TestImportUsers(t *testing.T) {
+	service, users := newImportService()
+	rows := importRows(t)
+
+	imported, err := service.ImportUsers(context.Background(), rows, false)
+	if err != nil {
+		t.Fatalf("ImportUsers: %v", err)
+	}
+	if imported != 2 {
+		t.Fatalf("imported = %d, want 2", imported)
+	}
+	for i, row := range rows {
+		if !reflect.DeepEqual(row.Violations, wantImportViolations[i]) {
+			t.Errorf("row %d (%s) violations = %v, want %v", i, row.User.Email, row.Violations, wantImportViolations[i])
+		}
+	}
+
+	ada := users.stored(t, rows[0].User.ID)
+	if got := permissionNames(ada.Permissions); !reflect.DeepEqual(got, []string{"user.view", "profile.edit"}) {
+		t.Errorf("imported permissions = %v, want the member role's", got)
+	}
+	grace := users.stored(t, rows[2].User.ID)
+	if !utils.IsBcryptHash(grace.Password) || utils.CompareHashAndPassword(grace.Password, "") == nil {
+		t.Errorf("user imported without password got hash %q, want an unusable bcrypt hash", grace.Password)
+	}
+	if created := users.eventData(t, events.UserCreated); len(created) != 2 {
+		t.Errorf("user.created events = %+v, want one per imported user", created)
+	}
+}
+
+func TestImportUsersDryRun(t *testing.T) {
+	service, users := newImportService()
+	rows := importRows(t)
+
+	imported, err := service.ImportUsers(context.Background(), rows, true)
+	if err != nil {
+		t.Fatalf("ImportUsers: %v", err)
+	}
+	if imported != 0 || len(users.users) != 1 || len(users.events) != 0 {
+		t.Fatalf("dry run imported %d users, stored %d, published %d events", imported, len(users.users), len(users.events))
+	}
+	for i, row := range rows {
+		if !reflect.DeepEqual(row.Violations, wantImportViolations[i]) {
+			t.Errorf("row %d (%s) violations = %v, want %v", i, row.User.Email, row.Violations, wantImportViolations[i])
+		}
+	}
+}
+
+func TestImportUsersRollsBackWhenEventsFail(t *testing.T) {
+	service, users := newImportService()
+	users.failEvents = true
+
+	if _, err := service.ImportUsers(context.Background(), importRows(t), false); err == nil {
+		t.Fatal("ImportUsers succeeded without recording events")
+	}
+	if len(users.users) != 1 {
+		t.Fatalf("stored %d users after a failed import, want only the existing one", len(users.users))
+	}
+}
@@ -2,75 +2,294 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"slices"
 
 	"github.com/gabehamasaki/momentum/services/identity/database"
 	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// UserService implements user management on top of a UserRepository
 type UserService struct {
-	db     *database.Database
+	users  database.UserRepository
+	cache  *UserCache
 	logger *zap.Logger
 }
 
-func NewUserService(db *database.Database, logger *zap.Logger) *UserService {
-	return &UserService{db: db, logger: logger}
+func NewUserService(users database.UserRepository, userCache *UserCache, logger *zap.Logger) *UserService {
+	return &UserService{users: users, cache: userCache, logger: logger}
 }
 
 func (s *UserService) GetUsers(ctx context.Context) ([]models.User, error) {
-	var users []models.User
-	conn, err := s.db.ConnWithContext(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer s.db.Close()
+	return s.users.FindAll(ctx)
+}
 
-	if err := conn.Preload("Role").Find(&users).Error; err != nil {
-		return nil, err
-	}
+// ListUsers returns a page of users ordered by creation, see database.UserPage
+func (s *UserService) ListUsers(ctx context.Context, page database.UserPage) ([]models.User, error) {
+	return s.users.FindPage(ctx, page)
+}
 
-	return users, nil
+// SearchUsers returns a page of active users matching query, most relevant first
+func (s *UserService) SearchUsers(ctx context.Context, query string, limit, offset int) ([]models.User, error) {
+	return s.users.Search(ctx, query, limit, offset)
 }
 
+// FindUserByID returns the user with its role and permissions, from the cache when possible
 func (s *UserService) FindUserByID(ctx context.Context, id string) (models.User, error) {
-	var user models.User
-	conn, err := s.db.ConnWithContext(ctx)
+	// The cache is shared by all tenants, so it is checked like the database query would
+	if user, ok := s.cache.Get(ctx, id); ok {
+		if tenantID := shared.TenantFromContext(ctx); tenantID != "" && user.TenantID != tenantID {
+			return models.User{}, gorm.ErrRecordNotFound
+		}
+		return user, nil
+	}
+
+	user, err := s.users.FindByID(ctx, id)
 	if err != nil {
 		return models.User{}, err
 	}
-	defer s.db.Close()
+	s.cache.Set(ctx, user)
+	return user, nil
+}
 
-	if err := conn.Preload("Role").Preload("Role.Permissions").Preload("Permissions").First(&user, "id = ?", id).Error; err != nil {
-		return models.User{}, err
+// CheckToken implements shared.AuthConfig.CheckToken, revoking the tokens of deleted
+// and suspended users and those issued before the last password change. It reads
+// through the cache, which deletions and password changes invalidate.
+func (s *UserService) CheckToken(ctx context.Context, claims *shared.Claims) error {
+	user, err := s.FindUserByID(ctx, claims.Subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return shared.ErrTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+	// iat has a resolution of one second, so a token issued in the second of the
+	// change may predate it and is revoked as well
+	if user.PasswordChangedAt != nil && claims.IssuedAt <= user.PasswordChangedAt.Unix() {
+		return shared.ErrTokenRevoked
 	}
+	return nil
+}
 
-	return user, nil
+// RoleExists reports whether the role exists, for validating requests referencing it
+func (s *UserService) RoleExists(ctx context.Context, id string) (bool, error) {
+	_, err := s.users.FindRole(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
 }
 
+// StoreUser creates the user with a copy of its role's permissions. It runs in a
+// transaction so the user is read back from the primary rather than a replica.
 func (s *UserService) StoreUser(ctx context.Context, user models.User) (models.User, error) {
-	conn, err := s.db.ConnWithContext(ctx)
+	err := s.users.Transaction(ctx, func(users database.UserRepository) error {
+		if err := users.Create(ctx, &user); err != nil {
+			return err
+		}
+
+		// Load role and permissions
+		var err error
+		if user, err = users.FindByID(ctx, user.ID); err != nil {
+			return err
+		}
+
+		// Assign role permissions to user
+		user.Permissions = append(user.Permissions, user.Role.Permissions...)
+		// Remove duplicate permissions
+		if err := users.ReplacePermissions(ctx, &user, user.Permissions); err != nil {
+			return err
+		}
+
+		event, err := userEvent(events.UserCreated, user.TenantID, events.UserData{
+			UserID: user.ID,
+			Name:   user.Name,
+			Email:  user.Email,
+			RoleID: user.RoleID,
+		})
+		if err != nil {
+			return err
+		}
+		return users.AddEvent(ctx, event)
+	})
 	if err != nil {
 		return models.User{}, err
 	}
-	defer s.db.Close()
 
-	if err := conn.Create(&user).Error; err != nil {
-		fmt.Println("Erro ao criar usuário:", err)
+	return user, nil
+}
+
+// RestoreUser reactivates a deactivated user, keeping its role and permissions,
+// and cancels its deactivation schedule
+func (s *UserService) RestoreUser(ctx context.Context, id string) (models.User, error) {
+	var user models.User
+	err := s.users.Transaction(ctx, func(users database.UserRepository) error {
+		var err error
+		if user, err = users.FindWithDeleted(ctx, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if !user.DeletedAt.Valid {
+			return ErrUserNotDeleted
+		}
+
+		if err := users.Restore(ctx, &user); err != nil {
+			return err
+		}
+
+		event, err := userEvent(events.UserRestored, user.TenantID, events.UserData{
+			UserID: user.ID,
+			Name:   user.Name,
+			Email:  user.Email,
+			RoleID: user.RoleID,
+		})
+		if err != nil {
+			return err
+		}
+		return users.AddEvent(ctx, event)
+	})
+	if err != nil {
 		return models.User{}, err
 	}
 
-	// Load role and permissions
-	if err := conn.Preload("Role").Preload("Role.Permissions").Preload("Permissions").First(&user, "id = ?", user.ID).Error; err != nil {
-		return models.User{}, err
+	s.cache.Invalidate(ctx, user.ID)
+	s.logger.Info("User restored", zap.String("user_id", user.ID))
+	return user, nil
+}
+
+// ExportUsers streams every user, or those of tenantID when set, in batches instead
+// of loading the whole table; fn is called once per batch and may block to apply
+// backpressure. With snapshot every batch is read from the same read-only
+// transaction, so users changed during the export are seen as they were at its start.
+func (s *UserService) ExportUsers(ctx context.Context, tenantID string, batchSize int, snapshot bool, fn func(users []models.User) error) error {
+	opts := database.ListOptions{Preload: []string{"Role"}}
+	if tenantID != "" {
+		opts.Scopes = append(opts.Scopes, model.TenantScope(tenantID))
+	}
+	if !snapshot {
+		return s.users.Stream(ctx, opts, batchSize, fn)
 	}
+	return s.users.ReadSnapshot(ctx, func(users database.UserRepository) error {
+		return users.Stream(ctx, opts, batchSize, fn)
+	})
+}
 
-	// Assign role permissions to user
-	user.Permissions = append(user.Permissions, user.Role.Permissions...)
-	// Remove duplicate permissions
-	if err := conn.Model(&user).Association("Permissions").Replace(user.Permissions); err != nil {
-		return models.User{}, err
+// UserUpdate lists the fields to change; nil fields are left untouched
+type UserUpdate struct {
+	Name   *string
+	Email  *string
+	RoleID *string
+}
+
+// FieldChange is the before/after value of a modified field
+type FieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// UpdateUser applies the update and returns the user along with the fields that
+// actually changed. A role change also replaces the user's copy of the role permissions.
+func (s *UserService) UpdateUser(ctx context.Context, id string, update UserUpdate) (models.User, []FieldChange, error) {
+	var user models.User
+	var changes []FieldChange
+	err := s.users.Transaction(ctx, func(users database.UserRepository) error {
+		var err error
+		if user, err = users.FindByID(ctx, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		var columns []string
+		if update.Name != nil && *update.Name != user.Name {
+			changes = append(changes, FieldChange{Field: "name", Before: user.Name, After: *update.Name})
+			columns = append(columns, "name")
+			user.Name = *update.Name
+		}
+		if update.Email != nil && *update.Email != user.Email {
+			changes = append(changes, FieldChange{Field: "email", Before: user.Email, After: *update.Email})
+			// A new address must be verified again
+			columns = append(columns, "email", "email_verified_at")
+			user.Email = *update.Email
+			user.EmailVerifiedAt = nil
+		}
+
+		var role *models.Role
+		if update.RoleID != nil && *update.RoleID != user.RoleID {
+			found, err := users.FindRole(ctx, *update.RoleID)
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrRoleNotFound
+				}
+				return err
+			}
+			role = &found
+			changes = append(changes, FieldChange{Field: "role", Before: user.Role.Name, After: role.Name})
+			columns = append(columns, "role_id")
+			user.RoleID = role.ID
+		}
+
+		if len(columns) == 0 {
+			return nil
+		}
+		// Bumping the version makes a concurrent update of the same user fail with
+		// model.ErrVersionConflict instead of overwriting this one
+		columns = append(columns, "version")
+		if err := users.UpdateColumns(ctx, &user, columns); err != nil {
+			return err
+		}
+		if slices.Contains(columns, "email") {
+			// Tokens sent to the previous address must not verify the new one
+			if err := users.InvalidateEmailVerifications(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+
+		if role != nil {
+			user.Role = *role
+			// Users carry a copy of their role's permissions, as done by StoreUser
+			if err := users.ReplacePermissions(ctx, &user, role.Permissions); err != nil {
+				return err
+			}
+		}
+
+		fields := make([]string, len(changes))
+		for i, change := range changes {
+			fields[i] = change.Field
+		}
+		event, err := userEvent(events.UserUpdated, user.TenantID, events.UserData{
+			UserID:  user.ID,
+			Name:    user.Name,
+			Email:   user.Email,
+			RoleID:  user.RoleID,
+			Changes: fields,
+		})
+		if err != nil {
+			return err
+		}
+		return users.AddEvent(ctx, event)
+	})
+	if err != nil {
+		return models.User{}, nil, err
 	}
 
-	return user, nil
+	if len(changes) > 0 {
+		s.cache.Invalidate(ctx, user.ID)
+
+		fields := make([]string, 0, len(changes))
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+		}
+		s.logger.Info("User updated", zap.String("user_id", user.ID), zap.Strings("fields", fields))
+	}
+	return user, changes, nil
 }
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gabehamasaki/momentum/services/identity/database"
+	"github.com/gabehamasaki/momentum/services/identity/models"
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/gabehamasaki/momentum/shared/model"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	viewUsers   = &models.Permission{ID: 1, Name: "user.view"}
+	editProfile = &models.Permission{ID: 2, Name: "profile.edit"}
+	manageRoles = &models.Permission{ID: 3, Name: "role.manage"}
+
+	memberRole = models.Role{BaseModel: model.BaseModel{ID: "member"}, Name: "Member", Permissions: []*models.Permission{viewUsers, editProfile}}
+	adminRole  = models.Role{BaseModel: model.BaseModel{ID: "admin"}, Name: "Admin", Permissions: []*models.Permission{viewUsers, manageRoles}}
+)
+
+func newTestUserService() (*UserService, *fakeUserRepository) {
+	users := newFakeUserRepository(memberRole, adminRole)
+	return NewUserService(users, nil, zap.NewNop()), users
+}
+
+func permissionNames(permissions []*models.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, perm := range permissions {
+		names[i] = perm.Name
+	}
+	return names
+}
+
+func TestStoreUserCopiesRolePermissions(t *testing.T) {
+	service, users := newTestUserService()
+
+	user, err := service.StoreUser(context.Background(), models.User{Name: "Ada", Email: "ada@example.com", Password: "hash", RoleID: "member"})
+	if err != nil {
+		t.Fatalf("StoreUser: %v", err)
+	}
+
+	if user.Role.Name != "Member" {
+		t.Errorf("role = %q, want the loaded role Member", user.Role.Name)
+	}
+	if got, want := permissionNames(users.stored(t, user.ID).Permissions), []string{"user.view", "profile.edit"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stored permissions = %v, want %v", got, want)
+	}
+	created := users.eventData(t, events.UserCreated)
+	if want := []events.UserData{{UserID: user.ID, Name: "Ada", Email: "ada@example.com", RoleID: "member"}}; !reflect.DeepEqual(created, want) {
+		t.Errorf("user.created events = %+v, want %+v", created, want)
+	}
+}
+
+func TestStoreUserRollsBackWhenEventFails(t *testing.T) {
+	service, users := newTestUserService()
+	users.failEvents = true
+
+	if _, err := service.StoreUser(context.Background(), models.User{Name: "Ada", Email: "ada@example.com", RoleID: "member"}); !errors.Is(err, errEventsUnavailable) {
+		t.Fatalf("StoreUser error = %v, want %v", err, errEventsUnavailable)
+	}
+	if len(users.users) != 0 {
+		t.Fatalf("users stored after a failed transaction: %v", users.users)
+	}
+}
+
+func TestStoreUserRejectsTakenEmail(t *testing.T) {
+	deleted := gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	tests := []struct {
+		name     string
+		existing models.User
+		wantErr  error
+	}{
+		{"same tenant", models.User{BaseModel: model.BaseModel{ID: "ada", TenantID: "acme"}, Email: "ADA@example.com"}, database.ErrEmailTaken},
+		{"other tenant", models.User{BaseModel: model.BaseModel{ID: "ada", TenantID: "globex"}, Email: "ada@example.com"}, nil},
+		{"deactivated user", models.User{BaseModel: model.BaseModel{ID: "ada", TenantID: "acme", DeletedAt: deleted}, Email: "ada@example.com"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users := newTestUserService()
+			users.add(tt.existing)
+
+			user := models.User{BaseModel: model.BaseModel{TenantID: "acme"}, Name: "Ada", Email: "ada@example.com", RoleID: "member"}
+			if _, err := service.StoreUser(context.Background(), user); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("StoreUser error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (len(users.users) != 1 || len(users.events) != 0) {
+				t.Fatalf("the rejected user was stored: %v", users.users)
+			}
+		})
+	}
+}
+
+func TestUpdateUserReportsChangedFields(t *testing.T) {
+	verifiedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ptr := func(s string) *string { return &s }
+
+	tests := []struct {
+		name            string
+		update          UserUpdate
+		wantChanges     []FieldChange
+		wantErr         error
+		wantPermissions []string
+	}{
+		{
+			name:   "nothing given",
+			update: UserUpdate{},
+		},
+		{
+			name:   "same values",
+			update: UserUpdate{Name: ptr("Ada"), Email: ptr("ada@example.com"), RoleID: ptr("member")},
+		},
+		{
+			name:        "new name",
+			update:      UserUpdate{Name: ptr("Ada Lovelace"), Email: ptr("ada@example.com")},
+			wantChanges: []FieldChange{{Field: "name", Before: "Ada", After: "Ada Lovelace"}},
+		},
+		{
+			name:        "new email",
+			update:      UserUpdate{Email: ptr("lovelace@example.com")},
+			wantChanges: []FieldChange{{Field: "email", Before: "ada@example.com", After: "lovelace@example.com"}},
+		},
+		{
+			name:            "new role",
+			update:          UserUpdate{RoleID: ptr("admin")},
+			wantChanges:     []FieldChange{{Field: "role", Before: "Member", After: "Admin"}},
+			wantPermissions: []string{"user.view", "role.manage"},
+		},
+		{
+			name:    "unknown role",
+			update:  UserUpdate{Name: ptr("Ada Lovelace"), RoleID: ptr("owner")},
+			wantErr: ErrRoleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users := newTestUserService()
+			users.add(models.User{
+				BaseModel:       model.BaseModel{ID: "user-1", TenantID: "acme"},
+				Name:            "Ada",
+				Email:           "ada@example.com",
+				RoleID:          "member",
+				EmailVerifiedAt: &verifiedAt,
+				Permissions:     memberRole.Permissions,
+			})
+
+			_, changes, err := service.UpdateUser(context.Background(), "user-1", tt.update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUser error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(changes, tt.wantChanges) {
+				t.Fatalf("changes = %+v, want %+v", changes, tt.wantChanges)
+			}
+
+			stored := users.stored(t, "user-1")
+			updated := users.eventData(t, events.UserUpdated)
+			if len(tt.wantChanges) == 0 {
+				if stored.Name != "Ada" || len(updated) != 0 {
+					t.Fatalf("unchanged user was written: %+v, events %+v", stored, updated)
+				}
+				return
+			}
+
+			if len(updated) != 1 || len(updated[0].Changes) != len(tt.wantChanges) || updated[0].Changes[0] != tt.wantChanges[0].Field {
+				t.Errorf("user.updated events = %+v, want one listing %v", updated, tt.wantChanges)
+			}
+			emailChanged := tt.wantChanges[0].Field == "email"
+			if emailChanged != (stored.EmailVerifiedAt == nil) {
+				t.Errorf("email_verified_at = %v after changing the email: %v", stored.EmailVerifiedAt, emailChanged)
+			}
+			if emailChanged != reflect.DeepEqual(users.invalidated, []string{"user-1"}) {
+				t.Errorf("invalidated verifications = %v, email changed: %v", users.invalidated, emailChanged)
+			}
+			if tt.wantPermissions != nil {
+				if got := permissionNames(stored.Permissions); !reflect.DeepEqual(got, tt.wantPermissions) {
+					t.Errorf("permissions = %v, want %v", got, tt.wantPermissions)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateUserNotFound(t *testing.T) {
+	service, _ := newTestUserService()
+	name := "Ada"
+
+	if _, _, err := service.UpdateUser(context.Background(), "missing", UserUpdate{Name: &name}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("UpdateUser error = %v, want %v", err, ErrUserNotFound)
+	}
+}
+
+func TestUpdateUserRejectsTakenEmail(t *testing.T) {
+	service, users := newTestUserService()
+	users.add(models.User{BaseModel: model.BaseModel{ID: "ada"}, Name: "Ada", Email: "ada@example.com", RoleID: "member"})
+	users.add(models.User{BaseModel: model.BaseModel{ID: "bob"}, Name: "Bob", Email: "bob@example.com", RoleID: "member"})
+	email := "Ada@Example.com"
+
+	if _, _, err := service.UpdateUser(context.Background(), "bob", UserUpdate{Email: &email}); !errors.Is(err, database.ErrEmailTaken) {
+		t.Fatalf("UpdateUser error = %v, want %v", err, database.ErrEmailTaken)
+	}
+	if got := users.stored(t, "bob").Email; got != "bob@example.com" {
+		t.Fatalf("email = %q, want it unchanged", got)
+	}
+}
+
+func TestRestoreUser(t *testing.T) {
+	scheduledAt := time.Now().Add(24 * time.Hour)
+	deleted := gorm.DeletedAt{Time: time.Now().Add(-time.Hour), Valid: true}
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		wantErr error
+	}{
+		{
+			name: "deactivated",
+			user: &models.User{BaseModel: model.BaseModel{ID: "user-1", DeletedAt: deleted}, Name: "Ada", RoleID: "member", ScheduleDeactivationAt: &scheduledAt},
+		},
+		{
+			name:    "active",
+			user:    &models.User{BaseModel: model.BaseModel{ID: "user-1"}, Name: "Ada", RoleID: "member"},
+			wantErr: ErrUserNotDeleted,
+		},
+		{
+			name:    "missing",
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users := newTestUserService()
+			if tt.user != nil {
+				users.add(*tt.user)
+			}
+
+			user, err := service.RestoreUser(context.Background(), "user-1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestoreUser error = %v, want %v", err, tt.wantErr)
+			}
+			restored := users.eventData(t, events.UserRestored)
+			if tt.wantErr != nil {
+				if len(restored) != 0 {
+					t.Fatalf("user.restored published on error: %+v", restored)
+				}
+				return
+			}
+
+			stored := users.stored(t, "user-1")
+			if stored.DeletedAt.Valid || stored.ScheduleDeactivationAt != nil {
+				t.Errorf("stored user still deactivated: deleted_at %v, scheduled %v", stored.DeletedAt, stored.ScheduleDeactivationAt)
+			}
+			if user.Role.Name != "Member" {
+				t.Errorf("role = %q, want Member to be kept", user.Role.Name)
+			}
+			if len(restored) != 1 || restored[0].UserID != "user-1" {
+				t.Errorf("user.restored events = %+v, want one for user-1", restored)
+			}
+		})
+	}
+}
+
+func TestCheckTokenRevokesTokensIssuedBeforePasswordChange(t *testing.T) {
+	changedAt := time.Date(2026, 3, 1, 12, 0, 0, 700_000_000, time.UTC)
+	deleted := gorm.DeletedAt{Time: changedAt, Valid: true}
+
+	tests := []struct {
+		name     string
+		user     models.User
+		issuedAt time.Time
+		wantErr  error
+	}{
+		{
+			name:     "password never changed",
+			user:     models.User{BaseModel: model.BaseModel{ID: "user-1"}},
+			issuedAt: changedAt.Add(-time.Hour),
+		},
+		{
+			name:     "issued before the change",
+			user:     models.User{BaseModel: model.BaseModel{ID: "user-1"}, PasswordChangedAt: &changedAt},
+			issuedAt: changedAt.Add(-time.Minute),
+			wantErr:  shared.ErrTokenRevoked,
+		},
+		{
+			name:     "issued in the same second",
+			user:     models.User{BaseModel: model.BaseModel{ID: "user-1"}, PasswordChangedAt: &changedAt},
+			issuedAt: changedAt.Add(-500 * time.Millisecond),
+			wantErr:  shared.ErrTokenRevoked,
+		},
+		{
+			name:     "issued after the change",
+			user:     models.User{BaseModel: model.BaseModel{ID: "user-1"}, PasswordChangedAt: &changedAt},
+			issuedAt: changedAt.Add(time.Second),
+		},
+		{
+			name:     "deleted user",
+			user:     models.User{BaseModel: model.BaseModel{ID: "user-1", DeletedAt: deleted}},
+			issuedAt: changedAt,
+			wantErr:  shared.ErrTokenRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, users := newTestUserService()
+			users.add(tt.user)
+
+			err := service.CheckToken(context.Background(), &shared.Claims{Subject: "user-1", IssuedAt: tt.issuedAt.Unix()})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckToken error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
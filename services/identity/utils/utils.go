@@ -14,6 +14,12 @@ func Bcrypt(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
+// IsBcryptHash reports whether hash is a bcrypt hash, as imported from other systems
+func IsBcryptHash(hash string) bool {
+	_, err := bcrypt.Cost([]byte(hash))
+	return err == nil
+}
+
 func CompareHashAndPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
@@ -0,0 +1,289 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultAlertInterval is how often alert rules are evaluated
+const DefaultAlertInterval = 30 * time.Second
+
+// AlertRule fires when calls ending with Code exceed Threshold within Window
+type AlertRule struct {
+	// Name identifies the rule in alerts and logs
+	Name string `json:"name"`
+
+	// Code is the gRPC status code counted, e.g. "Unauthenticated" for rejected tokens
+	// or "PermissionDenied" for authorization failures
+	Code string `json:"code"`
+
+	// Method restricts the rule to one full gRPC method (empty counts every method)
+	Method string `json:"method,omitempty"`
+
+	// PerTenant evaluates the threshold separately for every tenant label
+	PerTenant bool `json:"per_tenant,omitempty"`
+
+	Threshold uint64 `json:"threshold"`
+	Window    string `json:"window"`
+
+	window time.Duration
+}
+
+// AlertRulesFile is the format of the file loaded by the AlertEngine
+type AlertRulesFile struct {
+	// Webhook receives every alert as a JSON POST (alerts are only logged when empty)
+	Webhook string      `json:"webhook,omitempty"`
+	Rules   []AlertRule `json:"rules"`
+}
+
+// Alert is the payload posted to the webhook when a rule fires
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Code      string    `json:"code"`
+	Method    string    `json:"method,omitempty"`
+	Tenant    string    `json:"tenant,omitempty"`
+	Count     uint64    `json:"count"`
+	Threshold uint64    `json:"threshold"`
+	Window    string    `json:"window"`
+	FiredAt   time.Time `json:"fired_at"`
+	Server    string    `json:"server"`
+}
+
+// AlertConfig configures the security alert engine
+type AlertConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Metrics is the registry the rules are evaluated against
+	Metrics *Metrics
+
+	// RulesFile is reloaded whenever it changes, so rules can be tuned without a redeploy
+	RulesFile string
+
+	// Interval between evaluations (defaults to DefaultAlertInterval)
+	Interval time.Duration
+
+	// ServerName is added to every alert to identify the server
+	ServerName string
+}
+
+type alertSample struct {
+	at     time.Time
+	counts map[string]uint64
+}
+
+// AlertEngine periodically evaluates AlertRules against the RPC metrics and posts
+// an alert once per burst: a rule only fires again after its count drops below the threshold
+type AlertEngine struct {
+	config *AlertConfig
+	client *http.Client
+
+	mu      sync.Mutex
+	file    AlertRulesFile
+	modTime time.Time
+	samples map[string][]alertSample
+	firing  map[string]bool
+}
+
+// NewAlertEngine creates the engine and loads its rules, failing on an invalid rules file
+func NewAlertEngine(config *AlertConfig) (*AlertEngine, error) {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultAlertInterval
+	}
+
+	e := &AlertEngine{
+		config:  config,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		samples: make(map[string][]alertSample),
+		firing:  make(map[string]bool),
+	}
+	if _, err := e.reload(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Run evaluates the rules every interval until ctx is done
+func (e *AlertEngine) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if changed, err := e.reload(); err != nil {
+				e.config.Logger.Error("Failed to reload alert rules, keeping the previous ones", zap.Error(err))
+			} else if changed {
+				e.config.Logger.Info("Alert rules reloaded", zap.String("file", e.config.RulesFile))
+			}
+			e.evaluate(ctx, now)
+		}
+	}
+}
+
+// reload reads the rules file when its modification time changed
+func (e *AlertEngine) reload() (bool, error) {
+	info, err := os.Stat(e.config.RulesFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if info.ModTime().Equal(e.modTime) {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(e.config.RulesFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read alert rules: %w", err)
+	}
+	var file AlertRulesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return false, fmt.Errorf("failed to parse alert rules: %w", err)
+	}
+	for i := range file.Rules {
+		rule := &file.Rules[i]
+		if rule.Name == "" || rule.Code == "" || rule.Threshold == 0 {
+			return false, fmt.Errorf("alert rule %d: name, code and threshold are required", i)
+		}
+		if rule.window, err = time.ParseDuration(rule.Window); err != nil || rule.window <= 0 {
+			return false, fmt.Errorf("alert rule %s: invalid window %q", rule.Name, rule.Window)
+		}
+	}
+
+	e.file = file
+	e.modTime = info.ModTime()
+	return true, nil
+}
+
+// evaluate compares every rule with the counts of its window and fires new bursts
+func (e *AlertEngine) evaluate(ctx context.Context, now time.Time) {
+	e.mu.Lock()
+	file := e.file
+	var alerts []Alert
+	for _, rule := range file.Rules {
+		counts := e.config.Metrics.codeCounts(rule.Code, rule.Method, rule.PerTenant)
+		baseline := e.record(rule, now, counts)
+
+		for group, count := range counts {
+			key := rule.Name + "\x00" + group
+			delta := count - baseline[group]
+
+			if delta < rule.Threshold {
+				delete(e.firing, key)
+				continue
+			}
+			if e.firing[key] {
+				continue
+			}
+			e.firing[key] = true
+			alerts = append(alerts, Alert{
+				Rule:      rule.Name,
+				Code:      rule.Code,
+				Method:    rule.Method,
+				Tenant:    group,
+				Count:     delta,
+				Threshold: rule.Threshold,
+				Window:    rule.Window,
+				FiredAt:   now,
+				Server:    e.config.ServerName,
+			})
+		}
+	}
+	e.mu.Unlock()
+
+	for _, alert := range alerts {
+		e.fire(ctx, file.Webhook, alert)
+	}
+}
+
+// record stores the counts of a rule and returns the oldest sample still inside its window;
+// callers must hold mu
+func (e *AlertEngine) record(rule AlertRule, now time.Time, counts map[string]uint64) map[string]uint64 {
+	samples := append(e.samples[rule.Name], alertSample{at: now, counts: counts})
+
+	cutoff := now.Add(-rule.window)
+	drop := 0
+	for drop+1 < len(samples) && samples[drop].at.Before(cutoff) {
+		drop++
+	}
+	samples = samples[drop:]
+	e.samples[rule.Name] = samples
+
+	return samples[0].counts
+}
+
+// fire logs the alert and posts it to the webhook
+func (e *AlertEngine) fire(ctx context.Context, webhook string, alert Alert) {
+	e.config.Logger.Warn("Security alert fired",
+		zap.String("alert.rule", alert.Rule),
+		zap.String("alert.code", alert.Code),
+		zap.String("alert.method", alert.Method),
+		zap.String("alert.tenant", alert.Tenant),
+		zap.Uint64("alert.count", alert.Count),
+		zap.Uint64("alert.threshold", alert.Threshold),
+		zap.String("alert.window", alert.Window),
+	)
+	if webhook == "" {
+		return
+	}
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		e.config.Logger.Error("Failed to build alert webhook request", zap.Error(err))
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		e.config.Logger.Error("Failed to deliver alert", zap.String("alert.rule", alert.Rule), zap.Error(err))
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		e.config.Logger.Error("Alert webhook rejected alert",
+			zap.String("alert.rule", alert.Rule),
+			zap.Int("http.status", resp.StatusCode),
+		)
+	}
+}
+
+// codeCounts sums the calls that ended with code, grouped by tenant label when
+// perTenant is set (a single "" group otherwise)
+func (m *Metrics) codeCounts(code, fullMethod string, perTenant bool) map[string]uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[string]uint64)
+	for labels, n := range m.handled {
+		if labels.code != code || (fullMethod != "" && labels.fullMethod() != fullMethod) {
+			continue
+		}
+		group := ""
+		if perTenant {
+			group = labels.tenant
+		}
+		counts[group] += n
+	}
+	return counts
+}
@@ -0,0 +1,290 @@
+package shared
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrTokenMissing is returned when the request carries no bearer token
+	ErrTokenMissing = errors.New("missing bearer token")
+
+	// ErrTokenInvalid is returned for malformed tokens, bad signatures and claim mismatches
+	ErrTokenInvalid = errors.New("invalid token")
+
+	// ErrTokenExpired is returned when the token is past its exp claim or before its nbf claim
+	ErrTokenExpired = errors.New("token expired or not yet valid")
+
+	// ErrTokenRevoked is returned by AuthConfig.CheckToken for tokens that are no longer accepted
+	ErrTokenRevoked = errors.New("token revoked")
+
+	// ErrEmailNotVerified is returned when RequireVerifiedEmail is set and the token lacks the email_verified claim
+	ErrEmailNotVerified = errors.New("email address is not verified")
+)
+
+// AuthConfig configures bearer token validation
+type AuthConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Secret is the HS256 signing key shared with the token issuer
+	Secret Secret
+
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+
+	// Leeway tolerates clock skew when checking exp and nbf
+	Leeway time.Duration
+
+	// PublicMethods are full gRPC method names that do not require a token
+	PublicMethods []string
+
+	// RequireVerifiedEmail rejects tokens of users whose email_verified claim is not set
+	RequireVerifiedEmail bool
+
+	// CheckToken, when set, is called with the claims of every valid token and
+	// rejects it by returning ErrTokenRevoked, e.g. when its subject was deleted
+	CheckToken func(ctx context.Context, claims *Claims) error
+}
+
+// DefaultAuthConfig reads the token settings from JWT_SECRET, JWT_ISSUER and JWT_AUDIENCE
+func DefaultAuthConfig() *AuthConfig {
+	return &AuthConfig{
+		Logger:   GetLogger(),
+		Secret:   Secret(os.Getenv("JWT_SECRET")),
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+		Leeway:   30 * time.Second,
+	}
+}
+
+// Claims are the JWT claims understood by momentum services
+type Claims struct {
+	Subject     string   `json:"sub"`
+	Issuer      string   `json:"iss,omitempty"`
+	Audience    string   `json:"aud,omitempty"`
+	ExpiresAt   int64    `json:"exp"`
+	NotBefore   int64    `json:"nbf,omitempty"`
+	IssuedAt    int64    `json:"iat,omitempty"`
+	TenantID    string   `json:"tenant_id,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+
+	// EmailVerified follows the OpenID Connect claim; issuers set it from the user's email_verified_at
+	EmailVerified bool `json:"email_verified,omitempty"`
+}
+
+// AuthUser is the authenticated caller attached to the request context
+type AuthUser struct {
+	ID          string
+	TenantID    string
+	Roles       []string
+	Permissions []string
+}
+
+// HasPermission reports whether the caller was granted the permission
+func (u *AuthUser) HasPermission(permission string) bool {
+	return slices.Contains(u.Permissions, permission)
+}
+
+type authUserKey struct{}
+
+// ContextWithUser returns a copy of ctx carrying the authenticated user
+func ContextWithUser(ctx context.Context, user *AuthUser) context.Context {
+	recordUser(ctx, user)
+	return context.WithValue(ctx, authUserKey{}, user)
+}
+
+// UserFromContext returns the user authenticated by AuthUnaryInterceptor
+func UserFromContext(ctx context.Context) (*AuthUser, bool) {
+	user, ok := ctx.Value(authUserKey{}).(*AuthUser)
+	return user, ok
+}
+
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SignToken issues an HS256 JWT for the given claims
+func SignToken(claims *Claims, secret Secret) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signHS256(signingInput, secret)), nil
+}
+
+// ParseToken verifies an HS256 JWT and validates its time, issuer and audience claims
+func ParseToken(token string, config *AuthConfig) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenInvalid
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, ErrTokenInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, signHS256(parts[0]+"."+parts[1], config.Secret)) {
+		return nil, ErrTokenInvalid
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" {
+		return nil, ErrTokenInvalid
+	}
+
+	now := time.Now()
+	if claims.ExpiresAt == 0 || now.Add(-config.Leeway).Unix() > claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(config.Leeway).Unix() < claims.NotBefore {
+		return nil, ErrTokenExpired
+	}
+	if config.Issuer != "" && claims.Issuer != config.Issuer {
+		return nil, ErrTokenInvalid
+	}
+	if config.Audience != "" && claims.Audience != config.Audience {
+		return nil, ErrTokenInvalid
+	}
+
+	return &claims, nil
+}
+
+func signHS256(signingInput string, secret Secret) []byte {
+	mac := hmac.New(sha256.New, []byte(secret.Reveal()))
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// bearerToken extracts the token from the "authorization: Bearer <token>" metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ErrTokenMissing
+	}
+
+	for _, value := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(value, " ")
+		if found && strings.EqualFold(scheme, "bearer") && token != "" {
+			return strings.TrimSpace(token), nil
+		}
+	}
+	return "", ErrTokenMissing
+}
+
+// authenticate validates the bearer token of ctx and returns a context carrying the caller
+func authenticate(ctx context.Context, config *AuthConfig, method string) (context.Context, error) {
+	if slices.Contains(config.PublicMethods, method) {
+		return ctx, nil
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, ReasonError(codes.Unauthenticated, proto.ErrorReason_TOKEN_MISSING, err.Error())
+	}
+
+	claims, err := ParseToken(token, config)
+	if err != nil {
+		config.Logger.Debug("Rejected bearer token",
+			zap.String("grpc.method", method),
+			zap.Error(err),
+		)
+		reason := proto.ErrorReason_TOKEN_INVALID
+		if errors.Is(err, ErrTokenExpired) {
+			reason = proto.ErrorReason_TOKEN_EXPIRED
+		}
+		return nil, ReasonError(codes.Unauthenticated, reason, err.Error())
+	}
+	if config.RequireVerifiedEmail && !claims.EmailVerified {
+		return nil, ReasonError(codes.PermissionDenied, proto.ErrorReason_EMAIL_NOT_VERIFIED, ErrEmailNotVerified.Error())
+	}
+	if config.CheckToken != nil {
+		if err := config.CheckToken(ctx, claims); errors.Is(err, ErrTokenRevoked) {
+			config.Logger.Debug("Rejected revoked token",
+				zap.String("grpc.method", method),
+				zap.String("user.id", claims.Subject),
+			)
+			return nil, ReasonError(codes.Unauthenticated, proto.ErrorReason_TOKEN_REVOKED, err.Error())
+		} else if err != nil {
+			config.Logger.Error("Failed to check bearer token", zap.String("grpc.method", method), zap.Error(err))
+			return nil, status.Error(codes.Internal, "failed to check token")
+		}
+	}
+
+	return ContextWithUser(ctx, &AuthUser{
+		ID:          claims.Subject,
+		TenantID:    claims.TenantID,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+	}), nil
+}
+
+// AuthUnaryInterceptor validates the bearer token of every call outside
+// PublicMethods and makes the caller available through UserFromContext
+func AuthUnaryInterceptor(config *AuthConfig) grpc.UnaryServerInterceptor {
+	if config == nil {
+		config = DefaultAuthConfig()
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, config, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is the streaming counterpart of AuthUnaryInterceptor
+func AuthStreamInterceptor(config *AuthConfig) grpc.StreamServerInterceptor {
+	if config == nil {
+		config = DefaultAuthConfig()
+	}
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), config, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextServerStream overrides the context of a server stream
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
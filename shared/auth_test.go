@@ -0,0 +1,195 @@
+package shared
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = Secret("test-secret")
+
+func testAuthConfig() *AuthConfig {
+	return &AuthConfig{
+		Logger:   zap.NewNop(),
+		Secret:   testSecret,
+		Issuer:   "momentum",
+		Audience: "api",
+		Leeway:   30 * time.Second,
+	}
+}
+
+// testClaims returns claims accepted by testAuthConfig, changed by edit
+func testClaims(edit func(c *Claims)) *Claims {
+	now := time.Now()
+	claims := &Claims{
+		Subject:     "user-1",
+		Issuer:      "momentum",
+		Audience:    "api",
+		IssuedAt:    now.Unix(),
+		ExpiresAt:   now.Add(time.Hour).Unix(),
+		Permissions: []string{"user.view"},
+	}
+	if edit != nil {
+		edit(claims)
+	}
+	return claims
+}
+
+func signTestToken(t *testing.T, claims *Claims, secret Secret) string {
+	t.Helper()
+	token, err := SignToken(claims, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// tokenWithAlg signs valid claims with HS256 but declares alg in the header
+func tokenWithAlg(t *testing.T, alg string) string {
+	t.Helper()
+	payload, err := json.Marshal(testClaims(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"`+alg+`","typ":"JWT"}`)) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signHS256(signingInput, testSecret))
+}
+
+func TestParseToken(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+	}{
+		{"valid", func(t *testing.T) string { return signTestToken(t, testClaims(nil), testSecret) }, nil},
+		{"bad signature", func(t *testing.T) string { return signTestToken(t, testClaims(nil), "other-secret") }, ErrTokenInvalid},
+		{"alg HS256", func(t *testing.T) string { return tokenWithAlg(t, "HS256") }, nil},
+		{"alg none", func(t *testing.T) string { return tokenWithAlg(t, "none") }, ErrTokenInvalid},
+		{"alg HS512", func(t *testing.T) string { return tokenWithAlg(t, "HS512") }, ErrTokenInvalid},
+		{"malformed", func(t *testing.T) string { return "not-a-token" }, ErrTokenInvalid},
+		{"missing subject", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.Subject = "" }), testSecret)
+		}, ErrTokenInvalid},
+		{"expired", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.ExpiresAt = now.Add(-time.Minute).Unix() }), testSecret)
+		}, ErrTokenExpired},
+		{"expired within leeway", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.ExpiresAt = now.Add(-10 * time.Second).Unix() }), testSecret)
+		}, nil},
+		{"without exp", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.ExpiresAt = 0 }), testSecret)
+		}, ErrTokenExpired},
+		{"not yet valid", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.NotBefore = now.Add(time.Minute).Unix() }), testSecret)
+		}, ErrTokenExpired},
+		{"wrong issuer", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.Issuer = "other" }), testSecret)
+		}, ErrTokenInvalid},
+		{"wrong audience", func(t *testing.T) string {
+			return signTestToken(t, testClaims(func(c *Claims) { c.Audience = "other" }), testSecret)
+		}, ErrTokenInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseToken(tt.token(t), testAuthConfig())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseToken error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && claims.Subject != "user-1" {
+				t.Fatalf("subject = %q, want user-1", claims.Subject)
+			}
+		})
+	}
+}
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	errCheckFailed := errors.New("database is down")
+	checkToken := func(ctx context.Context, claims *Claims) error {
+		switch claims.Subject {
+		case "deleted":
+			return ErrTokenRevoked
+		case "unknown":
+			return errCheckFailed
+		}
+		return nil
+	}
+	bearer := func(t *testing.T, claims *Claims) string {
+		return "Bearer " + signTestToken(t, claims, testSecret)
+	}
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization func(t *testing.T) string
+		wantCode      codes.Code
+		wantReason    proto.ErrorReason
+	}{
+		{"valid", "/svc/Private", func(t *testing.T) string { return bearer(t, testClaims(nil)) }, codes.OK, 0},
+		{"public method", "/svc/Public", func(t *testing.T) string { return "" }, codes.OK, 0},
+		{"missing token", "/svc/Private", func(t *testing.T) string { return "" }, codes.Unauthenticated, proto.ErrorReason_TOKEN_MISSING},
+		{"bad signature", "/svc/Private", func(t *testing.T) string {
+			return "Bearer " + signTestToken(t, testClaims(nil), "other-secret")
+		}, codes.Unauthenticated, proto.ErrorReason_TOKEN_INVALID},
+		{"alg none", "/svc/Private", func(t *testing.T) string { return "Bearer " + tokenWithAlg(t, "none") }, codes.Unauthenticated, proto.ErrorReason_TOKEN_INVALID},
+		{"expired", "/svc/Private", func(t *testing.T) string {
+			return bearer(t, testClaims(func(c *Claims) { c.ExpiresAt = time.Now().Add(-time.Hour).Unix() }))
+		}, codes.Unauthenticated, proto.ErrorReason_TOKEN_EXPIRED},
+		{"not yet valid", "/svc/Private", func(t *testing.T) string {
+			return bearer(t, testClaims(func(c *Claims) { c.NotBefore = time.Now().Add(time.Hour).Unix() }))
+		}, codes.Unauthenticated, proto.ErrorReason_TOKEN_EXPIRED},
+		{"wrong issuer", "/svc/Private", func(t *testing.T) string {
+			return bearer(t, testClaims(func(c *Claims) { c.Issuer = "other" }))
+		}, codes.Unauthenticated, proto.ErrorReason_TOKEN_INVALID},
+		{"wrong audience", "/svc/Private", func(t *testing.T) string {
+			return bearer(t, testClaims(func(c *Claims) { c.Audience = "other" }))
+		}, codes.Unauthenticated, proto.ErrorReason_TOKEN_INVALID},
+		{"revoked", "/svc/Private", func(t *testing.T) string {
+			return bearer(t, testClaims(func(c *Claims) { c.Subject = "deleted" }))
+		}, codes.Unauthenticated, proto.ErrorReason_TOKEN_REVOKED},
+		{"revocation check failed", "/svc/Private", func(t *testing.T) string {
+			return bearer(t, testClaims(func(c *Claims) { c.Subject = "unknown" }))
+		}, codes.Internal, proto.ErrorReason_ERROR_REASON_UNSPECIFIED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testAuthConfig()
+			config.PublicMethods = []string{"/svc/Public"}
+			config.CheckToken = checkToken
+			interceptor := AuthUnaryInterceptor(config)
+
+			ctx := context.Background()
+			if authorization := tt.authorization(t); authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+			}
+			var user *AuthUser
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				user, _ = UserFromContext(ctx)
+				return nil, nil
+			})
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v: %v", code, tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				if reason := ErrorReason(err); reason != tt.wantReason {
+					t.Fatalf("reason = %v, want %v", reason, tt.wantReason)
+				}
+				return
+			}
+			if tt.method == "/svc/Private" && (user == nil || user.ID != "user-1" || !user.HasPermission("user.view")) {
+				t.Fatalf("handler saw user %+v, want user-1 with its permissions", user)
+			}
+		})
+	}
+}
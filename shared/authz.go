@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationConfig maps gRPC methods to the permission they require
+type AuthorizationConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// MethodPermissions maps full gRPC method names to a required permission.
+	// Methods missing from the map are denied unless listed in PublicMethods.
+	MethodPermissions map[string]string
+
+	// PublicMethods are full gRPC method names that skip authorization
+	PublicMethods []string
+}
+
+// RequirePermission fails unless the authenticated caller holds the permission.
+// Service code uses it for checks that depend on the request, not only the method.
+func RequirePermission(ctx context.Context, permission string) error {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return ReasonError(codes.Unauthenticated, proto.ErrorReason_TOKEN_MISSING, ErrTokenMissing.Error())
+	}
+	if !user.HasPermission(permission) {
+		st := status.Newf(codes.PermissionDenied, "missing permission %q", permission)
+		return withReason(st, proto.ErrorReason_PERMISSION_MISSING, map[string]string{"permission": permission}).Err()
+	}
+	return nil
+}
+
+// authorize checks the permission declared for the method
+func (c *AuthorizationConfig) authorize(ctx context.Context, method string) error {
+	if slices.Contains(c.PublicMethods, method) {
+		return nil
+	}
+
+	permission, ok := c.MethodPermissions[method]
+	if !ok {
+		c.Logger.Warn("Denied call to method without a declared permission", zap.String("grpc.method", method))
+		return status.Error(codes.PermissionDenied, "method has no declared permission")
+	}
+	return RequirePermission(ctx, permission)
+}
+
+// PermissionUnaryInterceptor enforces MethodPermissions; it must run after AuthUnaryInterceptor
+func PermissionUnaryInterceptor(config *AuthorizationConfig) grpc.UnaryServerInterceptor {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := config.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// PermissionStreamInterceptor enforces MethodPermissions; it must run after AuthStreamInterceptor
+func PermissionStreamInterceptor(config *AuthorizationConfig) grpc.StreamServerInterceptor {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := config.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
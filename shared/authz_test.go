@@ -0,0 +1,95 @@
+package shared
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func testAuthorizationConfig() *AuthorizationConfig {
+	return &AuthorizationConfig{
+		Logger: zap.NewNop(),
+		MethodPermissions: map[string]string{
+			"/svc/StoreUser":  "user.store",
+			"/svc/DeleteUser": "user.delete",
+		},
+		PublicMethods: []string{"/svc/Public"},
+	}
+}
+
+// callPermission runs the interceptor on method and reports whether the handler ran
+func callPermission(ctx context.Context, interceptor grpc.UnaryServerInterceptor, method string) (bool, error) {
+	called := false
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+	return called, err
+}
+
+func TestPermissionUnaryInterceptor(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		user       *AuthUser
+		wantCode   codes.Code
+		wantReason proto.ErrorReason
+	}{
+		{"allowed", "/svc/StoreUser", &AuthUser{ID: "user-1", Permissions: []string{"user.store"}}, codes.OK, 0},
+		{"denied", "/svc/DeleteUser", &AuthUser{ID: "user-1", Permissions: []string{"user.store"}}, codes.PermissionDenied, proto.ErrorReason_PERMISSION_MISSING},
+		{"unauthenticated", "/svc/StoreUser", nil, codes.Unauthenticated, proto.ErrorReason_TOKEN_MISSING},
+		{"undeclared method", "/svc/Undeclared", &AuthUser{ID: "user-1", Permissions: []string{"user.store", "user.delete"}}, codes.PermissionDenied, proto.ErrorReason_ERROR_REASON_UNSPECIFIED},
+		{"public method", "/svc/Public", nil, codes.OK, 0},
+	}
+	interceptor := PermissionUnaryInterceptor(testAuthorizationConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.user != nil {
+				ctx = ContextWithUser(ctx, tt.user)
+			}
+			called, err := callPermission(ctx, interceptor, tt.method)
+
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %v, want %v: %v", code, tt.wantCode, err)
+			}
+			if called != (tt.wantCode == codes.OK) {
+				t.Fatalf("handler called = %v with code %v", called, tt.wantCode)
+			}
+			if reason := ErrorReason(err); tt.wantCode != codes.OK && reason != tt.wantReason {
+				t.Fatalf("reason = %v, want %v", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+// A service token is a regular JWT without tenant, limited to the permissions of its calls
+func TestPermissionUnaryInterceptorServiceToken(t *testing.T) {
+	token := signTestToken(t, testClaims(func(c *Claims) {
+		c.Subject = "svc-gateway"
+		c.Permissions = []string{"user.store"}
+	}), testSecret)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+
+	auth := AuthUnaryInterceptor(testAuthConfig())
+	permission := PermissionUnaryInterceptor(testAuthorizationConfig())
+	chain := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return auth(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return permission(ctx, req, info, handler)
+		})
+	}
+
+	if called, err := callPermission(ctx, chain, "/svc/StoreUser"); err != nil || !called {
+		t.Fatalf("service token call to a granted method = %v, handler called = %v", err, called)
+	}
+	called, err := callPermission(ctx, chain, "/svc/DeleteUser")
+	if status.Code(err) != codes.PermissionDenied || ErrorReason(err) != proto.ErrorReason_PERMISSION_MISSING || called {
+		t.Fatalf("service token call to another method = %v, handler called = %v", err, called)
+	}
+}
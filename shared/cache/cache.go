@@ -0,0 +1,98 @@
+// Package cache provides a key-value cache with per-entry TTLs, backed by Redis
+// or by process memory.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache stores byte values by key. Implementations are safe for concurrent use;
+// a cache failure should be treated as a miss, not as a request failure.
+type Cache interface {
+	// Get returns the value and true, or false when the key is missing or expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores the value for ttl, or without expiration when ttl <= 0
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the keys; missing keys are ignored
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// sweepEvery is the number of Set calls between removals of expired entries
+const sweepEvery = 1024
+
+// Memory is an in-process Cache; each replica keeps its own entries, so an
+// invalidation on one replica is not seen by the others before the TTL
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	sets    int
+}
+
+type memoryEntry struct {
+	value []byte
+	// expiresAt is zero for entries without expiration
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// NewMemory creates an empty in-memory cache
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry)}
+}
+
+// Get implements Cache
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if entry.expired(time.Now()) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implements Cache
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+	m.entries[key] = entry
+
+	m.sets++
+	if m.sets%sweepEvery == 0 {
+		for k, entry := range m.entries {
+			if entry.expired(now) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
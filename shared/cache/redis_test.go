@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers +OK to every command and sends each command's arguments to commands
+func fakeRedis(t *testing.T) (*Redis, <-chan []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	commands := make(chan []string, 16)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			args, err := readCommand(reader)
+			if err != nil {
+				return
+			}
+			commands <- args
+			conn.Write([]byte("+OK\r\n"))
+		}
+	}()
+
+	r := NewRedis(listener.Addr().String(), "", 0)
+	t.Cleanup(func() { r.Close() })
+	return r, commands
+}
+
+// readCommand reads an array of bulk strings, as sent by redisConn.exchange
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, count)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestRedisSetTTL(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		want string
+	}{
+		{"milliseconds", 1500 * time.Millisecond, "SET k v PX 1500"},
+		{"below a millisecond", 300 * time.Microsecond, "SET k v PX 1"},
+		{"zero", 0, "SET k v"},
+		{"negative", -time.Second, "SET k v"},
+	}
+	r, commands := fakeRedis(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Set(context.Background(), "k", []byte("v"), tt.ttl); err != nil {
+				t.Fatalf("Set: %v", err)
+			}
+			if got := strings.Join(<-commands, " "); got != tt.want {
+				t.Fatalf("command = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemorySetWithoutTTL(t *testing.T) {
+	m := NewMemory()
+	m.Set(context.Background(), "k", []byte("v"), 0)
+	if _, ok, _ := m.Get(context.Background(), "k"); !ok {
+		t.Fatal("an entry set without TTL expired")
+	}
+}
@@ -0,0 +1,113 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// ClientConfig configures connections created by NewClient
+type ClientConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Name identifies the calling service to servers, e.g. in deprecated method usage (optional)
+	Name string
+
+	// Version is sent to servers and compared with theirs (defaults to Version)
+	Version string
+
+	// Options are appended to the defaults, e.g. transport credentials replacing the insecure ones
+	Options []grpc.DialOption
+}
+
+// NewClient creates a connection to another service. Every call carries the client
+// name and version, and a warning is logged once per server version that is not compatible.
+// Calls made while serving an RPC count towards its cost (see CostUnaryInterceptor).
+func NewClient(target string, config *ClientConfig) (*grpc.ClientConn, error) {
+	if config == nil {
+		config = &ClientConfig{}
+	}
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+	if config.Version == "" {
+		config.Version = Version
+	}
+
+	checker := &versionChecker{
+		logger:  config.Logger.With(zap.String("grpc.target", target)),
+		version: config.Version,
+		headers: []string{ClientVersionMetadataKey, config.Version},
+	}
+	if config.Name != "" {
+		checker.headers = append(checker.headers, ClientNameMetadataKey, config.Name)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(checker.unary, costUnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(checker.stream, costStreamClientInterceptor),
+	}
+	return grpc.NewClient(target, append(opts, config.Options...)...)
+}
+
+// versionChecker compares the version header of responses with the client version
+type versionChecker struct {
+	logger  *zap.Logger
+	version string
+	headers []string
+	warned  sync.Map
+}
+
+func (c *versionChecker) unary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	ctx = metadata.AppendToOutgoingContext(ctx, c.headers...)
+	err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...)
+	c.check(header)
+	return err
+}
+
+func (c *versionChecker) stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, c.headers...)
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &versionCheckedStream{ClientStream: stream, checker: c}, nil
+}
+
+// check warns once per incompatible server version
+func (c *versionChecker) check(header metadata.MD) {
+	values := header.Get(ServerVersionMetadataKey)
+	if len(values) == 0 || CompatibleVersions(c.version, values[0]) {
+		return
+	}
+	if _, warned := c.warned.LoadOrStore(values[0], struct{}{}); !warned {
+		c.logger.Warn("Server version is not compatible with this client",
+			zap.String("client_version", c.version),
+			zap.String("server_version", values[0]),
+		)
+	}
+}
+
+// versionCheckedStream checks the server version once the first message arrived,
+// when reading the header no longer blocks
+type versionCheckedStream struct {
+	grpc.ClientStream
+	checker *versionChecker
+	once    sync.Once
+}
+
+func (s *versionCheckedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.once.Do(func() {
+		if header, headerErr := s.ClientStream.Header(); headerErr == nil {
+			s.checker.check(header)
+		}
+	})
+	return err
+}
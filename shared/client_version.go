@@ -0,0 +1,103 @@
+package shared
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ClientVersionTooOldReason is the ErrorInfo reason of calls rejected by the minimum client version
+const ClientVersionTooOldReason = "CLIENT_VERSION_TOO_OLD"
+
+// ErrClientVersionTooOld is returned when a client is older than the server's minimum version
+var ErrClientVersionTooOld = errors.New("client version is no longer supported")
+
+// ClientVersionConfig configures the minimum client version enforcement
+type ClientVersionConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// MinVersion is the oldest x-client-version accepted. Calls without the header,
+	// such as external callers, and development builds are always accepted.
+	MinVersion string
+}
+
+// checkClientVersion rejects calls whose client version is older than the minimum
+func checkClientVersion(ctx context.Context, config *ClientVersionConfig, method string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(ClientVersionMetadataKey)
+	if len(values) == 0 {
+		return nil
+	}
+
+	if cmp, ok := CompareVersions(values[0], config.MinVersion); !ok || cmp >= 0 {
+		return nil
+	}
+
+	config.Logger.Warn("Rejected outdated client",
+		zap.String("grpc.method", method),
+		zap.String("client_version", values[0]),
+		zap.String("min_client_version", config.MinVersion),
+	)
+	return clientVersionError(values[0], config.MinVersion)
+}
+
+// clientVersionError builds a FailedPrecondition status carrying the required version
+// in an ErrorInfo detail, so clients can tell the caller what to upgrade to
+func clientVersionError(clientVersion, minVersion string) error {
+	st := status.Newf(codes.FailedPrecondition, "%s: %s is older than the required %s", ErrClientVersionTooOld, clientVersion, minVersion)
+	return withReason(st, proto.ErrorReason_CLIENT_VERSION_TOO_OLD, map[string]string{
+		"client_version":   clientVersion,
+		"required_version": minVersion,
+	}).Err()
+}
+
+// RequiredClientVersion returns the minimum version named by an error returned for
+// an outdated client
+func RequiredClientVersion(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "", false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetReason() == ClientVersionTooOldReason {
+			return info.GetMetadata()["required_version"], true
+		}
+	}
+	return "", false
+}
+
+// MinClientVersionUnaryInterceptor rejects calls from clients older than MinVersion
+func MinClientVersionUnaryInterceptor(config *ClientVersionConfig) grpc.UnaryServerInterceptor {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := checkClientVersion(ctx, config, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// MinClientVersionStreamInterceptor is the streaming counterpart of MinClientVersionUnaryInterceptor
+func MinClientVersionStreamInterceptor(config *ClientVersionConfig) grpc.StreamServerInterceptor {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkClientVersion(ss.Context(), config, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// breaker is a consecutive-failures circuit breaker. Once open, calls fail fast
+// until the open timeout elapses; then one probe call decides whether it closes.
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	logger      *zap.Logger
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(threshold int, openTimeout time.Duration, logger *zap.Logger) *breaker {
+	return &breaker{threshold: threshold, openTimeout: openTimeout, logger: logger}
+}
+
+// allow reports whether a call may be made
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record updates the breaker with the result of a call
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasOpen := b.failures >= b.threshold
+	b.probing = false
+	if !isFailure(err) {
+		if wasOpen {
+			b.logger.Info("Identity circuit breaker closed")
+		}
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.openTimeout)
+		if !wasOpen {
+			b.logger.Warn("Identity circuit breaker opened",
+				zap.Int("failures", b.failures),
+				zap.Duration("open_timeout", b.openTimeout),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// isFailure reports whether err means identity is unhealthy, as opposed to an
+// error answering the request
+func isFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
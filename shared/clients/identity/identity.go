@@ -0,0 +1,188 @@
+// Package identity is the IdentityService client for other momentum services.
+// It dials with shared.NewClient and adds default deadlines, retries with backoff,
+// a circuit breaker and forwarding of the caller's bearer token, or of a service
+// token for calls made outside of a request.
+package identity
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultTimeout is the deadline of unary calls whose context has none
+	DefaultTimeout = 5 * time.Second
+
+	// DefaultMaxAttempts is how many times a unary call is tried
+	DefaultMaxAttempts = 3
+
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 2 * time.Second
+
+	// DefaultFailureThreshold is how many consecutive failures open the circuit
+	DefaultFailureThreshold = 5
+
+	// DefaultOpenTimeout is how long the circuit stays open before a probe call
+	DefaultOpenTimeout = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling the server while the circuit is open
+var ErrCircuitOpen = status.Error(codes.Unavailable, "identity circuit breaker is open")
+
+// Config configures the client; zero values use the defaults
+type Config struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Name identifies the calling service, see shared.ClientConfig
+	Name string
+
+	// Timeout is the deadline of unary calls whose context has none. Streams are
+	// not bounded, as they follow long-running operations.
+	Timeout time.Duration
+
+	// MaxAttempts bounds the tries of a unary call failing with Unavailable, which
+	// gRPC returns when the call was not processed; mutations are retried too
+	MaxAttempts int
+
+	// InitialBackoff doubles after each retry up to MaxBackoff, with jitter
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// FailureThreshold consecutive Unavailable or DeadlineExceeded errors open the
+	// circuit for OpenTimeout; a single call then probes whether identity recovered
+	FailureThreshold int
+	OpenTimeout      time.Duration
+
+	// ServiceToken is the bearer token of the calling service, sent on calls that
+	// have no caller token to forward, e.g. those made from HTTP webhooks or jobs
+	ServiceToken shared.Secret
+
+	// Options are passed to shared.NewClient, e.g. transport credentials
+	Options []grpc.DialOption
+}
+
+// Client is an IdentityServiceClient owning its connection
+type Client struct {
+	proto.IdentityServiceClient
+	conn *grpc.ClientConn
+}
+
+// New creates a client for the identity service at target
+func New(target string, config *Config) (*Client, error) {
+	if config == nil {
+		config = &Config{}
+	}
+	if config.Logger == nil {
+		config.Logger = shared.GetLogger()
+	}
+	if config.Timeout == 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = DefaultInitialBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = max(DefaultMaxBackoff, config.InitialBackoff)
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultOpenTimeout
+	}
+
+	c := &caller{
+		config:  config,
+		breaker: newBreaker(config.FailureThreshold, config.OpenTimeout, config.Logger.With(zap.String("grpc.target", target))),
+	}
+	opts := append([]grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(c.unary),
+		grpc.WithChainStreamInterceptor(c.stream),
+	}, config.Options...)
+
+	conn, err := shared.NewClient(target, &shared.ClientConfig{Logger: config.Logger, Name: config.Name, Options: opts})
+	if err != nil {
+		return nil, err
+	}
+	return &Client{IdentityServiceClient: proto.NewIdentityServiceClient(conn), conn: conn}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// caller applies the deadline, retry and circuit breaker policies to each call
+type caller struct {
+	config  *Config
+	breaker *breaker
+}
+
+func (c *caller) unary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = c.authorize(ctx)
+	if _, ok := ctx.Deadline(); !ok && c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	backoff := c.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		if !c.breaker.allow() {
+			return ErrCircuitOpen
+		}
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		c.breaker.record(err)
+		if err == nil || attempt >= c.config.MaxAttempts || status.Code(err) != codes.Unavailable {
+			return err
+		}
+
+		// Jitter over the upper half of the backoff spreads retries of concurrent callers
+		wait := backoff/2 + rand.N(backoff/2+1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, c.config.MaxBackoff)
+	}
+}
+
+func (c *caller) stream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if !c.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+	stream, err := streamer(c.authorize(ctx), desc, cc, method, opts...)
+	c.breaker.record(err)
+	return stream, err
+}
+
+// authorize copies the authorization metadata of the request being served to the
+// outgoing call, unless the caller already set one, and falls back to the service token
+func (c *caller) authorize(ctx context.Context) context.Context {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		return ctx
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			return metadata.AppendToOutgoingContext(ctx, "authorization", values[0])
+		}
+	}
+	if token := c.config.ServiceToken.Reveal(); token != "" {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+	return ctx
+}
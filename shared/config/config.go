@@ -0,0 +1,350 @@
+// Package config holds the typed configuration shared by every momentum service.
+// Each section is loaded from the environment with its defaults and validated at
+// startup, so a misconfigured service reports every problem before serving traffic.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared"
+	"github.com/gabehamasaki/momentum/shared/cache"
+	"github.com/gabehamasaki/momentum/shared/events"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap"
+)
+
+// FileEnv is the variable pointing to an optional env file loaded before the configuration
+const FileEnv = "CONFIG_FILE"
+
+// LoadFile loads the env file named by CONFIG_FILE, if any. Variables already set in
+// the process environment win over the file.
+func LoadFile(env *shared.Env) {
+	path := os.Getenv(FileEnv)
+	if path == "" {
+		return
+	}
+	if err := godotenv.Load(path); err != nil {
+		env.Invalid(FileEnv, fmt.Sprintf("failed to load config file: %v", err))
+	}
+}
+
+// Server configures the listeners of a service
+type Server struct {
+	// Environment (development, production, staging)
+	Environment string
+
+	// GRPCPort, HTTPPort and MetricsPort are left empty when the service does not listen on them
+	GRPCPort    string
+	HTTPPort    string
+	MetricsPort string
+
+	// MinClientVersion rejects internal clients reporting an older x-client-version (empty accepts every client)
+	MinClientVersion string
+
+	// DeprecatedMethods answer with a Warning header and count their callers
+	DeprecatedMethods []shared.Deprecation
+
+	// ShutdownTimeout bounds the drain of in-flight calls and workers on shutdown
+	ShutdownTimeout time.Duration
+}
+
+// IsProduction reports whether the service runs in production
+func (s Server) IsProduction() bool {
+	return s.Environment == "production"
+}
+
+// LoadServer reads ENVIRONMENT, MIN_CLIENT_VERSION, DEPRECATED_METHODS, SHUTDOWN_TIMEOUT and the <prefix>_GRPC_PORT,
+// <prefix>_HTTP_PORT and <prefix>_METRICS_PORT variables; ports without a default are not read
+func LoadServer(env *shared.Env, prefix string, defaults Server) Server {
+	server := Server{
+		Environment:      env.String("ENVIRONMENT", "development"),
+		MinClientVersion: env.String("MIN_CLIENT_VERSION", ""),
+		ShutdownTimeout:  env.Duration("SHUTDOWN_TIMEOUT", shared.DefaultDrainTimeout),
+	}
+	if server.MinClientVersion != "" {
+		if _, ok := shared.CompareVersions(server.MinClientVersion, server.MinClientVersion); !ok {
+			env.Invalid("MIN_CLIENT_VERSION", fmt.Sprintf("%q is not a semantic version", server.MinClientVersion))
+		}
+	}
+	deprecated, err := shared.ParseDeprecations(env.String("DEPRECATED_METHODS", ""))
+	if err != nil {
+		env.Invalid("DEPRECATED_METHODS", err.Error())
+	}
+	server.DeprecatedMethods = deprecated
+
+	ports := []struct {
+		suffix   string
+		value    *string
+		fallback string
+	}{
+		{"_GRPC_PORT", &server.GRPCPort, defaults.GRPCPort},
+		{"_HTTP_PORT", &server.HTTPPort, defaults.HTTPPort},
+		{"_METRICS_PORT", &server.MetricsPort, defaults.MetricsPort},
+	}
+	for _, port := range ports {
+		if port.fallback == "" {
+			continue
+		}
+		key := prefix + port.suffix
+		*port.value = env.String(key, port.fallback)
+		if n, err := strconv.Atoi(*port.value); err != nil || n < 1 || n > 65535 {
+			env.Invalid(key, fmt.Sprintf("%q is not a valid port", *port.value))
+		}
+	}
+
+	return server
+}
+
+// Database configures the primary database connection
+type Database struct {
+	DSN shared.Secret
+
+	// Candidates are extra DSNs probed to follow a failover
+	Candidates []string
+
+	// Replicas are read replica DSNs serving queries that tolerate replication lag
+	Replicas []string
+
+	AutoMigrate           bool
+	TopologyCheckInterval time.Duration
+
+	// StatementBudget flags RPCs running more SQL statements than this (0 disables)
+	StatementBudget int
+}
+
+// LoadDatabase reads <prefix>_DSN, <prefix>_DSN_CANDIDATES, <prefix>_REPLICA_DSNS,
+// <prefix>_AUTO_MIGRATE, <prefix>_TOPOLOGY_CHECK_INTERVAL and SQL_STATEMENT_BUDGET
+func LoadDatabase(env *shared.Env, prefix string) Database {
+	database := Database{
+		DSN:                   env.Secret(prefix+"_DSN", true),
+		Candidates:            shared.SplitList(env.String(prefix+"_DSN_CANDIDATES", "")),
+		Replicas:              shared.SplitList(env.String(prefix+"_REPLICA_DSNS", "")),
+		AutoMigrate:           env.Bool(prefix+"_AUTO_MIGRATE", true),
+		TopologyCheckInterval: env.Duration(prefix+"_TOPOLOGY_CHECK_INTERVAL", 15*time.Second),
+		StatementBudget:       env.Int("SQL_STATEMENT_BUDGET", 0),
+	}
+
+	if database.TopologyCheckInterval <= 0 {
+		env.Invalid(prefix+"_TOPOLOGY_CHECK_INTERVAL", "must be positive")
+	}
+	if database.StatementBudget < 0 {
+		env.Invalid("SQL_STATEMENT_BUDGET", "must not be negative")
+	}
+
+	return database
+}
+
+// Cache selects the shared cache: Redis at RedisAddr, or process memory when unset
+type Cache struct {
+	RedisAddr     string
+	RedisPassword shared.Secret
+	RedisDB       int
+}
+
+// LoadCache reads REDIS_ADDR, REDIS_PASSWORD and REDIS_DB
+func LoadCache(env *shared.Env) Cache {
+	c := Cache{
+		RedisAddr:     env.String("REDIS_ADDR", ""),
+		RedisPassword: env.Secret("REDIS_PASSWORD", false),
+		RedisDB:       env.Int("REDIS_DB", 0),
+	}
+	if c.RedisDB < 0 {
+		env.Invalid("REDIS_DB", "must not be negative")
+	}
+	return c
+}
+
+// New creates the configured cache
+func (c Cache) New() cache.Cache {
+	if c.RedisAddr == "" {
+		return cache.NewMemory()
+	}
+	return cache.NewRedis(c.RedisAddr, c.RedisPassword.Reveal(), c.RedisDB)
+}
+
+// Events configures the event bus; events are only logged when NATSURL is unset
+type Events struct {
+	// NATSURL is nats://[user:password@]host:port
+	NATSURL shared.Secret
+}
+
+// LoadEvents reads NATS_URL
+func LoadEvents(env *shared.Env) Events {
+	return Events{NATSURL: env.Secret("NATS_URL", false)}
+}
+
+// Publisher creates the configured publisher, identifying the client as name
+func (e Events) Publisher(logger *zap.Logger, name string) (events.Publisher, error) {
+	if e.NATSURL.Reveal() == "" {
+		return events.LogPublisher{Logger: logger}, nil
+	}
+	return events.NewNATS(e.NATSURL.Reveal(), name)
+}
+
+// Logger configures the application logs and the gRPC payload logging
+type Logger struct {
+	Level    string
+	FilePath string
+
+	// Production switches to JSON file output without caller information
+	Production bool
+
+	// Rotation applies to the log file and the access log
+	Rotation shared.Rotation
+
+	LogRequests  bool
+	LogResponses bool
+	LogMetadata  bool
+}
+
+// LoadLogger reads LOG_LEVEL, LOG_FILE_PATH, the LOG_MAX_* rotation limits, LOG_COMPRESS
+// and the LOG_GRPC_* switches
+func LoadLogger(env *shared.Env, server Server, defaultFilePath string) Logger {
+	logger := Logger{
+		Level:      env.String("LOG_LEVEL", "info"),
+		FilePath:   env.String("LOG_FILE_PATH", defaultFilePath),
+		Production: server.IsProduction(),
+		Rotation: shared.Rotation{
+			MaxSizeMB:  env.Int("LOG_MAX_SIZE_MB", shared.DefaultLogMaxSizeMB),
+			MaxBackups: env.Int("LOG_MAX_BACKUPS", 7),
+			MaxAge:     env.Duration("LOG_MAX_AGE", 0),
+			Compress:   env.Bool("LOG_COMPRESS", true),
+		},
+
+		LogRequests:  env.Bool("LOG_GRPC_REQUESTS", true),
+		LogResponses: env.Bool("LOG_GRPC_RESPONSES", false),
+		LogMetadata:  env.Bool("LOG_GRPC_METADATA", false),
+	}
+
+	if logger.Rotation.MaxSizeMB < 0 {
+		env.Invalid("LOG_MAX_SIZE_MB", "must not be negative")
+	}
+	if logger.Rotation.MaxBackups < 0 {
+		env.Invalid("LOG_MAX_BACKUPS", "must not be negative")
+	}
+
+	switch strings.ToLower(logger.Level) {
+	case "debug", "info", "warn", "warning", "error", "fatal", "panic":
+	default:
+		env.Invalid("LOG_LEVEL", fmt.Sprintf("unknown level %q", logger.Level))
+	}
+
+	return logger
+}
+
+// LoggerConfig builds the shared logger settings for the given server
+func (l Logger) LoggerConfig(serverName, environment string) *shared.LoggerConfig {
+	return &shared.LoggerConfig{
+		ServerName:       serverName,
+		Environment:      environment,
+		LogLevel:         l.Level,
+		EnableConsole:    true,
+		EnableFile:       l.Production,
+		LogFilePath:      l.FilePath,
+		Rotation:         l.Rotation,
+		EnableJSON:       l.Production,
+		EnableCaller:     !l.Production,
+		EnableStacktrace: true,
+	}
+}
+
+// ErrorReporting configures the optional Sentry compatible error reporting
+type ErrorReporting struct {
+	// DSN enables reporting when set
+	DSN shared.Secret
+
+	// Release tags every event; defaults to the service name and version
+	Release string
+}
+
+// LoadErrorReporting reads SENTRY_DSN and SENTRY_RELEASE
+func LoadErrorReporting(env *shared.Env) ErrorReporting {
+	reporting := ErrorReporting{
+		DSN:     env.Secret("SENTRY_DSN", false),
+		Release: env.String("SENTRY_RELEASE", ""),
+	}
+	if reporting.Enabled() {
+		if _, _, err := shared.ParseSentryDSN(reporting.DSN.Reveal()); err != nil {
+			env.Invalid("SENTRY_DSN", err.Error())
+		}
+	}
+	return reporting
+}
+
+// Enabled reports whether errors are sent to an error tracker
+func (e ErrorReporting) Enabled() bool {
+	return e.DSN.Reveal() != ""
+}
+
+// Reporter creates the reporter, or returns nil when reporting is disabled or the DSN is invalid
+func (e ErrorReporting) Reporter(serverName, environment, version string) *shared.SentryReporter {
+	if !e.Enabled() {
+		return nil
+	}
+	release := e.Release
+	if release == "" {
+		release = serverName + "@" + version
+	}
+	reporter, err := shared.NewSentryReporter(shared.ErrorReportConfig{
+		DSN:         e.DSN,
+		Release:     release,
+		Environment: environment,
+		ServerName:  serverName,
+	})
+	if err != nil {
+		return nil
+	}
+	return reporter
+}
+
+// Auth configures bearer token validation
+type Auth struct {
+	// JWTSecret enables token validation when set; production requires it
+	JWTSecret   shared.Secret
+	JWTIssuer   string
+	JWTAudience string
+
+	// RequireVerifiedEmail rejects users whose token lacks the email_verified claim
+	RequireVerifiedEmail bool
+}
+
+// LoadAuth reads JWT_SECRET, JWT_ISSUER, JWT_AUDIENCE and JWT_REQUIRE_EMAIL_VERIFIED.
+// Running without JWT_SECRET, and so without authentication, is for development only.
+func LoadAuth(env *shared.Env, server Server) Auth {
+	auth := Auth{
+		JWTSecret:   env.Secret("JWT_SECRET", server.IsProduction()),
+		JWTIssuer:   env.String("JWT_ISSUER", ""),
+		JWTAudience: env.String("JWT_AUDIENCE", ""),
+
+		RequireVerifiedEmail: env.Bool("JWT_REQUIRE_EMAIL_VERIFIED", false),
+	}
+
+	// Claims checks without a secret would silently be skipped
+	if !auth.Enabled() && (auth.JWTIssuer != "" || auth.JWTAudience != "" || auth.RequireVerifiedEmail) {
+		env.Invalid("JWT_SECRET", "required when JWT_ISSUER, JWT_AUDIENCE or JWT_REQUIRE_EMAIL_VERIFIED is set")
+	}
+
+	return auth
+}
+
+// Enabled reports whether requests must carry a valid token
+func (a Auth) Enabled() bool {
+	return a.JWTSecret.Reveal() != ""
+}
+
+// AuthConfig builds the shared token settings
+func (a Auth) AuthConfig() *shared.AuthConfig {
+	return &shared.AuthConfig{
+		Logger:   shared.GetLogger(),
+		Secret:   a.JWTSecret,
+		Issuer:   a.JWTIssuer,
+		Audience: a.JWTAudience,
+		Leeway:   30 * time.Second,
+
+		RequireVerifiedEmail: a.RequireVerifiedEmail,
+	}
+}
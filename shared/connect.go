@@ -0,0 +1,419 @@
+package shared
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	connectContentTypeProto = "application/proto"
+	connectContentTypeJSON  = "application/json"
+
+	connectDefaultMaxBodyBytes = 4 << 20
+)
+
+// ConnectHandler serves the unary methods of a gRPC service over the Connect protocol,
+// so browsers and plain HTTP/1.1 clients can call it with protobuf or JSON bodies
+type ConnectHandler struct {
+	desc         *grpc.ServiceDesc
+	impl         any
+	methods      map[string]grpc.MethodDesc
+	interceptor  grpc.UnaryServerInterceptor
+	maxBodyBytes int64
+}
+
+// NewConnectHandler creates a Connect handler for the given service description and implementation.
+// The interceptors run in the same order as they would when chained on the gRPC server.
+func NewConnectHandler(desc *grpc.ServiceDesc, impl any, interceptors ...grpc.UnaryServerInterceptor) *ConnectHandler {
+	methods := make(map[string]grpc.MethodDesc, len(desc.Methods))
+	for _, m := range desc.Methods {
+		methods[m.MethodName] = m
+	}
+
+	return &ConnectHandler{
+		desc:         desc,
+		impl:         impl,
+		methods:      methods,
+		interceptor:  chainUnaryInterceptors(interceptors),
+		maxBodyBytes: connectDefaultMaxBodyBytes,
+	}
+}
+
+// Path returns the URL prefix the handler should be mounted on
+func (h *ConnectHandler) Path() string {
+	return "/" + h.desc.ServiceName + "/"
+}
+
+// ServeHTTP implements http.Handler
+func (h *ConnectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	codec, ok := connectCodecFor(r.Header.Get("Content-Type"))
+	if !ok {
+		w.Header().Set("Accept-Post", connectContentTypeProto+", "+connectContentTypeJSON)
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	methodName := strings.TrimPrefix(r.URL.Path, h.Path())
+	method, ok := h.methods[methodName]
+	if !ok {
+		writeConnectError(w, status.Errorf(codes.Unimplemented, "method %s not implemented", methodName))
+		return
+	}
+
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "" && encoding != "identity" {
+		writeConnectError(w, status.Errorf(codes.Unimplemented, "unsupported content encoding %q", encoding))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, h.maxBodyBytes))
+	if err != nil {
+		writeConnectError(w, status.Errorf(codes.ResourceExhausted, "failed to read request body: %v", err))
+		return
+	}
+
+	ctx, cancel, err := connectContext(r)
+	if err != nil {
+		writeConnectError(w, err)
+		return
+	}
+	defer cancel()
+
+	fullMethod := "/" + h.desc.ServiceName + "/" + methodName
+	stream := &connectTransportStream{method: fullMethod}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+	dec := func(v any) error {
+		if err := codec.unmarshal(body, v.(proto.Message)); err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to decode request: %v", err)
+		}
+		return nil
+	}
+
+	resp, err := method.Handler(h.impl, ctx, dec, h.interceptor)
+
+	header, trailer := stream.metadata()
+	writeConnectMetadata(w.Header(), header, "")
+	writeConnectMetadata(w.Header(), trailer, "Trailer-")
+
+	if err != nil {
+		writeConnectError(w, err)
+		return
+	}
+
+	out, err := codec.marshal(resp.(proto.Message))
+	if err != nil {
+		writeConnectError(w, status.Errorf(codes.Internal, "failed to encode response: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", codec.contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(out)
+}
+
+// connectCodec marshals messages for one of the Connect content types
+type connectCodec struct {
+	contentType string
+	marshal     func(proto.Message) ([]byte, error)
+	unmarshal   func([]byte, proto.Message) error
+}
+
+var (
+	connectProtoCodec = connectCodec{
+		contentType: connectContentTypeProto,
+		marshal:     proto.Marshal,
+		unmarshal:   proto.Unmarshal,
+	}
+	connectJSONCodec = connectCodec{
+		contentType: connectContentTypeJSON,
+		marshal:     protojson.Marshal,
+		unmarshal:   protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal,
+	}
+)
+
+// connectCodecFor resolves the codec for a request content type
+func connectCodecFor(contentType string) (connectCodec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return connectCodec{}, false
+	}
+
+	switch mediaType {
+	case connectContentTypeProto:
+		return connectProtoCodec, true
+	case connectContentTypeJSON:
+		return connectJSONCodec, true
+	default:
+		return connectCodec{}, false
+	}
+}
+
+// connectContext builds the handler context with deadline, incoming metadata and peer info
+func connectContext(r *http.Request) (context.Context, context.CancelFunc, error) {
+	ctx := r.Context()
+	cancel := context.CancelFunc(func() {})
+
+	if timeout := r.Header.Get("Connect-Timeout-Ms"); timeout != "" {
+		ms, err := strconv.ParseInt(timeout, 10, 64)
+		if err != nil || ms < 0 {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "invalid Connect-Timeout-Ms %q", timeout)
+		}
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+	}
+
+	md := metadata.MD{}
+	for key, values := range r.Header {
+		lowerKey := strings.ToLower(key)
+		if isConnectProtocolHeader(lowerKey) {
+			continue
+		}
+		for _, value := range values {
+			if strings.HasSuffix(lowerKey, "-bin") {
+				decoded, err := decodeConnectBinaryHeader(value)
+				if err != nil {
+					cancel()
+					return nil, nil, status.Errorf(codes.InvalidArgument, "invalid binary header %q", key)
+				}
+				value = decoded
+			}
+			md.Append(lowerKey, value)
+		}
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+
+	return ctx, cancel, nil
+}
+
+// isConnectProtocolHeader reports whether a header belongs to the transport rather than the application
+func isConnectProtocolHeader(key string) bool {
+	switch key {
+	case "content-type", "content-length", "content-encoding", "accept-encoding", "connection", "te", "host":
+		return true
+	}
+	return strings.HasPrefix(key, "connect-")
+}
+
+// decodeConnectBinaryHeader decodes a "-bin" header, accepting padded and unpadded base64
+func decodeConnectBinaryHeader(value string) (string, error) {
+	decoded, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(value, "="))
+	return string(decoded), err
+}
+
+// writeConnectMetadata copies gRPC metadata into HTTP headers, base64 encoding binary values
+func writeConnectMetadata(h http.Header, md metadata.MD, prefix string) {
+	for key, values := range md {
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.RawStdEncoding.EncodeToString([]byte(value))
+			}
+			h.Add(prefix+key, value)
+		}
+	}
+}
+
+// connectErrorDetail is a single entry of the Connect error "details" array
+type connectErrorDetail struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// connectError is the JSON body returned for failed unary calls
+type connectError struct {
+	Code    string               `json:"code"`
+	Message string               `json:"message,omitempty"`
+	Details []connectErrorDetail `json:"details,omitempty"`
+}
+
+// writeConnectError writes a gRPC status error using the Connect error format
+func writeConnectError(w http.ResponseWriter, err error) {
+	st, ok := status.FromError(withFallbackReason(err))
+	if !ok {
+		st = status.New(codes.Unknown, err.Error())
+	}
+
+	body := connectError{
+		Code:    connectCodeName(st.Code()),
+		Message: st.Message(),
+	}
+	for _, detail := range st.Proto().GetDetails() {
+		typeName := detail.GetTypeUrl()
+		if i := strings.LastIndex(typeName, "/"); i >= 0 {
+			typeName = typeName[i+1:]
+		}
+		body.Details = append(body.Details, connectErrorDetail{
+			Type:  typeName,
+			Value: base64.RawStdEncoding.EncodeToString(detail.GetValue()),
+		})
+	}
+
+	w.Header().Set("Content-Type", connectContentTypeJSON)
+	w.WriteHeader(connectHTTPStatus(st.Code()))
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// connectCodeName converts a gRPC code into its Connect wire name
+func connectCodeName(code codes.Code) string {
+	switch code {
+	case codes.Canceled:
+		return "canceled"
+	case codes.InvalidArgument:
+		return "invalid_argument"
+	case codes.DeadlineExceeded:
+		return "deadline_exceeded"
+	case codes.NotFound:
+		return "not_found"
+	case codes.AlreadyExists:
+		return "already_exists"
+	case codes.PermissionDenied:
+		return "permission_denied"
+	case codes.ResourceExhausted:
+		return "resource_exhausted"
+	case codes.FailedPrecondition:
+		return "failed_precondition"
+	case codes.Aborted:
+		return "aborted"
+	case codes.OutOfRange:
+		return "out_of_range"
+	case codes.Unimplemented:
+		return "unimplemented"
+	case codes.Internal:
+		return "internal"
+	case codes.Unavailable:
+		return "unavailable"
+	case codes.DataLoss:
+		return "data_loss"
+	case codes.Unauthenticated:
+		return "unauthenticated"
+	default:
+		return "unknown"
+	}
+}
+
+// connectHTTPStatus maps a gRPC code to the HTTP status mandated by the Connect protocol
+func connectHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// connectTransportStream collects headers and trailers set by handlers through grpc.SetHeader/SetTrailer
+type connectTransportStream struct {
+	method  string
+	mu      sync.Mutex
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (s *connectTransportStream) Method() string {
+	return s.method
+}
+
+func (s *connectTransportStream) SetHeader(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *connectTransportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *connectTransportStream) SetTrailer(md metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.trailer = metadata.Join(s.trailer, md)
+	return nil
+}
+
+func (s *connectTransportStream) metadata() (metadata.MD, metadata.MD) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.header, s.trailer
+}
+
+// chainUnaryInterceptors combines interceptors into one, the first being the outermost
+func chainUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	switch len(interceptors) {
+	case 0:
+		return nil
+	case 1:
+		return interceptors[0]
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return interceptors[0](ctx, req, info, chainedHandler(interceptors, 0, info, handler))
+	}
+}
+
+func chainedHandler(interceptors []grpc.UnaryServerInterceptor, current int, info *grpc.UnaryServerInfo, final grpc.UnaryHandler) grpc.UnaryHandler {
+	if current == len(interceptors)-1 {
+		return final
+	}
+	return func(ctx context.Context, req any) (any, error) {
+		return interceptors[current+1](ctx, req, info, chainedHandler(interceptors, current+1, info, final))
+	}
+}
+
+// NewConnectServer creates an HTTP server exposing the given Connect handlers
+func NewConnectServer(port string, handlers ...*ConnectHandler) *http.Server {
+	mux := http.NewServeMux()
+	for _, h := range handlers {
+		mux.Handle(h.Path(), h)
+	}
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%s", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
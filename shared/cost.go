@@ -0,0 +1,231 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// maxTracedStatements bounds the statements listed in an expensive call trace
+const maxTracedStatements = 5
+
+// CostThresholds flag a call as expensive when it exceeds any of them; zero
+// thresholds are ignored
+type CostThresholds struct {
+	// Statements is the number of SQL statements run by the call
+	Statements int
+
+	// Bytes is the size of the request and response messages together
+	Bytes int
+
+	// DownstreamCalls is the number of calls made to other services through NewClient
+	DownstreamCalls int
+}
+
+// CostConfig configures CostUnaryInterceptor
+type CostConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Metrics receives the cost counters (optional)
+	Metrics *Metrics
+
+	// ServerName labels the metrics
+	ServerName string
+
+	// Expensive are the thresholds of expensive calls (all zero disables the detection)
+	Expensive CostThresholds
+
+	// TraceRate is the fraction of expensive calls logged with their detailed
+	// breakdown, from 0 to 1; every expensive call is counted regardless
+	TraceRate float64
+}
+
+type callCostKey struct{}
+
+// callCost accumulates the resources used while serving one RPC. Statements are
+// reported by the StatementCounter plugin and downstream calls by NewClient
+// connections, as long as they run with the RPC context.
+type callCost struct {
+	statements statementCounter
+
+	mu         sync.Mutex
+	downstream map[string]int
+}
+
+func callCostFrom(ctx context.Context) *callCost {
+	cost, _ := ctx.Value(callCostKey{}).(*callCost)
+	return cost
+}
+
+func (c *callCost) addDownstream(method string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.downstream[method]++
+}
+
+func (c *callCost) downstreamCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := 0
+	for _, n := range c.downstream {
+		total += n
+	}
+	return total
+}
+
+// costCounters are the cost totals by method, exposed on /metrics
+type costCounters struct {
+	mu         sync.Mutex
+	statements map[methodLabels]uint64
+	downstream map[methodLabels]uint64
+	expensive  map[methodLabels]uint64
+}
+
+// CostUnaryInterceptor accounts the SQL statements, message bytes and downstream
+// calls of each unary RPC. Totals are exported as metrics and attached to the
+// completion log entries of LoggingUnaryInterceptor, which must run after it;
+// calls above the Expensive thresholds are counted and a sample of them logged
+// with the statements and downstream methods they spent the most on.
+func CostUnaryInterceptor(config *CostConfig) grpc.UnaryServerInterceptor {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+	counters := &costCounters{
+		statements: make(map[methodLabels]uint64),
+		downstream: make(map[methodLabels]uint64),
+		expensive:  make(map[methodLabels]uint64),
+	}
+	if config.Metrics != nil {
+		config.Metrics.Register(counters)
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		cost := &callCost{
+			statements: statementCounter{byStmt: make(map[string]int)},
+			downstream: make(map[string]int),
+		}
+		resp, err := handler(context.WithValue(ctx, callCostKey{}, cost), req)
+
+		statements := cost.statements.count()
+		downstream := cost.downstreamCalls()
+		bytes := messageSize(req) + messageSize(resp)
+
+		limits := config.Expensive
+		expensive := limits.Statements > 0 && statements > limits.Statements ||
+			limits.Bytes > 0 && bytes > limits.Bytes ||
+			limits.DownstreamCalls > 0 && downstream > limits.DownstreamCalls
+
+		labels := newMethodLabels(config.ServerName, info.FullMethod, "unary")
+		counters.add(labels, statements, downstream, expensive)
+
+		if expensive && config.TraceRate > 0 && rand.Float64() < config.TraceRate {
+			cost.mu.Lock()
+			calls := make(map[string]int, len(cost.downstream))
+			for method, n := range cost.downstream {
+				calls[method] = n
+			}
+			cost.mu.Unlock()
+
+			config.Logger.Warn("Expensive call",
+				zap.String("grpc.method", info.FullMethod),
+				zap.Int("db.statements", statements),
+				zap.Int("grpc.bytes", bytes),
+				zap.Int("grpc.downstream_calls", downstream),
+				zap.Any("db.top_statements", cost.statements.top(maxTracedStatements)),
+				zap.Any("grpc.downstream_methods", calls),
+			)
+		}
+		return resp, err
+	}
+}
+
+// costUnaryClientInterceptor reports calls made while serving an RPC to its cost
+func costUnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if cost := callCostFrom(ctx); cost != nil {
+		cost.addDownstream(method)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// costStreamClientInterceptor reports streams opened while serving an RPC to its cost
+func costStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if cost := callCostFrom(ctx); cost != nil {
+		cost.addDownstream(method)
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+func (c *costCounters) add(labels methodLabels, statements, downstream int, expensive bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statements[labels] += uint64(statements)
+	c.downstream[labels] += uint64(downstream)
+	if expensive {
+		c.expensive[labels]++
+	}
+}
+
+// WriteMetrics renders the cost counters in the Prometheus text format
+func (c *costCounters) WriteMetrics(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	series := []struct {
+		name, help string
+		values     map[methodLabels]uint64
+	}{
+		{"grpc_server_db_statements_total", "SQL statements run by unary RPCs.", c.statements},
+		{"grpc_server_downstream_calls_total", "Calls to other services made by unary RPCs.", c.downstream},
+		{"grpc_server_expensive_calls_total", "Unary RPCs exceeding an expensive call threshold.", c.expensive},
+	}
+	for _, s := range series {
+		if len(s.values) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", s.name, s.help)
+		fmt.Fprintf(w, "# TYPE %s counter\n", s.name)
+		for _, l := range sortedMethodLabels(s.values) {
+			fmt.Fprintf(w, "%s{%s} %d\n", s.name, l.format(), s.values[l])
+		}
+	}
+}
+
+// statementCount is a statement and how many times a call ran it
+type statementCount struct {
+	SQL   string `json:"sql"`
+	Count int    `json:"count"`
+}
+
+func (c *statementCounter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// top returns the n statements run the most times
+func (c *statementCounter) top(n int) []statementCount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make([]statementCount, 0, len(c.byStmt))
+	for sql, count := range c.byStmt {
+		counts = append(counts, statementCount{SQL: sql, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].SQL < counts[j].SQL
+	})
+	if len(counts) > n {
+		counts = counts[:n]
+	}
+	return counts
+}
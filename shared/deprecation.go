@@ -0,0 +1,202 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// ClientNameMetadataKey is the request header carrying the name of the calling service
+	ClientNameMetadataKey = "x-client-name"
+
+	// WarningMetadataKey is the response header announcing that a method is deprecated
+	WarningMetadataKey = "warning"
+
+	// UnknownClient labels calls that do not identify their client name or version
+	UnknownClient = "unknown"
+
+	// DefaultDeprecationClientLimit is the number of client name and version pairs given their own label
+	DefaultDeprecationClientLimit = 100
+
+	otherClient = "other"
+)
+
+// Deprecation marks an RPC as scheduled for removal
+type Deprecation struct {
+	// Method is the full gRPC method name, e.g. /shared.IdentityService/GetUsers
+	Method string
+
+	// Replacement is the method callers should move to (optional)
+	Replacement string
+}
+
+// warning formats the Warning header value, following the HTTP 299 "miscellaneous persistent warning"
+func (d Deprecation) warning() string {
+	message := d.Method + " is deprecated"
+	if d.Replacement != "" {
+		message += ", use " + d.Replacement
+	}
+	return fmt.Sprintf("299 - %q", message)
+}
+
+// ParseDeprecations parses a list of "method" or "method=replacement" entries
+// separated by ";", e.g. "/shared.IdentityService/GetUsers=/shared.IdentityService/ListUsers"
+func ParseDeprecations(value string) ([]Deprecation, error) {
+	var deprecations []Deprecation
+	for _, item := range SplitList(value) {
+		method, replacement, _ := strings.Cut(item, "=")
+		deprecation := Deprecation{Method: strings.TrimSpace(method), Replacement: strings.TrimSpace(replacement)}
+		if !strings.HasPrefix(deprecation.Method, "/") || strings.Count(deprecation.Method, "/") != 2 {
+			return nil, fmt.Errorf("%q is not a full gRPC method name", deprecation.Method)
+		}
+		deprecations = append(deprecations, deprecation)
+	}
+	return deprecations, nil
+}
+
+type deprecatedCallLabels struct {
+	methodLabels
+	client, version string
+}
+
+// DeprecationTracker sends a warning to callers of deprecated methods and counts
+// them by client name and version, so we know when nobody calls a method anymore
+type DeprecationTracker struct {
+	logger       *zap.Logger
+	serverName   string
+	deprecations map[string]Deprecation
+	limit        int
+
+	mu      sync.Mutex
+	clients map[[2]string]struct{}
+	calls   map[deprecatedCallLabels]uint64
+}
+
+// NewDeprecationTracker creates a tracker for the given methods and registers its
+// counters with metrics (nil only logs)
+func NewDeprecationTracker(logger *zap.Logger, metrics *Metrics, serverName string, deprecations []Deprecation) *DeprecationTracker {
+	if logger == nil {
+		logger = GetLogger()
+	}
+	t := &DeprecationTracker{
+		logger:       logger,
+		serverName:   serverName,
+		deprecations: make(map[string]Deprecation, len(deprecations)),
+		limit:        DefaultDeprecationClientLimit,
+		clients:      make(map[[2]string]struct{}),
+		calls:        make(map[deprecatedCallLabels]uint64),
+	}
+	for _, deprecation := range deprecations {
+		t.deprecations[deprecation.Method] = deprecation
+	}
+	if metrics != nil {
+		metrics.Register(t)
+	}
+	return t
+}
+
+// track records a call and returns the deprecation of the method, if any
+func (t *DeprecationTracker) track(ctx context.Context, method, kind string) (Deprecation, bool) {
+	deprecation, ok := t.deprecations[method]
+	if !ok {
+		return Deprecation{}, false
+	}
+
+	client, version := callingClient(ctx)
+	labels := deprecatedCallLabels{methodLabels: newMethodLabels(t.serverName, method, kind)}
+
+	t.mu.Lock()
+	key := [2]string{client, version}
+	_, known := t.clients[key]
+	if !known && len(t.clients) < t.limit {
+		t.clients[key] = struct{}{}
+		known = true
+	}
+	if known {
+		labels.client, labels.version = client, version
+	} else {
+		labels.client, labels.version = otherClient, otherClient
+	}
+	first := t.calls[labels] == 0
+	t.calls[labels]++
+	t.mu.Unlock()
+
+	if first {
+		t.logger.Warn("Deprecated method called",
+			zap.String("grpc.method", method),
+			zap.String("client_name", client),
+			zap.String("client_version", version),
+		)
+	}
+	return deprecation, true
+}
+
+// callingClient returns the client name and version sent by NewClient
+func callingClient(ctx context.Context) (string, string) {
+	client, version := UnknownClient, UnknownClient
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(ClientNameMetadataKey); len(values) > 0 && values[0] != "" {
+		client = values[0]
+	}
+	if values := md.Get(ClientVersionMetadataKey); len(values) > 0 && values[0] != "" {
+		version = values[0]
+	}
+	return client, version
+}
+
+// WriteMetrics renders the deprecated call counters
+func (t *DeprecationTracker) WriteMetrics(w io.Writer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.calls) == 0 {
+		return
+	}
+	labels := make([]deprecatedCallLabels, 0, len(t.calls))
+	for l := range t.calls {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].methodLabels != labels[j].methodLabels {
+			return labels[i].methodLabels.less(labels[j].methodLabels)
+		}
+		if labels[i].client != labels[j].client {
+			return labels[i].client < labels[j].client
+		}
+		return labels[i].version < labels[j].version
+	})
+
+	fmt.Fprint(w, "# HELP grpc_server_deprecated_calls_total Total number of calls to deprecated methods, by client name and version.\n")
+	fmt.Fprint(w, "# TYPE grpc_server_deprecated_calls_total counter\n")
+	for _, l := range labels {
+		fmt.Fprintf(w, "grpc_server_deprecated_calls_total{%s,client=%q,client_version=%q} %d\n", l.methodLabels.format(), l.client, l.version, t.calls[l])
+	}
+}
+
+// DeprecationUnaryInterceptor warns callers of deprecated methods and counts their calls
+func DeprecationUnaryInterceptor(tracker *DeprecationTracker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if deprecation, ok := tracker.track(ctx, info.FullMethod, "unary"); ok {
+			_ = grpc.SetHeader(ctx, metadata.Pairs(WarningMetadataKey, deprecation.warning()))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// DeprecationStreamInterceptor is the streaming counterpart of DeprecationUnaryInterceptor
+func DeprecationStreamInterceptor(tracker *DeprecationTracker) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if deprecation, ok := tracker.track(ss.Context(), info.FullMethod, streamKind(info)); ok {
+			_ = ss.SetHeader(metadata.Pairs(WarningMetadataKey, deprecation.warning()))
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,219 @@
+package shared
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvError describes a missing or invalid environment variable
+type EnvError struct {
+	Key     string
+	Problem string
+}
+
+func (e *EnvError) Error() string {
+	return e.Key + ": " + e.Problem
+}
+
+// EnvErrors lists every environment problem found while loading a configuration
+type EnvErrors []*EnvError
+
+func (e EnvErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, err := range e {
+		problems[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid environment (%d problems): %s", len(e), strings.Join(problems, "; "))
+}
+
+// Secret is a string that never prints its value, so it can't leak into logs
+type Secret string
+
+// String implements fmt.Stringer
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+// GoString implements fmt.GoStringer
+func (s Secret) GoString() string {
+	return s.String()
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Reveal returns the secret value
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// GetEnvInt reads an integer, returning the default when unset
+func GetEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue, &EnvError{Key: key, Problem: fmt.Sprintf("%q is not an integer", value)}
+	}
+	return parsed, nil
+}
+
+// GetEnvFloat reads a decimal number, returning the default when unset
+func GetEnvFloat(key string, defaultValue float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue, &EnvError{Key: key, Problem: fmt.Sprintf("%q is not a number", value)}
+	}
+	return parsed, nil
+}
+
+// GetEnvBool reads a boolean (true/false, 1/0, yes/no), returning the default when unset
+func GetEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	switch strings.ToLower(value) {
+	case "":
+		return defaultValue, nil
+	case "true", "1", "yes":
+		return true, nil
+	case "false", "0", "no":
+		return false, nil
+	default:
+		return defaultValue, &EnvError{Key: key, Problem: fmt.Sprintf("%q is not a boolean", value)}
+	}
+}
+
+// GetEnvDuration reads a duration such as "15s" or "5m", returning the default when unset
+func GetEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue, &EnvError{Key: key, Problem: fmt.Sprintf("%q is not a duration", value)}
+	}
+	return parsed, nil
+}
+
+// GetEnvURL reads an absolute URL, returning the parsed default when unset
+func GetEnvURL(key string, defaultValue string) (*url.URL, error) {
+	value := GetEnv(key, defaultValue)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, &EnvError{Key: key, Problem: fmt.Sprintf("%q is not an absolute URL", value)}
+	}
+	return parsed, nil
+}
+
+// GetEnvSecret reads a secret; required secrets must be set and non-empty
+func GetEnvSecret(key string, required bool) (Secret, error) {
+	value := os.Getenv(key)
+	if value == "" && required {
+		return "", &EnvError{Key: key, Problem: "required secret is not set"}
+	}
+	return Secret(value), nil
+}
+
+// Env loads a service configuration, collecting every problem so misconfiguration
+// is reported as a single, complete list instead of one variable at a time
+type Env struct {
+	errs EnvErrors
+}
+
+// NewEnv creates an empty Env
+func NewEnv() *Env {
+	return &Env{}
+}
+
+// Require declares variables the service cannot start without
+func (e *Env) Require(keys ...string) {
+	for _, key := range keys {
+		if os.Getenv(key) == "" {
+			e.errs = append(e.errs, &EnvError{Key: key, Problem: "required variable is not set"})
+		}
+	}
+}
+
+// String reads a string, returning the default when unset
+func (e *Env) String(key, defaultValue string) string {
+	return GetEnv(key, defaultValue)
+}
+
+// Int reads an integer, recording an error when invalid
+func (e *Env) Int(key string, defaultValue int) int {
+	value, err := GetEnvInt(key, defaultValue)
+	e.record(err)
+	return value
+}
+
+// Float reads a decimal number, recording an error when invalid
+func (e *Env) Float(key string, defaultValue float64) float64 {
+	value, err := GetEnvFloat(key, defaultValue)
+	e.record(err)
+	return value
+}
+
+// Bool reads a boolean, recording an error when invalid
+func (e *Env) Bool(key string, defaultValue bool) bool {
+	value, err := GetEnvBool(key, defaultValue)
+	e.record(err)
+	return value
+}
+
+// Duration reads a duration, recording an error when invalid
+func (e *Env) Duration(key string, defaultValue time.Duration) time.Duration {
+	value, err := GetEnvDuration(key, defaultValue)
+	e.record(err)
+	return value
+}
+
+// URL reads an absolute URL, recording an error when invalid
+func (e *Env) URL(key string, defaultValue string) *url.URL {
+	value, err := GetEnvURL(key, defaultValue)
+	e.record(err)
+	return value
+}
+
+// Secret reads a secret, recording an error when a required secret is missing
+func (e *Env) Secret(key string, required bool) Secret {
+	value, err := GetEnvSecret(key, required)
+	e.record(err)
+	return value
+}
+
+// Invalid records a problem found while validating an already loaded value
+func (e *Env) Invalid(key, problem string) {
+	e.errs = append(e.errs, &EnvError{Key: key, Problem: problem})
+}
+
+// Err returns all collected problems as EnvErrors, or nil when the environment is valid
+func (e *Env) Err() error {
+	if len(e.errs) == 0 {
+		return nil
+	}
+	return e.errs
+}
+
+func (e *Env) record(err error) {
+	if envErr, ok := err.(*EnvError); ok {
+		e.errs = append(e.errs, envErr)
+	}
+}
@@ -0,0 +1,404 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// ErrorSourceKey tags a logged error with what produced it, see ErrorSource
+	ErrorSourceKey = "error.source"
+
+	// ErrorSourcePanic marks recovered panics
+	ErrorSourcePanic = "panic"
+
+	// ErrorSourceJob marks failures of background jobs
+	ErrorSourceJob = "job"
+
+	// errorReportQueueSize bounds the events waiting to be sent; newer events are dropped when full
+	errorReportQueueSize = 100
+)
+
+// ErrorSource returns the field that tags an error log with its source in error reports
+func ErrorSource(source string) zap.Field {
+	return zap.String(ErrorSourceKey, source)
+}
+
+// errorReportTags are log fields promoted to searchable tags instead of extra data
+var errorReportTags = []string{ErrorSourceKey, "grpc.method", "grpc.code", "tenant_id", "kind"}
+
+// errorReportPIIFields are redacted from reports on top of the sensitive fields
+var errorReportPIIFields = []string{"email", "peer.addr"}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// ErrorEvent is an error reported to an error tracking service
+type ErrorEvent struct {
+	Message string
+	Level   zapcore.Level
+	Time    time.Time
+
+	// Error is the text of the error field of the log entry, if any
+	Error string
+	Stack string
+
+	Tags  map[string]string
+	Extra map[string]any
+}
+
+// ErrorReporter receives every error level log entry (see LoggerConfig.ErrorReporter);
+// implementations must not block the caller
+type ErrorReporter interface {
+	Report(event ErrorEvent)
+}
+
+// ErrorReportConfig configures the Sentry compatible error reporter
+type ErrorReportConfig struct {
+	// DSN is the project DSN, e.g. https://<key>@sentry.example.com/<project>
+	DSN Secret
+
+	// Release and Environment tag every event so errors can be tied to a deploy
+	Release     string
+	Environment string
+
+	// ServerName identifies the server instance
+	ServerName string
+
+	// Sensitive lists the fields redacted from events (defaults to DefaultSensitiveFields)
+	Sensitive *SensitiveFieldRegistry
+}
+
+// SentryReporter sends error events to the store endpoint of Sentry or of any
+// service implementing its API. Events are scrubbed of sensitive fields and email
+// addresses, queued and sent by a background goroutine.
+type SentryReporter struct {
+	config    ErrorReportConfig
+	endpoint  string
+	auth      string
+	client    *http.Client
+	sensitive atomic.Pointer[SensitiveFieldRegistry]
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan ErrorEvent
+	done   chan struct{}
+}
+
+// ParseSentryDSN returns the store endpoint and auth header of a DSN
+func ParseSentryDSN(dsn string) (endpoint, auth string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("invalid DSN: unsupported scheme %q", u.Scheme)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid DSN: missing public key")
+	}
+
+	prefix, project := "", strings.Trim(u.Path, "/")
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		prefix, project = "/"+project[:i], project[i+1:]
+	}
+	if project == "" {
+		return "", "", fmt.Errorf("invalid DSN: missing project ID")
+	}
+
+	endpoint = fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project)
+	auth = "Sentry sentry_version=7, sentry_client=momentum/1.0, sentry_key=" + u.User.Username()
+	if secret, ok := u.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+	return endpoint, auth, nil
+}
+
+// NewSentryReporter creates the reporter and starts its sender; call Close to flush
+// the queued events on shutdown
+func NewSentryReporter(config ErrorReportConfig) (*SentryReporter, error) {
+	endpoint, auth, err := ParseSentryDSN(config.DSN.Reveal())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &SentryReporter{
+		config:   config,
+		endpoint: endpoint,
+		auth:     auth,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		queue:    make(chan ErrorEvent, errorReportQueueSize),
+		done:     make(chan struct{}),
+	}
+	sensitive := config.Sensitive
+	if sensitive == nil {
+		sensitive = NewSensitiveFieldRegistry(DefaultSensitiveFields...)
+	}
+	r.sensitive.Store(sensitive)
+
+	go r.send()
+	return r, nil
+}
+
+// UseSensitiveFields switches the fields redacted from events, e.g. once the
+// service loaded its sensitive fields file
+func (r *SentryReporter) UseSensitiveFields(registry *SensitiveFieldRegistry) {
+	r.sensitive.Store(registry)
+}
+
+// Report scrubs and queues the event, dropping it when the queue is full
+func (r *SentryReporter) Report(event ErrorEvent) {
+	event = r.scrub(event)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- event:
+	default:
+	}
+}
+
+// Close stops accepting events and waits until the queued ones are sent or ctx ends
+func (r *SentryReporter) Close(ctx context.Context) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.queue)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+	}
+}
+
+func (r *SentryReporter) send() {
+	defer close(r.done)
+	for event := range r.queue {
+		if err := r.post(event); err != nil {
+			// Logged below the error level so failures are not reported again
+			GetLogger().Warn("Failed to send error report", zap.Error(err))
+		}
+	}
+}
+
+func (r *SentryReporter) post(event ErrorEvent) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	if event.Stack != "" {
+		event.Extra["stacktrace"] = event.Stack
+	}
+
+	payload := map[string]any{
+		"event_id":    hex.EncodeToString(id),
+		"timestamp":   event.Time.UTC().Format(time.RFC3339Nano),
+		"level":       sentryLevel(event.Level),
+		"logger":      "zap",
+		"platform":    "go",
+		"server_name": r.config.ServerName,
+		"release":     r.config.Release,
+		"environment": r.config.Environment,
+		"message":     event.Message,
+		"tags":        event.Tags,
+		"extra":       event.Extra,
+	}
+	if event.Error != "" {
+		payload["exception"] = map[string]any{
+			"values": []map[string]any{{"type": event.Message, "value": event.Error}},
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error tracker returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// scrub redacts sensitive fields and masks email addresses so no PII leaves the service
+func (r *SentryReporter) scrub(event ErrorEvent) ErrorEvent {
+	fields := slices.Concat(r.sensitive.Load().ForTenant(event.Tags["tenant_id"]), errorReportPIIFields)
+
+	event.Message = maskEmails(event.Message)
+	event.Error = maskEmails(event.Error)
+	for key, value := range event.Tags {
+		event.Tags[key] = maskEmails(value)
+	}
+	event.Extra = scrubMap(event.Extra, fields)
+	return event
+}
+
+func scrubMap(values map[string]any, fields []string) map[string]any {
+	scrubbed := make(map[string]any, len(values))
+	for key, value := range values {
+		lowerKey := strings.ToLower(key)
+		if slices.ContainsFunc(fields, func(field string) bool { return strings.Contains(lowerKey, field) }) {
+			scrubbed[key] = "[REDACTED]"
+			continue
+		}
+		scrubbed[key] = scrubValue(value, fields)
+	}
+	return scrubbed
+}
+
+func scrubValue(value any, fields []string) any {
+	switch v := value.(type) {
+	case string:
+		return maskEmails(v)
+	case map[string]any:
+		return scrubMap(v, fields)
+	case []any:
+		scrubbed := make([]any, len(v))
+		for i, item := range v {
+			scrubbed[i] = scrubValue(item, fields)
+		}
+		return scrubbed
+	default:
+		return value
+	}
+}
+
+func maskEmails(text string) string {
+	return emailPattern.ReplaceAllString(text, "[email]")
+}
+
+func sentryLevel(level zapcore.Level) string {
+	if level > zapcore.ErrorLevel {
+		return "fatal"
+	}
+	return "error"
+}
+
+// reportingCore forwards error level entries to an ErrorReporter besides writing
+// them to the wrapped core
+type reportingCore struct {
+	zapcore.Core
+	reporter ErrorReporter
+	fields   []zapcore.Field
+}
+
+// newReportingCore wraps core so error level entries are also reported
+func newReportingCore(core zapcore.Core, reporter ErrorReporter) zapcore.Core {
+	return &reportingCore{Core: core, reporter: reporter}
+}
+
+func (c *reportingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reportingCore{
+		Core:     c.Core.With(fields),
+		reporter: c.reporter,
+		fields:   append(slices.Clip(c.fields), fields...),
+	}
+}
+
+func (c *reportingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	checked = c.Core.Check(entry, checked)
+	if entry.Level >= zapcore.ErrorLevel {
+		checked = checked.AddCore(entry, errorReportHook{reporter: c.reporter, fields: c.fields})
+	}
+	return checked
+}
+
+// errorReportHook is the core added to checked error entries; it only reports them
+type errorReportHook struct {
+	reporter ErrorReporter
+	fields   []zapcore.Field
+}
+
+func (h errorReportHook) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+func (h errorReportHook) With(fields []zapcore.Field) zapcore.Core {
+	return errorReportHook{reporter: h.reporter, fields: append(slices.Clip(h.fields), fields...)}
+}
+
+func (h errorReportHook) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if h.Enabled(entry.Level) {
+		return checked.AddCore(entry, h)
+	}
+	return checked
+}
+
+func (h errorReportHook) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range h.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	extra := enc.Fields
+
+	// Failed calls are logged as errors whatever their code; only server errors are reported
+	if code, ok := extra["grpc.code"].(string); ok && !serverErrorCodes[code] {
+		return nil
+	}
+
+	event := ErrorEvent{
+		Message: entry.Message,
+		Level:   entry.Level,
+		Time:    entry.Time,
+		Stack:   entry.Stack,
+		Tags:    make(map[string]string),
+	}
+	for _, key := range errorReportTags {
+		if value, ok := extra[key].(string); ok {
+			event.Tags[key] = value
+			delete(extra, key)
+		}
+	}
+	if text, ok := extra["error"].(string); ok {
+		event.Error = text
+		delete(extra, "error")
+	}
+	if stack, ok := extra["grpc.stack"].(string); ok {
+		event.Stack = stack
+		delete(extra, "grpc.stack")
+	}
+	event.Extra = extra
+
+	h.reporter.Report(event)
+	return nil
+}
+
+func (h errorReportHook) Sync() error {
+	return nil
+}
@@ -0,0 +1,106 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the ErrorInfo domain of the reasons in proto.ErrorReason
+const ErrorDomain = "momentum"
+
+// fallbackReasons are attached to errors returned without a reason
+var fallbackReasons = map[codes.Code]proto.ErrorReason{
+	codes.InvalidArgument:    proto.ErrorReason_INVALID_ARGUMENT,
+	codes.OutOfRange:         proto.ErrorReason_INVALID_ARGUMENT,
+	codes.NotFound:           proto.ErrorReason_NOT_FOUND,
+	codes.AlreadyExists:      proto.ErrorReason_ALREADY_EXISTS,
+	codes.PermissionDenied:   proto.ErrorReason_PERMISSION_DENIED,
+	codes.Unauthenticated:    proto.ErrorReason_UNAUTHENTICATED,
+	codes.ResourceExhausted:  proto.ErrorReason_RESOURCE_EXHAUSTED,
+	codes.FailedPrecondition: proto.ErrorReason_FAILED_PRECONDITION,
+	codes.Aborted:            proto.ErrorReason_ABORTED,
+	codes.Unimplemented:      proto.ErrorReason_UNIMPLEMENTED,
+	codes.Unavailable:        proto.ErrorReason_UNAVAILABLE,
+	codes.DeadlineExceeded:   proto.ErrorReason_DEADLINE_EXCEEDED,
+	codes.Canceled:           proto.ErrorReason_CANCELED,
+}
+
+// ReasonError returns a status error carrying reason in an ErrorInfo detail
+func ReasonError(code codes.Code, reason proto.ErrorReason, message string) error {
+	return withReason(status.New(code, message), reason, nil).Err()
+}
+
+// withReason adds an ErrorInfo detail with the reason and metadata to st
+func withReason(st *status.Status, reason proto.ErrorReason, metadata map[string]string) *status.Status {
+	detailed, err := st.WithDetails(reasonInfo(reason, metadata))
+	if err != nil {
+		return st
+	}
+	return detailed
+}
+
+func reasonInfo(reason proto.ErrorReason, metadata map[string]string) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: reason.String(), Domain: ErrorDomain, Metadata: metadata}
+}
+
+// ErrorReason returns the reason of an error returned by a momentum service, or
+// ERROR_REASON_UNSPECIFIED for other errors and reasons unknown to this build
+func ErrorReason(err error) proto.ErrorReason {
+	st, ok := status.FromError(err)
+	if !ok {
+		return proto.ErrorReason_ERROR_REASON_UNSPECIFIED
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			return proto.ErrorReason(proto.ErrorReason_value[info.GetReason()])
+		}
+	}
+	return proto.ErrorReason_ERROR_REASON_UNSPECIFIED
+}
+
+// withFallbackReason attaches the generic reason of the status code to errors
+// without an ErrorInfo detail; other errors are reported as INTERNAL
+func withFallbackReason(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.FromContextError(err)
+	}
+	if st.Code() == codes.OK {
+		return err
+	}
+	for _, detail := range st.Details() {
+		if _, ok := detail.(*errdetails.ErrorInfo); ok {
+			return err
+		}
+	}
+
+	reason, ok := fallbackReasons[st.Code()]
+	if !ok {
+		reason = proto.ErrorReason_INTERNAL
+	}
+	return withReason(st, reason, nil).Err()
+}
+
+// ErrorReasonUnaryInterceptor makes every error carry a reason, so clients can
+// always branch on ErrorReason
+func ErrorReasonUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		return resp, withFallbackReason(err)
+	}
+}
+
+// ErrorReasonStreamInterceptor makes every stream error carry a reason
+func ErrorReasonStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return withFallbackReason(handler(srv, ss))
+	}
+}
@@ -0,0 +1,92 @@
+// Package events publishes domain events so services can react to changes in
+// other services without polling them.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Event types published by identity. Subjects are SubjectPrefix followed by the type.
+const (
+	UserCreated  = "user.created"
+	UserUpdated  = "user.updated"
+	UserDeleted  = "user.deleted"
+	UserRestored = "user.restored"
+	UserLoggedIn = "user.logged_in"
+
+	SubjectPrefix = "momentum."
+)
+
+// Event is the envelope of every published event. Fields are only ever added,
+// so consumers can decode events from newer publishers.
+type Event struct {
+	// ID is unique per event, for consumers deduplicating redeliveries
+	ID       string          `json:"id"`
+	Type     string          `json:"type"`
+	Source   string          `json:"source"`
+	Time     time.Time       `json:"time"`
+	TenantID string          `json:"tenant_id,omitempty"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// Subject is the subject the event is published on, e.g. momentum.user.created
+func (e Event) Subject() string {
+	return SubjectPrefix + e.Type
+}
+
+// UserData is the payload of user events
+type UserData struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email,omitempty"`
+	RoleID string `json:"role_id,omitempty"`
+	// Changes lists the modified fields of user.updated events
+	Changes []string `json:"changes,omitempty"`
+}
+
+// New builds an event with a fresh ID and the current time
+func New(source, eventType, tenantID string, data any) (Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:       uuid.NewString(),
+		Type:     eventType,
+		Source:   source,
+		Time:     time.Now().UTC(),
+		TenantID: tenantID,
+		Data:     payload,
+	}, nil
+}
+
+// ErrUnavailable wraps the errors of a Publisher that cannot reach the bus, as
+// opposed to failures of the event itself
+var ErrUnavailable = errors.New("event bus unavailable")
+
+// Publisher delivers events to the bus. Publish may be retried with the same
+// event, so implementations need not be idempotent but consumers must be.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// LogPublisher only logs events, for deployments without a bus
+type LogPublisher struct {
+	Logger *zap.Logger
+}
+
+// Publish implements Publisher
+func (p LogPublisher) Publish(ctx context.Context, event Event) error {
+	p.Logger.Debug("Event published",
+		zap.String("subject", event.Subject()),
+		zap.String("event_id", event.ID),
+		zap.ByteString("data", event.Data),
+	)
+	return nil
+}
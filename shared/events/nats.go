@@ -0,0 +1,341 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNATSTimeout bounds connecting and each publish when ctx has no earlier deadline
+const DefaultNATSTimeout = 5 * time.Second
+
+// NATS publishes events and delivers subscribed messages with the NATS core
+// protocol. The connection is opened on first use and reopened after errors;
+// subscriptions are renewed on every new connection.
+type NATS struct {
+	addr     string
+	name     string
+	user     string
+	password string
+	token    string
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	conn    *natsConn
+	subs    map[int]natsSub
+	nextSID int
+}
+
+// Msg is a message delivered to a subscription; Reply is set for requests
+type Msg struct {
+	Subject string
+	Reply   string
+	Data    []byte
+}
+
+type natsSub struct {
+	subject string
+	queue   string
+	handler func(Msg)
+}
+
+type natsConn struct {
+	net.Conn
+	writer *bufio.Writer
+
+	// mu serializes writes between Publish and the PONG replies of the reader
+	mu     sync.Mutex
+	closed chan struct{}
+}
+
+// NewNATS creates a publisher for a nats://[user:password@]host:port URL (a user
+// without password is sent as a token); name identifies the client to the server
+func NewNATS(rawURL, name string) (*NATS, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS URL: %w", err)
+	}
+	if u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("invalid NATS URL %q: expected nats://host:port", u.Redacted())
+	}
+
+	n := &NATS{addr: u.Host, name: name, timeout: DefaultNATSTimeout}
+	if u.Port() == "" {
+		n.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if password, ok := u.User.Password(); ok {
+			n.user, n.password = u.User.Username(), password
+		} else {
+			n.token = u.User.Username()
+		}
+	}
+	return n, nil
+}
+
+// Publish implements Publisher
+func (n *NATS) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return n.PublishData(ctx, event.Subject(), payload)
+}
+
+// PublishData publishes a raw payload, such as the reply to a request
+func (n *NATS) PublishData(ctx context.Context, subject string, data []byte) error {
+	conn, err := n.connection(ctx)
+	if err != nil {
+		return err
+	}
+	return n.write(ctx, conn, func(w *bufio.Writer) {
+		fmt.Fprintf(w, "PUB %s %d\r\n", subject, len(data))
+		w.Write(data)
+		w.WriteString("\r\n")
+	})
+}
+
+// Subscribe delivers the messages published on subject to handler, on the
+// connection's reader goroutine, so handler must not block. Subscribers sharing
+// a non-empty queue group receive each message once between them.
+func (n *NATS) Subscribe(ctx context.Context, subject, queue string, handler func(Msg)) error {
+	n.mu.Lock()
+	n.nextSID++
+	sid := n.nextSID
+	sub := natsSub{subject: subject, queue: queue, handler: handler}
+	if n.subs == nil {
+		n.subs = make(map[int]natsSub)
+	}
+	n.subs[sid] = sub
+	conn := n.conn
+	n.mu.Unlock()
+
+	// A connection opened from now on subscribes in connect
+	if conn == nil {
+		return nil
+	}
+	return n.write(ctx, conn, func(w *bufio.Writer) { writeSub(w, sid, sub) })
+}
+
+// Connect opens the connection if needed and returns a channel closed when it
+// is lost, so subscribers know when to reconnect
+func (n *NATS) Connect(ctx context.Context) (<-chan struct{}, error) {
+	conn, err := n.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return conn.closed, nil
+}
+
+// Close closes the connection
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	return err
+}
+
+// write sends a command, closing the connection when it fails
+func (n *NATS) write(ctx context.Context, conn *natsConn, command func(w *bufio.Writer)) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.SetWriteDeadline(n.deadline(ctx))
+	command(conn.writer)
+	if err := conn.writer.Flush(); err != nil {
+		conn.Close()
+		return fmt.Errorf("%w: nats: %w", ErrUnavailable, err)
+	}
+	// Keepalive PONGs written by the reader must not inherit this deadline
+	conn.SetWriteDeadline(time.Time{})
+	return nil
+}
+
+func writeSub(w *bufio.Writer, sid int, sub natsSub) {
+	if sub.queue == "" {
+		fmt.Fprintf(w, "SUB %s %d\r\n", sub.subject, sid)
+		return
+	}
+	fmt.Fprintf(w, "SUB %s %s %d\r\n", sub.subject, sub.queue, sid)
+}
+
+// deliver hands a message to the handler of its subscription
+func (n *NATS) deliver(sid int, msg Msg) {
+	n.mu.Lock()
+	sub, ok := n.subs[sid]
+	n.mu.Unlock()
+	if ok {
+		sub.handler(msg)
+	}
+}
+
+func (n *NATS) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(n.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+// connection returns the open connection, reconnecting once the previous one failed
+func (n *NATS) connection(ctx context.Context) (*natsConn, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conn != nil {
+		select {
+		case <-n.conn.closed:
+			n.conn = nil
+		default:
+			return n.conn, nil
+		}
+	}
+
+	conn, err := n.connect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	n.conn = conn
+	return conn, nil
+}
+
+// connect reads the server INFO, sends CONNECT and the registered subscriptions
+// and waits for the PONG answering a PING, so authentication and subscription
+// errors are reported here rather than lost. It runs with n.mu held.
+func (n *NATS) connect(ctx context.Context) (*natsConn, error) {
+	deadline := n.deadline(ctx)
+	dialer := net.Dialer{Deadline: deadline}
+	netConn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+	netConn.SetDeadline(deadline)
+	reader := bufio.NewReader(netConn)
+
+	fail := func(err error) (*natsConn, error) {
+		netConn.Close()
+		return nil, fmt.Errorf("nats: %w", err)
+	}
+
+	info, err := reader.ReadString('\n')
+	if err != nil {
+		return fail(err)
+	}
+	if !strings.HasPrefix(info, "INFO ") {
+		return fail(fmt.Errorf("unexpected greeting %q", strings.TrimSpace(info)))
+	}
+
+	options, err := json.Marshal(map[string]any{
+		"verbose":    false,
+		"pedantic":   false,
+		"name":       n.name,
+		"lang":       "go",
+		"protocol":   1,
+		"user":       n.user,
+		"pass":       n.password,
+		"auth_token": n.token,
+	})
+	if err != nil {
+		return fail(err)
+	}
+	writer := bufio.NewWriter(netConn)
+	fmt.Fprintf(writer, "CONNECT %s\r\n", options)
+	for sid, sub := range n.subs {
+		writeSub(writer, sid, sub)
+	}
+	writer.WriteString("PING\r\n")
+	if err := writer.Flush(); err != nil {
+		return fail(err)
+	}
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fail(err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			return fail(errors.New(strings.TrimSpace(strings.TrimPrefix(line, "-ERR"))))
+		}
+	}
+
+	netConn.SetDeadline(time.Time{})
+	conn := &natsConn{Conn: netConn, writer: writer, closed: make(chan struct{})}
+	go conn.read(reader, n.deliver)
+	return conn, nil
+}
+
+// read answers the server's keepalive PINGs and delivers subscribed messages.
+// Errors reported with -ERR are fatal in NATS, so the connection is closed and
+// the next Publish or Connect reconnects.
+func (c *natsConn) read(reader *bufio.Reader, deliver func(sid int, msg Msg)) {
+	defer close(c.closed)
+	defer c.Close()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch line = strings.TrimSpace(line); {
+		case line == "PING":
+			c.mu.Lock()
+			c.writer.WriteString("PONG\r\n")
+			err = c.writer.Flush()
+			c.mu.Unlock()
+			if err != nil {
+				return
+			}
+		case strings.HasPrefix(line, "MSG "):
+			sid, msg, err := readMsg(reader, line)
+			if err != nil {
+				return
+			}
+			deliver(sid, msg)
+		case strings.HasPrefix(line, "-ERR"):
+			return
+		}
+	}
+}
+
+// readMsg parses "MSG <subject> <sid> [reply-to] <#bytes>" and reads the payload
+// that follows it
+func readMsg(reader *bufio.Reader, line string) (int, Msg, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 4 && len(fields) != 5 {
+		return 0, Msg{}, fmt.Errorf("malformed %q", line)
+	}
+	sid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return 0, Msg{}, fmt.Errorf("malformed %q", line)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 {
+		return 0, Msg{}, fmt.Errorf("malformed %q", line)
+	}
+
+	msg := Msg{Subject: fields[1], Data: make([]byte, size+2)}
+	if len(fields) == 5 {
+		msg.Reply = fields[3]
+	}
+	if _, err := io.ReadFull(reader, msg.Data); err != nil {
+		return 0, Msg{}, err
+	}
+	msg.Data = msg.Data[:size]
+	return sid, msg, nil
+}
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadMsg(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		payload string
+		wantSID int
+		want    Msg
+		wantErr bool
+	}{
+		{
+			name:    "request",
+			line:    "MSG momentum.rpc.GetUser 3 _INBOX.abc 5",
+			payload: "hello\r\n",
+			wantSID: 3,
+			want:    Msg{Subject: "momentum.rpc.GetUser", Reply: "_INBOX.abc", Data: []byte("hello")},
+		},
+		{
+			name:    "without reply",
+			line:    "MSG momentum.user.created 1 8",
+			payload: "a\r\nb c\r\n\r\n",
+			wantSID: 1,
+			want:    Msg{Subject: "momentum.user.created", Data: []byte("a\r\nb c\r\n")},
+		},
+		{name: "missing size", line: "MSG subject 1", wantErr: true},
+		{name: "invalid sid", line: "MSG subject one 0", payload: "\r\n", wantErr: true},
+		{name: "truncated payload", line: "MSG subject 1 10", payload: "short", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sid, msg, err := readMsg(bufio.NewReader(strings.NewReader(tt.payload)), tt.line)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("readMsg accepted %q", tt.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readMsg: %v", err)
+			}
+			if sid != tt.wantSID || msg.Subject != tt.want.Subject || msg.Reply != tt.want.Reply || string(msg.Data) != string(tt.want.Data) {
+				t.Fatalf("readMsg = %d %+v, want %d %+v", sid, msg, tt.wantSID, tt.want)
+			}
+		})
+	}
+}
+
+// TestNATSRequestReply plays the server side of a request: the subscription is
+// sent on connect, and the handler's reply is published to the reply subject
+func TestNATSRequestReply(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	n, err := NewNATS("nats://"+listener.Addr().String(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	ctx := context.Background()
+	if err := n.Subscribe(ctx, "momentum.rpc.Echo", "echo", func(msg Msg) {
+		go n.PublishData(ctx, msg.Reply, append([]byte("echo "), msg.Data...))
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	published := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		reader := bufio.NewReader(conn)
+		conn.Write([]byte("INFO {}\r\n"))
+
+		var received []string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if line = strings.TrimSpace(line); line == "PING" {
+				break
+			}
+			received = append(received, line)
+		}
+		if len(received) != 2 || received[1] != "SUB momentum.rpc.Echo echo 1" {
+			published <- "unexpected handshake: " + strings.Join(received, " | ")
+			return
+		}
+		conn.Write([]byte("PONG\r\nMSG momentum.rpc.Echo 1 _INBOX.1 2\r\nhi\r\n"))
+
+		pub, _ := reader.ReadString('\n')
+		payload, _ := reader.ReadString('\n')
+		published <- strings.TrimSpace(pub) + " " + strings.TrimSpace(payload)
+	}()
+
+	if _, err := n.Connect(ctx); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	select {
+	case got := <-published:
+		if got != "PUB _INBOX.1 7 echo hi" {
+			t.Fatalf("server received %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reply was published")
+	}
+}
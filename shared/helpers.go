@@ -1,6 +1,9 @@
 package shared
 
-import "os"
+import (
+	"os"
+	"strings"
+)
 
 func GetEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -8,3 +11,14 @@ func GetEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// SplitList splits a ";" separated list, dropping empty entries
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -34,6 +34,9 @@ type LoggerConfig struct {
 	// LogFilePath is the path to the log file
 	LogFilePath string
 
+	// Rotation limits the size and number of log files (see NewRotatingFile)
+	Rotation Rotation
+
 	// EnableJSON enables JSON format output
 	EnableJSON bool
 
@@ -42,6 +45,9 @@ type LoggerConfig struct {
 
 	// EnableStacktrace enables stacktrace for error level and above
 	EnableStacktrace bool
+
+	// ErrorReporter, when set, also receives every error level entry (see NewSentryReporter)
+	ErrorReporter ErrorReporter
 }
 
 // DefaultLoggerConfig returns a sensible default configuration
@@ -72,6 +78,7 @@ func DefaultLoggerConfig() *LoggerConfig {
 		EnableConsole:    true,
 		EnableFile:       environment == "production",
 		LogFilePath:      "/var/log/app.log",
+		Rotation:         Rotation{MaxSizeMB: DefaultLogMaxSizeMB},
 		EnableJSON:       environment == "production",
 		EnableCaller:     environment != "production",
 		EnableStacktrace: true,
@@ -133,7 +140,7 @@ func createLogger(config *LoggerConfig) (*zap.Logger, error) {
 	// File output
 	if config.EnableFile && config.LogFilePath != "" {
 		fileEncoder := createFileEncoder(config, encoderConfig)
-		if fileWriter, err := createFileWriter(config.LogFilePath); err == nil {
+		if fileWriter, err := createFileWriter(config.LogFilePath, config.Rotation); err == nil {
 			fileCore := zapcore.NewCore(fileEncoder, fileWriter, level)
 			cores = append(cores, fileCore)
 		}
@@ -141,6 +148,9 @@ func createLogger(config *LoggerConfig) (*zap.Logger, error) {
 
 	// Combine cores
 	core := zapcore.NewTee(cores...)
+	if config.ErrorReporter != nil {
+		core = newReportingCore(core, config.ErrorReporter)
+	}
 
 	// Create logger options
 	opts := []zap.Option{
@@ -232,13 +242,9 @@ func createFileEncoder(config *LoggerConfig, encoderConfig zapcore.EncoderConfig
 	return zapcore.NewJSONEncoder(encoderConfig)
 }
 
-// createFileWriter creates a file writer with rotation if available
-func createFileWriter(filePath string) (zapcore.WriteSyncer, error) {
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return nil, err
-	}
-	return zapcore.AddSync(file), nil
+// createFileWriter creates a rotating file writer
+func createFileWriter(filePath string, rotation Rotation) (zapcore.WriteSyncer, error) {
+	return NewRotatingFile(filePath, rotation)
 }
 
 // LogWithServerContext adds server context to existing logger
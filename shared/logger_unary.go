@@ -37,11 +37,17 @@ type InterceptorConfig struct {
 	// SensitiveFields are field names that should be redacted in logs
 	SensitiveFields []string
 
+	// SensitiveRegistry, when set, replaces SensitiveFields and picks up runtime changes
+	SensitiveRegistry *SensitiveFieldRegistry
+
 	// SlowRequestThreshold logs a warning for requests taking longer than this duration
 	SlowRequestThreshold time.Duration
 
 	// ServerName is added to all log entries to identify the server
 	ServerName string
+
+	// AccessLogger, when set, receives one fixed-schema entry per call (see NewAccessLogger)
+	AccessLogger *zap.Logger
 }
 
 // DefaultInterceptorConfig returns a sensible default configuration
@@ -57,7 +63,7 @@ func DefaultInterceptorConfig() *InterceptorConfig {
 		LogRequests:          true,
 		LogResponses:         false, // Disabled by default for security
 		LogMetadata:          false,
-		SensitiveFields:      []string{"password", "token", "secret", "key", "authorization"},
+		SensitiveFields:      DefaultSensitiveFields,
 		SlowRequestThreshold: 5 * time.Second,
 		ServerName:           serverName,
 	}
@@ -69,8 +75,15 @@ func LoggingUnaryInterceptor(config *InterceptorConfig) grpc.UnaryServerIntercep
 		config = DefaultInterceptorConfig()
 	}
 
+	// Lowercase once instead of on every request
+	registry := config.SensitiveRegistry
+	if registry == nil {
+		registry = NewSensitiveFieldRegistry(config.SensitiveFields...)
+	}
+
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 		startTime := time.Now()
+		sensitiveFields := registry.Fields()
 
 		// Create base logger with method info
 		logger := config.Logger.With(
@@ -81,22 +94,31 @@ func LoggingUnaryInterceptor(config *InterceptorConfig) grpc.UnaryServerIntercep
 		)
 
 		// Add client info if available
+		var peerAddr string
 		if p, ok := peer.FromContext(ctx); ok {
-			logger = logger.With(zap.String("grpc.peer.addr", p.Addr.String()))
+			peerAddr = p.Addr.String()
+			logger = logger.With(zap.String("grpc.peer.addr", peerAddr))
+		}
+
+		// Let later interceptors (e.g. authentication) fill in the user and tenant
+		ctx, record := withRequestRecord(ctx)
+		if config.AccessLogger != nil {
+			defer func() {
+				logAccess(config.AccessLogger, info.FullMethod, status.Code(err), time.Since(startTime), peerAddr, record, callCostFrom(ctx), req, resp)
+			}()
 		}
 
 		// Add metadata if enabled
 		if config.LogMetadata {
 			if md, ok := metadata.FromIncomingContext(ctx); ok {
-				logger = logger.With(zap.Any("grpc.metadata", sanitizeMetadata(md, config.SensitiveFields)))
+				logger = logger.With(zap.Any("grpc.metadata", sanitizeMetadata(md, sensitiveFields)))
 			}
 		}
 
 		// Log incoming request
 		if config.LogRequests {
-			sanitizedReq := sanitizeFields(req, config.SensitiveFields)
 			logger.Log(config.LogLevel, "gRPC request received",
-				zap.Any("grpc.request", sanitizedReq),
+				zap.String("grpc.request", sanitizeFields(req, sensitiveFields)),
 			)
 		} else {
 			logger.Log(config.LogLevel, "gRPC request received")
@@ -107,6 +129,7 @@ func LoggingUnaryInterceptor(config *InterceptorConfig) grpc.UnaryServerIntercep
 			if r := recover(); r != nil {
 				err = status.Errorf(codes.Internal, "panic recovered: %v", r)
 				logger.Error("gRPC method panicked",
+					ErrorSource(ErrorSourcePanic),
 					zap.Any("grpc.panic", r),
 					zap.String("grpc.stack", string(debug.Stack())),
 					zap.Duration("grpc.duration", time.Since(startTime)),
@@ -123,6 +146,15 @@ func LoggingUnaryInterceptor(config *InterceptorConfig) grpc.UnaryServerIntercep
 			zap.Duration("grpc.duration", duration),
 			zap.String("grpc.code", status.Code(err).String()),
 		}
+		if record.tenantID != "" {
+			logFields = append(logFields, zap.String("tenant_id", record.tenantID))
+		}
+		if cost := callCostFrom(ctx); cost != nil {
+			logFields = append(logFields,
+				zap.Int("db.statements", cost.statements.count()),
+				zap.Int("grpc.downstream_calls", cost.downstreamCalls()),
+			)
+		}
 
 		if err != nil {
 			// Log error details
@@ -137,8 +169,7 @@ func LoggingUnaryInterceptor(config *InterceptorConfig) grpc.UnaryServerIntercep
 		} else {
 			// Log successful completion
 			if config.LogResponses && resp != nil {
-				sanitizedResp := sanitizeFields(resp, config.SensitiveFields)
-				logFields = append(logFields, zap.Any("grpc.response", sanitizedResp))
+				logFields = append(logFields, zap.String("grpc.response", sanitizeFields(resp, sensitiveFields)))
 			}
 
 			// Check for slow requests
@@ -163,7 +194,7 @@ func extractServiceName(fullMethod string) string {
 	return fullMethod
 }
 
-// sanitizeMetadata removes sensitive data from gRPC metadata
+// sanitizeMetadata removes sensitive data from gRPC metadata; sensitiveFields must be lowercase
 func sanitizeMetadata(md metadata.MD, sensitiveFields []string) map[string][]string {
 	sanitized := make(map[string][]string)
 
@@ -172,7 +203,7 @@ func sanitizeMetadata(md metadata.MD, sensitiveFields []string) map[string][]str
 		isSensitive := false
 
 		for _, field := range sensitiveFields {
-			if strings.Contains(lowerKey, strings.ToLower(field)) {
+			if strings.Contains(lowerKey, field) {
 				isSensitive = true
 				break
 			}
@@ -188,25 +219,28 @@ func sanitizeMetadata(md metadata.MD, sensitiveFields []string) map[string][]str
 	return sanitized
 }
 
-// sanitizeFields recursively removes sensitive data from structs
-func sanitizeFields(obj any, sensitiveFields []string) any {
+// sanitizeFields renders a payload for logging, redacting it when it mentions a
+// sensitive field. It returns a string so zap does not reflect over the payload
+// again; sensitiveFields must be lowercase.
+func sanitizeFields(obj any, sensitiveFields []string) string {
 	if obj == nil {
-		return nil
+		return ""
 	}
 
 	// For now, convert to string and check for sensitive patterns
 	// In a real implementation, you might want to use reflection
 	// to properly handle struct fields
 	objStr := fmt.Sprintf("%+v", obj)
+	lowerObjStr := strings.ToLower(objStr)
 
 	// Simple sanitization - replace potential sensitive values
 	for _, field := range sensitiveFields {
-		if strings.Contains(strings.ToLower(objStr), strings.ToLower(field)) {
+		if strings.Contains(lowerObjStr, field) {
 			return "[REDACTED - Contains sensitive data]"
 		}
 	}
 
-	return obj
+	return objStr
 }
 
 // Simple usage function for backward compatibility
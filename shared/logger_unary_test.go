@@ -0,0 +1,99 @@
+package shared
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+)
+
+// discardLogger encodes entries like production does, without writing them
+func discardLogger() *zap.Logger {
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	return zap.New(zapcore.NewCore(encoder, zapcore.AddSync(io.Discard), zapcore.InfoLevel))
+}
+
+var loggedRequest = &proto.GetUserRequest{Id: "7f9c2ba4-e88f-4a1b-9d2c-3f6a5e8b1c0d"}
+
+// TestSanitizeFieldsAllocations enforces the allocation budget of rendering a
+// payload: the formatted and the lowercased copies, plus what fmt needs for a
+// protobuf message
+func TestSanitizeFieldsAllocations(t *testing.T) {
+	fields := NewSensitiveFieldRegistry(DefaultSensitiveFields...).Fields()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		sanitizeFields(loggedRequest, fields)
+	})
+	if allocs > 10 {
+		t.Fatalf("sanitizeFields allocated %v times, budget is 10", allocs)
+	}
+}
+
+func TestSanitizeFieldsRedactsSensitivePayloads(t *testing.T) {
+	fields := NewSensitiveFieldRegistry("password").Fields()
+
+	if got := sanitizeFields(&proto.ChangePasswordRequest{CurrentPassword: "secret"}, fields); got != "[REDACTED - Contains sensitive data]" {
+		t.Fatalf("sanitizeFields = %q, want the payload redacted", got)
+	}
+	if got := sanitizeFields(loggedRequest, fields); got == "" || got == "[REDACTED - Contains sensitive data]" {
+		t.Fatalf("sanitizeFields = %q, want the payload rendered", got)
+	}
+}
+
+func BenchmarkSanitizeFields(b *testing.B) {
+	fields := NewSensitiveFieldRegistry(DefaultSensitiveFields...).Fields()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		sanitizeFields(loggedRequest, fields)
+	}
+}
+
+// loggedCall returns a call through an interceptor logging requests and responses
+func loggedCall() func() error {
+	interceptor := LoggingUnaryInterceptor(&InterceptorConfig{
+		Logger:          discardLogger(),
+		LogLevel:        zapcore.InfoLevel,
+		LogRequests:     true,
+		LogResponses:    true,
+		SensitiveFields: DefaultSensitiveFields,
+		ServerName:      "bench",
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: proto.IdentityService_GetUser_FullMethodName}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return &proto.GetUserResponse{Name: "Ada Lovelace", Email: "ada@example.com", Role: "member"}, nil
+	}
+
+	return func() error {
+		_, err := interceptor(context.Background(), loggedRequest, info, handler)
+		return err
+	}
+}
+
+// TestLoggingUnaryInterceptorAllocations enforces the allocation budget of a
+// logged call, which encodes two entries with the rendered payloads
+func TestLoggingUnaryInterceptorAllocations(t *testing.T) {
+	call := loggedCall()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = call()
+	})
+	if allocs > 40 {
+		t.Fatalf("logged call allocated %v times, budget is 40", allocs)
+	}
+}
+
+func BenchmarkLoggingUnaryInterceptor(b *testing.B) {
+	call := loggedCall()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := call(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
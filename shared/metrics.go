@@ -0,0 +1,364 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultLatencyBuckets are the histogram upper bounds, in seconds, used for RPC latencies
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// MetricsConfig configures the RED metrics recorded for every RPC
+type MetricsConfig struct {
+	// Metrics stores the recorded values (defaults to a new registry)
+	Metrics *Metrics
+
+	// Buckets are the latency histogram upper bounds in seconds, used when Metrics is nil
+	Buckets []float64
+
+	// ServerName is added as the "server" label to every series
+	ServerName string
+
+	// Tenants labels handled counts and latencies by tenant, bounding the number of
+	// distinct values (nil leaves the tenant label empty)
+	Tenants *TenantLabeler
+}
+
+// DefaultMetricsConfig returns a sensible default configuration
+func DefaultMetricsConfig() *MetricsConfig {
+	serverName := os.Getenv("SERVER_NAME")
+	if serverName == "" {
+		serverName = "unknown-server"
+	}
+
+	return &MetricsConfig{
+		Buckets:    DefaultLatencyBuckets,
+		ServerName: serverName,
+	}
+}
+
+// Metrics holds request counts, error codes and latency histograms per method and
+// renders them in the Prometheus text exposition format
+type Metrics struct {
+	mu      sync.Mutex
+	buckets []float64
+	started map[methodLabels]uint64
+	handled map[handledLabels]uint64
+	latency map[tenantLabels]*histogram
+	traffic map[methodLabels]*trafficTotals
+
+	collectors []MetricsCollector
+}
+
+// MetricsCollector renders additional series after the RPC metrics
+type MetricsCollector interface {
+	WriteMetrics(w io.Writer)
+}
+
+type methodLabels struct {
+	server, service, method, kind string
+}
+
+type tenantLabels struct {
+	methodLabels
+	tenant string
+}
+
+type handledLabels struct {
+	tenantLabels
+	code string
+}
+
+type trafficTotals struct {
+	bytesIn, bytesOut, messagesIn, messagesOut uint64
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics creates an empty registry using the given latency buckets
+func NewMetrics(buckets []float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+
+	return &Metrics{
+		buckets: sorted,
+		started: make(map[methodLabels]uint64),
+		handled: make(map[handledLabels]uint64),
+		latency: make(map[tenantLabels]*histogram),
+		traffic: make(map[methodLabels]*trafficTotals),
+	}
+}
+
+func (m *Metrics) start(labels methodLabels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.started[labels]++
+}
+
+func (m *Metrics) observe(labels tenantLabels, code codes.Code, duration time.Duration) {
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handled[handledLabels{tenantLabels: labels, code: code.String()}]++
+
+	h, ok := m.latency[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latency[labels] = h
+	}
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+func (m *Metrics) recordTraffic(labels methodLabels, traffic *rpcTraffic) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	totals, ok := m.traffic[labels]
+	if !ok {
+		totals = &trafficTotals{}
+		m.traffic[labels] = totals
+	}
+	totals.bytesIn += uint64(traffic.bytesIn.Load())
+	totals.bytesOut += uint64(traffic.bytesOut.Load())
+	totals.messagesIn += uint64(traffic.messagesIn.Load())
+	totals.messagesOut += uint64(traffic.messagesOut.Load())
+}
+
+// Register adds a collector rendered by WriteTo
+func (m *Metrics) Register(collector MetricsCollector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collectors = append(m.collectors, collector)
+}
+
+// WriteTo renders every series in the Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	collectors := m.writeRPCMetrics(&b)
+
+	// Collectors may read the metrics themselves, so they run without the lock
+	for _, collector := range collectors {
+		collector.WriteMetrics(&b)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (m *Metrics) writeRPCMetrics(b *strings.Builder) []MetricsCollector {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b.WriteString("# HELP grpc_server_started_total Total number of RPCs started on the server.\n")
+	b.WriteString("# TYPE grpc_server_started_total counter\n")
+	for _, labels := range sortedMethodLabels(m.started) {
+		fmt.Fprintf(b, "grpc_server_started_total{%s} %d\n", labels.format(), m.started[labels])
+	}
+
+	handled := make([]handledLabels, 0, len(m.handled))
+	for labels := range m.handled {
+		handled = append(handled, labels)
+	}
+	sort.Slice(handled, func(i, j int) bool {
+		if handled[i].tenantLabels != handled[j].tenantLabels {
+			return handled[i].tenantLabels.less(handled[j].tenantLabels)
+		}
+		return handled[i].code < handled[j].code
+	})
+
+	b.WriteString("# HELP grpc_server_handled_total Total number of RPCs completed on the server, by status code.\n")
+	b.WriteString("# TYPE grpc_server_handled_total counter\n")
+	for _, labels := range handled {
+		fmt.Fprintf(b, "grpc_server_handled_total{%s,grpc_code=%q} %d\n", labels.tenantLabels.format(), labels.code, m.handled[labels])
+	}
+
+	b.WriteString("# HELP grpc_server_handling_seconds Histogram of RPC handling latency in seconds.\n")
+	b.WriteString("# TYPE grpc_server_handling_seconds histogram\n")
+	latency := make([]tenantLabels, 0, len(m.latency))
+	for labels := range m.latency {
+		latency = append(latency, labels)
+	}
+	sort.Slice(latency, func(i, j int) bool { return latency[i].less(latency[j]) })
+
+	for _, labels := range latency {
+		h := m.latency[labels]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(b, "grpc_server_handling_seconds_bucket{%s,le=%q} %d\n", labels.format(), strconv.FormatFloat(bound, 'g', -1, 64), h.counts[i])
+		}
+		fmt.Fprintf(b, "grpc_server_handling_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels.format(), h.count)
+		fmt.Fprintf(b, "grpc_server_handling_seconds_sum{%s} %s\n", labels.format(), strconv.FormatFloat(h.sum, 'g', -1, 64))
+		fmt.Fprintf(b, "grpc_server_handling_seconds_count{%s} %d\n", labels.format(), h.count)
+	}
+
+	trafficFamilies := []struct {
+		name, help string
+		value      func(*trafficTotals) uint64
+	}{
+		{"grpc_server_received_bytes_total", "Total bytes received on the wire, by method.", func(t *trafficTotals) uint64 { return t.bytesIn }},
+		{"grpc_server_sent_bytes_total", "Total bytes sent on the wire, by method.", func(t *trafficTotals) uint64 { return t.bytesOut }},
+		{"grpc_server_msg_received_total", "Total messages received, by method.", func(t *trafficTotals) uint64 { return t.messagesIn }},
+		{"grpc_server_msg_sent_total", "Total messages sent, by method.", func(t *trafficTotals) uint64 { return t.messagesOut }},
+	}
+	trafficLabels := sortedMethodLabels(m.traffic)
+	for _, family := range trafficFamilies {
+		if len(trafficLabels) == 0 {
+			break
+		}
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n", family.name, family.help, family.name)
+		for _, labels := range trafficLabels {
+			fmt.Fprintf(b, "%s{%s} %d\n", family.name, labels.format(), family.value(m.traffic[labels]))
+		}
+	}
+
+	return m.collectors
+}
+
+// Handler serves the metrics for Prometheus scraping
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
+
+// NewMetricsServer creates an HTTP server exposing the metrics on /metrics
+func NewMetricsServer(port string, metrics *Metrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%s", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+func (l methodLabels) format() string {
+	return fmt.Sprintf("server=%q,grpc_service=%q,grpc_method=%q,grpc_type=%q", l.server, l.service, l.method, l.kind)
+}
+
+func (l methodLabels) fullMethod() string {
+	return "/" + l.service + "/" + l.method
+}
+
+func (l methodLabels) less(other methodLabels) bool {
+	if l.service != other.service {
+		return l.service < other.service
+	}
+	return l.method < other.method
+}
+
+func (l tenantLabels) format() string {
+	return fmt.Sprintf("%s,tenant=%q", l.methodLabels.format(), l.tenant)
+}
+
+func (l tenantLabels) less(other tenantLabels) bool {
+	if l.methodLabels != other.methodLabels {
+		return l.methodLabels.less(other.methodLabels)
+	}
+	return l.tenant < other.tenant
+}
+
+func sortedMethodLabels[V any](series map[methodLabels]V) []methodLabels {
+	labels := make([]methodLabels, 0, len(series))
+	for l := range series {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].less(labels[j]) })
+	return labels
+}
+
+func newMethodLabels(serverName, fullMethod, kind string) methodLabels {
+	service, method := extractServiceName(fullMethod), fullMethod
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		method = fullMethod[i+1:]
+	}
+	return methodLabels{server: serverName, service: service, method: method, kind: kind}
+}
+
+// ensureMetrics fills the registry of a config that was built without one
+func ensureMetrics(config *MetricsConfig) *MetricsConfig {
+	if config == nil {
+		config = DefaultMetricsConfig()
+	}
+	if config.Metrics == nil {
+		config.Metrics = NewMetrics(config.Buckets)
+	}
+	return config
+}
+
+// MetricsUnaryInterceptor records request count, status code and latency for every unary RPC
+func MetricsUnaryInterceptor(config *MetricsConfig) grpc.UnaryServerInterceptor {
+	config = ensureMetrics(config)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		labels := newMethodLabels(config.ServerName, info.FullMethod, "unary")
+		config.Metrics.start(labels)
+
+		// The tenant is only known once authentication ran further down the chain
+		ctx, record := withRequestRecord(ctx)
+
+		startTime := time.Now()
+		resp, err := handler(ctx, req)
+		config.Metrics.observe(tenantLabels{labels, config.Tenants.Label(record.tenantID)}, status.Code(err), time.Since(startTime))
+
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor records the same metrics for streaming RPCs, measured until the stream ends
+func MetricsStreamInterceptor(config *MetricsConfig) grpc.StreamServerInterceptor {
+	config = ensureMetrics(config)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		labels := newMethodLabels(config.ServerName, info.FullMethod, streamKind(info))
+		config.Metrics.start(labels)
+
+		ctx, record := withRequestRecord(ss.Context())
+
+		startTime := time.Now()
+		err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+		config.Metrics.observe(tenantLabels{labels, config.Tenants.Label(record.tenantID)}, status.Code(err), time.Since(startTime))
+
+		return err
+	}
+}
+
+// streamKind returns the grpc_type label of a streaming RPC
+func streamKind(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return "bidi_stream"
+	case info.IsClientStream:
+		return "client_stream"
+	default:
+		return "server_stream"
+	}
+}
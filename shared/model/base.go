@@ -0,0 +1,73 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVersionConflict is returned when a record was modified since it was loaded
+var ErrVersionConflict = errors.New("record was modified by another transaction")
+
+// BaseModel holds the columns shared by every service model: a UUID primary key,
+// timestamps, soft delete, the owning tenant and an optimistic locking version
+type BaseModel struct {
+	ID        string `gorm:"type:uuid;primarykey"`
+	TenantID  string `gorm:"index;not null;default:''"`
+	Version   int64  `gorm:"not null;default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// Versioned is implemented by the models embedding BaseModel, whose updates are
+// guarded by their version
+type Versioned interface {
+	versioned()
+}
+
+func (m *BaseModel) versioned() {}
+
+// BeforeCreate assigns a new UUID unless the caller already provided one
+func (m *BaseModel) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// BeforeUpdate guards updates of a loaded record with its version and bumps it.
+// Batch updates through an empty model (e.g. Model(&User{}).Where(...)) are not versioned.
+func (m *BaseModel) BeforeUpdate(tx *gorm.DB) error {
+	if m.ID == "" {
+		return nil
+	}
+
+	tx.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "version"}, Value: m.Version},
+	}})
+
+	// Select(...) restricts the SET list to its columns, which would drop the bump
+	// and leave the stored version unchanged
+	if selects := tx.Statement.Selects; len(selects) > 0 && !slices.Contains(selects, "*") && !slices.Contains(selects, "version") {
+		tx.Statement.Selects = append(selects[:len(selects):len(selects)], "version")
+	}
+
+	next := m.Version + 1
+	tx.Statement.SetColumn("version", next)
+	m.Version = next
+	return nil
+}
+
+// AfterUpdate reports a conflict when the versioned update matched no rows
+func (m *BaseModel) AfterUpdate(tx *gorm.DB) error {
+	if m.ID != "" && tx.Statement.RowsAffected == 0 {
+		m.Version--
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type widget struct {
+	BaseModel
+	Name  string
+	Color string
+}
+
+// mockDB opens GORM on a sqlmock connection with the postgres dialect; updates run
+// outside a transaction so each expects a single Exec
+func mockDB(t *testing.T, plugins ...gorm.Plugin) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	conn, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, plugin := range plugins {
+		if err := db.Use(plugin); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db, mock
+}
+
+// dryRun returns the SQL fn would execute, without running it
+func dryRun(t *testing.T, db *gorm.DB, fn func(tx *gorm.DB) *gorm.DB) (string, []any) {
+	t.Helper()
+	stmt := fn(db.Session(&gorm.Session{DryRun: true})).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestBeforeUpdateBumpsVersionOfSelectedColumns(t *testing.T) {
+	db, _ := mockDB(t)
+
+	tests := []struct {
+		name   string
+		update func(tx *gorm.DB, w *widget) *gorm.DB
+	}{
+		{"all columns", func(tx *gorm.DB, w *widget) *gorm.DB { return tx.Model(w).Updates(w) }},
+		{"selected columns", func(tx *gorm.DB, w *widget) *gorm.DB { return tx.Model(w).Select("name").Updates(w) }},
+		{"map", func(tx *gorm.DB, w *widget) *gorm.DB {
+			return tx.Model(w).Select("name").Updates(map[string]any{"name": "b"})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &widget{BaseModel: BaseModel{ID: "w-1", Version: 3}, Name: "b", Color: "red"}
+			sql, vars := dryRun(t, db, func(tx *gorm.DB) *gorm.DB { return tt.update(tx, w) })
+
+			set, where, _ := strings.Cut(sql, " WHERE ")
+			if !strings.Contains(set, `"version"=`) {
+				t.Fatalf("SET list misses the version: %s", sql)
+			}
+			if !strings.Contains(where, `"widgets"."version" = `) {
+				t.Fatalf("WHERE misses the version guard: %s", sql)
+			}
+			if !containsAll(vars, int64(4), int64(3)) {
+				t.Fatalf("vars = %v, want the new version 4 and the guard 3", vars)
+			}
+		})
+	}
+}
+
+func TestBeforeUpdateSkipsUnversionedBatches(t *testing.T) {
+	db, _ := mockDB(t)
+
+	// An empty model is a batch update: no guard, no bump
+	sql, _ := dryRun(t, db, func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&widget{}).Where("color = ?", "red").Update("name", "b")
+	})
+	if strings.Contains(sql, "version") {
+		t.Fatalf("batch update is versioned: %s", sql)
+	}
+}
+
+func TestBeforeUpdateDoesNotModifySelectedSlice(t *testing.T) {
+	db, _ := mockDB(t)
+	columns := make([]string, 1, 4)
+	columns[0] = "name"
+
+	dryRun(t, db, func(tx *gorm.DB) *gorm.DB {
+		w := &widget{BaseModel: BaseModel{ID: "w-1"}}
+		return tx.Model(w).Select(columns).Updates(w)
+	})
+	if extended := columns[:2]; extended[1] != "" {
+		t.Fatalf("caller's columns were modified: %v", extended)
+	}
+}
+
+// TestStaleCopyConflicts updates two copies loaded at the same version: the
+// second matches no row once the first bumped the stored version
+func TestStaleCopyConflicts(t *testing.T) {
+	db, mock := mockDB(t)
+	update := regexp.QuoteMeta(`UPDATE "widgets" SET "version"=$1,"updated_at"=$2,"name"=$3 WHERE "widgets"."version" = $4 AND "widgets"."deleted_at" IS NULL AND "id" = $5`)
+	mock.ExpectExec(update).WithArgs(int64(4), sqlmock.AnyArg(), "first", int64(3), "w-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(update).WithArgs(int64(4), sqlmock.AnyArg(), "second", int64(3), "w-1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	first := &widget{BaseModel: BaseModel{ID: "w-1", Version: 3}, Name: "first"}
+	second := &widget{BaseModel: BaseModel{ID: "w-1", Version: 3}, Name: "second"}
+
+	if err := db.Model(first).Select("name").Updates(first).Error; err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	if err := db.Model(second).Select("name").Updates(second).Error; !errors.Is(err, ErrVersionConflict) {
+		t.Fatalf("second update error = %v, want %v", err, ErrVersionConflict)
+	}
+	if first.Version != 4 || second.Version != 3 {
+		t.Fatalf("versions = %d and %d, want 4 and the conflicting copy left at 3", first.Version, second.Version)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func containsAll(vars []any, want ...any) bool {
+	for _, w := range want {
+		found := false
+		for _, v := range vars {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTenantMismatch is returned when a statement writes a record of another tenant than the caller's
+var ErrTenantMismatch = errors.New("record belongs to another tenant")
+
+// TenantShared is implemented by models whose records without tenant, such as
+// global roles, are shared by every tenant: tenant callers read them but cannot
+// modify them
+type TenantShared interface {
+	TenantShared()
+}
+
+// TenantIsolation is a GORM plugin restricting every statement on a model with a
+// tenant_id column to the tenant that Tenant returns for the statement context.
+// Queries, updates and deletes are filtered by tenant, and created records get
+// the caller's tenant or fail with ErrTenantMismatch when given another one.
+// Contexts without tenant, like platform administrators and background jobs, are
+// not restricted.
+//
+// Raw and Exec statements bypass isolation: the plugin only rewrites statements
+// built from a model, so hand-written SQL on a tenant table must filter by
+// tenant_id itself, even when run with a tenant context.
+type TenantIsolation struct {
+	// Tenant returns the tenant of the caller, empty when unrestricted
+	Tenant func(ctx context.Context) string
+}
+
+// Name implements gorm.Plugin
+func (TenantIsolation) Name() string {
+	return "momentum:tenant_isolation"
+}
+
+// Initialize implements gorm.Plugin
+func (p TenantIsolation) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		name     string
+		register func(name string, fn func(*gorm.DB)) error
+		fn       func(*gorm.DB)
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, p.create},
+		{"query", callbacks.Query().Before("gorm:query").Register, p.read},
+		{"row", callbacks.Row().Before("gorm:row").Register, p.read},
+		{"update", callbacks.Update().Before("gorm:before_update").Register, p.write},
+		{"delete", callbacks.Delete().Before("gorm:before_delete").Register, p.write},
+	}
+
+	for _, r := range registrations {
+		if err := r.register(p.Name()+":"+r.name, r.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tenant returns the caller's tenant when the statement's model has a tenant_id column
+func (p TenantIsolation) tenant(db *gorm.DB) string {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Context == nil {
+		return ""
+	}
+	if _, ok := db.Statement.Schema.FieldsByDBName["tenant_id"]; !ok {
+		return ""
+	}
+	return p.Tenant(db.Statement.Context)
+}
+
+var tenantColumn = clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}
+
+// read also matches the shared records of TenantShared models
+func (p TenantIsolation) read(db *gorm.DB) {
+	tenantID := p.tenant(db)
+	if tenantID == "" {
+		return
+	}
+
+	var expr clause.Expression = clause.Eq{Column: tenantColumn, Value: tenantID}
+	if _, ok := reflect.New(db.Statement.Schema.ModelType).Interface().(TenantShared); ok {
+		expr = clause.IN{Column: tenantColumn, Values: []any{tenantID, ""}}
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{expr}})
+}
+
+// write rejects loaded records of another tenant, which would otherwise look like
+// a version conflict, before filtering the statement by tenant. It runs ahead of
+// the model hooks so BeforeUpdate does not bump the version of a rejected record.
+func (p TenantIsolation) write(db *gorm.DB) {
+	tenantID := p.tenant(db)
+	if tenantID == "" {
+		return
+	}
+
+	ctx := db.Statement.Context
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	if primary := db.Statement.Schema.PrioritizedPrimaryField; primary != nil && value.Kind() == reflect.Struct {
+		if _, zero := primary.ValueOf(ctx, value); !zero {
+			if owner, _ := db.Statement.Schema.FieldsByDBName["tenant_id"].ValueOf(ctx, value); owner != tenantID {
+				db.AddError(ErrTenantMismatch)
+				return
+			}
+		}
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{clause.Eq{Column: tenantColumn, Value: tenantID}}})
+}
+
+func (p TenantIsolation) create(db *gorm.DB) {
+	tenantID := p.tenant(db)
+	if tenantID == "" {
+		return
+	}
+
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := assignTenant(db, reflect.Indirect(value.Index(i)), tenantID); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := assignTenant(db, value, tenantID); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// assignTenant sets the tenant of a record created without one
+func assignTenant(db *gorm.DB, record reflect.Value, tenantID string) error {
+	ctx := db.Statement.Context
+	field := db.Statement.Schema.FieldsByDBName["tenant_id"]
+	owner, zero := field.ValueOf(ctx, record)
+	if zero {
+		return field.Set(ctx, record, tenantID)
+	}
+	if owner != tenantID {
+		return ErrTenantMismatch
+	}
+	return nil
+}
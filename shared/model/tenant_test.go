@@ -0,0 +1,170 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+// sharedWidget is a TenantShared model: records without tenant are global
+type sharedWidget struct {
+	BaseModel
+	Name string
+}
+
+func (sharedWidget) TenantShared() {}
+
+type tenantKey struct{}
+
+func withTenant(tenantID string) context.Context {
+	return context.WithValue(context.Background(), tenantKey{}, tenantID)
+}
+
+var isolation = TenantIsolation{Tenant: func(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantKey{}).(string)
+	return tenantID
+}}
+
+const tenantFilter = `"tenant_id" = `
+
+func TestTenantIsolationFiltersStatements(t *testing.T) {
+	db, _ := mockDB(t, isolation)
+
+	tests := []struct {
+		name      string
+		statement func(tx *gorm.DB) *gorm.DB
+	}{
+		{"query", func(tx *gorm.DB) *gorm.DB { return tx.Where("name = ?", "a").Find(&[]widget{}) }},
+		{"update", func(tx *gorm.DB) *gorm.DB {
+			return tx.Model(&widget{BaseModel: BaseModel{ID: "w-1", TenantID: "acme"}}).Update("name", "b")
+		}},
+		{"batch update", func(tx *gorm.DB) *gorm.DB { return tx.Model(&widget{}).Where("name = ?", "a").Update("name", "b") }},
+		{"delete", func(tx *gorm.DB) *gorm.DB {
+			return tx.Delete(&widget{BaseModel: BaseModel{ID: "w-1", TenantID: "acme"}})
+		}},
+		{"batch delete", func(tx *gorm.DB) *gorm.DB { return tx.Where("name = ?", "a").Delete(&widget{}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := dryRun(t, db, func(tx *gorm.DB) *gorm.DB { return tt.statement(tx.WithContext(withTenant("acme"))) })
+			if _, where, _ := strings.Cut(sql, " WHERE "); !strings.Contains(where, `"widgets".`+tenantFilter) || !containsAll(vars, "acme") {
+				t.Fatalf("statement is not restricted to the tenant: %s %v", sql, vars)
+			}
+
+			sql, _ = dryRun(t, db, func(tx *gorm.DB) *gorm.DB { return tt.statement(tx.WithContext(context.Background())) })
+			if strings.Contains(sql, tenantFilter) {
+				t.Fatalf("statement without tenant is restricted: %s", sql)
+			}
+		})
+	}
+}
+
+func TestTenantIsolationFiltersRows(t *testing.T) {
+	db, mock := mockDB(t, isolation)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "widgets" WHERE "widgets"."tenant_id" = $1 AND "widgets"."deleted_at" IS NULL`)).
+		WithArgs("acme").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	var count int64
+	if err := db.WithContext(withTenant("acme")).Model(&widget{}).Select("count(*)").Row().Scan(&count); err != nil {
+		t.Fatalf("Row: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTenantIsolationRejectsRecordsOfOtherTenants(t *testing.T) {
+	db, _ := mockDB(t, isolation)
+	ctx := withTenant("acme")
+
+	// Loaded records are checked before BeforeUpdate, which would bump the version
+	w := &widget{BaseModel: BaseModel{ID: "w-1", TenantID: "globex", Version: 3}}
+	if err := db.Session(&gorm.Session{DryRun: true}).WithContext(ctx).Model(w).Update("name", "b").Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("update error = %v, want %v", err, ErrTenantMismatch)
+	}
+	if w.Version != 3 {
+		t.Fatalf("version = %d, the rejected update bumped it", w.Version)
+	}
+
+	other := &widget{BaseModel: BaseModel{ID: "w-1", TenantID: "globex"}}
+	if err := db.Session(&gorm.Session{DryRun: true}).WithContext(ctx).Delete(other).Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("delete error = %v, want %v", err, ErrTenantMismatch)
+	}
+}
+
+func TestTenantIsolationAssignsTenantOnCreate(t *testing.T) {
+	db, _ := mockDB(t, isolation)
+	ctx := withTenant("acme")
+
+	tests := []struct {
+		name    string
+		records []*widget
+		wantErr error
+	}{
+		{"without tenant", []*widget{{Name: "a"}}, nil},
+		{"caller's tenant", []*widget{{BaseModel: BaseModel{TenantID: "acme"}, Name: "a"}}, nil},
+		{"other tenant", []*widget{{BaseModel: BaseModel{TenantID: "globex"}, Name: "a"}}, ErrTenantMismatch},
+		{"batch with another tenant", []*widget{{Name: "a"}, {BaseModel: BaseModel{TenantID: "globex"}, Name: "b"}}, ErrTenantMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.Session(&gorm.Session{DryRun: true}).WithContext(ctx).Create(&tt.records).Error
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			for _, record := range tt.records {
+				if record.TenantID != "acme" {
+					t.Fatalf("created record has tenant %q, want the caller's", record.TenantID)
+				}
+			}
+		})
+	}
+
+	// Without tenant in the context, the record keeps the one it was given
+	w := &widget{BaseModel: BaseModel{TenantID: "globex"}}
+	if err := db.Session(&gorm.Session{DryRun: true}).Create(w).Error; err != nil || w.TenantID != "globex" {
+		t.Fatalf("unrestricted Create = %v with tenant %q, want globex", err, w.TenantID)
+	}
+}
+
+func TestTenantIsolationSharedRecords(t *testing.T) {
+	db, _ := mockDB(t, isolation)
+	ctx := withTenant("acme")
+
+	sql, vars := dryRun(t, db, func(tx *gorm.DB) *gorm.DB { return tx.WithContext(ctx).Find(&[]sharedWidget{}) })
+	if !strings.Contains(sql, `"shared_widgets"."tenant_id" IN (`) || !containsAll(vars, "acme", "") {
+		t.Fatalf("query does not match the tenant's and the shared records: %s %v", sql, vars)
+	}
+
+	// Tenants read shared records but cannot modify them
+	global := &sharedWidget{BaseModel: BaseModel{ID: "s-1"}}
+	if err := db.Session(&gorm.Session{DryRun: true}).WithContext(ctx).Model(global).Update("name", "b").Error; !errors.Is(err, ErrTenantMismatch) {
+		t.Fatalf("update of a shared record error = %v, want %v", err, ErrTenantMismatch)
+	}
+	sql, _ = dryRun(t, db, func(tx *gorm.DB) *gorm.DB {
+		return tx.WithContext(ctx).Model(&sharedWidget{}).Where("name = ?", "a").Update("name", "b")
+	})
+	if !strings.Contains(sql, `"shared_widgets".`+tenantFilter) {
+		t.Fatalf("batch update reaches shared records: %s", sql)
+	}
+}
+
+func TestTenantIsolationDoesNotRestrictRawSQL(t *testing.T) {
+	db, _ := mockDB(t, isolation)
+
+	sql, _ := dryRun(t, db, func(tx *gorm.DB) *gorm.DB {
+		return tx.WithContext(withTenant("acme")).Raw(`SELECT * FROM widgets WHERE name = ?`, "a").Scan(&[]widget{})
+	})
+	if strings.Contains(sql, "tenant_id") {
+		t.Fatalf("raw SQL was rewritten: %s", sql)
+	}
+}
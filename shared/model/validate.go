@@ -0,0 +1,273 @@
+package model
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// FieldError describes a single field that failed a validation rule
+type FieldError struct {
+	Field string
+	Rule  string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s fails %q", e.Field, e.Rule)
+}
+
+// ValidationError lists every field that failed validation
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		msgs = append(msgs, field.Error())
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// MinPasswordLength is the minimum number of characters accepted by the password rule
+const MinPasswordLength = 8
+
+// Validate checks the `validate` struct tags of v. Supported rules are required,
+// email, password, rfc3339, min=N and max=N (string lengths are counted in runes).
+// Every failed rule of every field is reported, not only the first.
+func Validate(v any) error {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []FieldError
+	validateStruct(value, func(field reflect.StructField, fieldValue reflect.Value) {
+		errs = append(errs, checkField(field.Name, field.Tag.Get("validate"), fieldValue)...)
+	})
+	if len(errs) > 0 {
+		return &ValidationError{Fields: errs}
+	}
+	return nil
+}
+
+// Fields collects violations of values checked one by one, for types such as
+// generated messages that cannot carry `validate` tags
+type Fields []FieldError
+
+// Check validates value against comma-separated rules in the `validate` tag syntax
+func (f *Fields) Check(name, rules string, value any) {
+	*f = append(*f, checkField(name, rules, reflect.ValueOf(value))...)
+}
+
+// Err returns a *ValidationError listing the violations, or nil
+func (f Fields) Err() error {
+	if len(f) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: f}
+}
+
+// validateStruct walks the exported fields of a struct, descending into embedded structs
+func validateStruct(value reflect.Value, visit func(reflect.StructField, reflect.Value)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			validateStruct(value.Field(i), visit)
+			continue
+		}
+		if _, ok := field.Tag.Lookup("validate"); ok {
+			visit(field, value.Field(i))
+		}
+	}
+}
+
+func checkField(name, tag string, value reflect.Value) []FieldError {
+	var errs []FieldError
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if !checkRule(rule, value) {
+			errs = append(errs, FieldError{Field: name, Rule: rule})
+		}
+	}
+	return errs
+}
+
+func checkRule(rule string, value reflect.Value) bool {
+	if !value.IsValid() {
+		return rule != "required"
+	}
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return rule != "required"
+		}
+		value = value.Elem()
+	}
+
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		return !value.IsZero()
+	case "email":
+		if value.Kind() != reflect.String || value.String() == "" {
+			return true
+		}
+		addr, err := mail.ParseAddress(value.String())
+		return err == nil && addr.Address == value.String()
+	case "password":
+		if value.Kind() != reflect.String || value.String() == "" {
+			return true
+		}
+		return strongPassword(value.String())
+	case "rfc3339":
+		if value.Kind() != reflect.String || value.String() == "" {
+			return true
+		}
+		_, err := time.Parse(time.RFC3339, value.String())
+		return err == nil
+	case "min", "max":
+		limit, err := strconv.Atoi(arg)
+		if err != nil {
+			return false
+		}
+		var size int
+		switch value.Kind() {
+		case reflect.String:
+			size = utf8.RuneCountInString(value.String())
+		case reflect.Slice, reflect.Map, reflect.Array:
+			size = value.Len()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			size = int(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			size = int(value.Uint())
+		default:
+			return true
+		}
+		if name == "min" {
+			return size >= limit
+		}
+		return size <= limit
+	default:
+		// Unknown rules are a programming error in the model definition
+		return false
+	}
+}
+
+// strongPassword requires MinPasswordLength characters mixing letters with digits or symbols
+func strongPassword(password string) bool {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return false
+	}
+	var letter, other bool
+	for _, r := range password {
+		if unicode.IsLetter(r) {
+			letter = true
+		} else if !unicode.IsSpace(r) {
+			other = true
+		}
+	}
+	return letter && other
+}
+
+// Validator is a GORM plugin enforcing `validate` tags on every create and
+// update, regardless of which code path wrote the record
+type Validator struct{}
+
+// Name implements gorm.Plugin
+func (Validator) Name() string {
+	return "momentum:validator"
+}
+
+// Initialize implements gorm.Plugin
+func (p Validator) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:before_create").Before("gorm:create").Register(p.Name()+":create", validateCreate); err != nil {
+		return err
+	}
+	return callbacks.Update().After("gorm:before_update").Before("gorm:update").Register(p.Name()+":update", validateUpdate)
+}
+
+func validateCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	value := reflect.Indirect(db.Statement.ReflectValue)
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := Validate(value.Index(i).Interface()); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := Validate(value.Interface()); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// validateUpdate only checks the columns written by the statement, so partial
+// updates are not rejected for fields they leave untouched
+func validateUpdate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	var errs []FieldError
+	switch dest := db.Statement.Dest.(type) {
+	case map[string]any:
+		for key, v := range dest {
+			if field := db.Statement.Schema.LookUpField(key); field != nil {
+				errs = append(errs, checkField(field.Name, field.Tag.Get("validate"), reflect.ValueOf(v))...)
+			}
+		}
+	default:
+		value := reflect.Indirect(reflect.ValueOf(dest))
+		if value.Kind() != reflect.Struct || value.Type() != db.Statement.Schema.ModelType {
+			return
+		}
+		for _, field := range db.Statement.Schema.Fields {
+			tag, ok := field.Tag.Lookup("validate")
+			if !ok || !updatesField(db.Statement, field, value) {
+				continue
+			}
+			fieldValue, _ := field.ValueOf(db.Statement.Context, value)
+			errs = append(errs, checkField(field.Name, tag, reflect.ValueOf(fieldValue))...)
+		}
+	}
+
+	if len(errs) > 0 {
+		db.AddError(&ValidationError{Fields: errs})
+	}
+}
+
+// updatesField reports whether a struct update writes the field: selected
+// columns when Select was used, non-zero fields otherwise
+func updatesField(stmt *gorm.Statement, field *schema.Field, value reflect.Value) bool {
+	if len(stmt.Selects) > 0 {
+		for _, selected := range stmt.Selects {
+			if selected == "*" || selected == field.Name || selected == field.DBName {
+				return true
+			}
+		}
+		return false
+	}
+	_, zero := field.ValueOf(stmt.Context, value)
+	return !zero
+}
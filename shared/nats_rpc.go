@@ -0,0 +1,251 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabehamasaki/momentum/shared/events"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultNATSSubjectPrefix prefixes the subject of every method served over NATS
+	DefaultNATSSubjectPrefix = "momentum.rpc"
+
+	// DefaultNATSRetryDelay separates the reconnection attempts of a NATSHandler
+	DefaultNATSRetryDelay = 5 * time.Second
+)
+
+// NATSRequest is the JSON body of a request sent to a method served over NATS
+type NATSRequest struct {
+	// Metadata is received by the handler as incoming gRPC metadata, e.g. authorization
+	Metadata map[string][]string `json:"metadata,omitempty"`
+	// Message is the protobuf encoded request message
+	Message []byte `json:"message"`
+	// TimeoutMs is the deadline of the call, like grpc-timeout (0 for none)
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+}
+
+// NATSReply is the JSON body of the reply; exactly one of Message and Status is set
+type NATSReply struct {
+	// Message is the protobuf encoded response message
+	Message []byte `json:"message,omitempty"`
+	// Status is the protobuf encoded google.rpc.Status of a failed call, with its ErrorInfo
+	Status []byte `json:"status,omitempty"`
+
+	Header  map[string][]string `json:"header,omitempty"`
+	Trailer map[string][]string `json:"trailer,omitempty"`
+}
+
+// NATSConfig selects the methods served over NATS and how
+type NATSConfig struct {
+	Logger *zap.Logger
+
+	// Methods are full method names of the service, e.g. /shared.IdentityService/GetUser
+	Methods []string
+
+	// SubjectPrefix defaults to DefaultNATSSubjectPrefix
+	SubjectPrefix string
+
+	// Queue is the queue group shared by the replicas, so each request is answered once;
+	// defaults to the service name
+	Queue string
+
+	// RetryDelay defaults to DefaultNATSRetryDelay
+	RetryDelay time.Duration
+}
+
+// NATSHandler serves selected unary methods of a gRPC service over NATS request-reply,
+// running the same interceptors as the gRPC server
+type NATSHandler struct {
+	conn        *events.NATS
+	impl        any
+	config      NATSConfig
+	methods     map[string]natsMethod
+	interceptor grpc.UnaryServerInterceptor
+
+	mu       sync.Mutex
+	stopped  bool
+	inflight sync.WaitGroup
+}
+
+type natsMethod struct {
+	fullMethod string
+	desc       grpc.MethodDesc
+}
+
+// NewNATSHandler creates a NATS handler for the configured methods of the given service.
+// The interceptors run in the same order as they would when chained on the gRPC server.
+func NewNATSHandler(conn *events.NATS, desc *grpc.ServiceDesc, impl any, config NATSConfig, interceptors ...grpc.UnaryServerInterceptor) (*NATSHandler, error) {
+	if config.Logger == nil {
+		config.Logger = zap.NewNop()
+	}
+	if config.SubjectPrefix == "" {
+		config.SubjectPrefix = DefaultNATSSubjectPrefix
+	}
+	if config.Queue == "" {
+		config.Queue = desc.ServiceName
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultNATSRetryDelay
+	}
+
+	h := &NATSHandler{
+		conn:        conn,
+		impl:        impl,
+		config:      config,
+		methods:     make(map[string]natsMethod, len(config.Methods)),
+		interceptor: chainUnaryInterceptors(interceptors),
+	}
+	for _, fullMethod := range config.Methods {
+		name, ok := strings.CutPrefix(fullMethod, "/"+desc.ServiceName+"/")
+		if !ok {
+			return nil, fmt.Errorf("method %s does not belong to %s", fullMethod, desc.ServiceName)
+		}
+		i := 0
+		for i < len(desc.Methods) && desc.Methods[i].MethodName != name {
+			i++
+		}
+		if i == len(desc.Methods) {
+			return nil, fmt.Errorf("method %s is not a unary method of %s", fullMethod, desc.ServiceName)
+		}
+		h.methods[h.Subject(fullMethod)] = natsMethod{fullMethod: fullMethod, desc: desc.Methods[i]}
+	}
+	return h, nil
+}
+
+// Subject returns the subject a method is served on: /shared.IdentityService/GetUser
+// becomes <prefix>.shared.IdentityService.GetUser
+func (h *NATSHandler) Subject(fullMethod string) string {
+	return h.config.SubjectPrefix + "." + strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", ".")
+}
+
+// Run subscribes to the methods and keeps the connection open until ctx is done,
+// then waits for the calls in progress to reply
+func (h *NATSHandler) Run(ctx context.Context) {
+	defer h.conn.Close()
+	defer h.inflight.Wait()
+
+	for subject := range h.methods {
+		if err := h.conn.Subscribe(ctx, subject, h.config.Queue, h.serve); err != nil {
+			h.config.Logger.Warn("Failed to subscribe to NATS subject", zap.String("subject", subject), zap.Error(err))
+		}
+	}
+
+	for {
+		closed, err := h.conn.Connect(ctx)
+		if err != nil {
+			h.config.Logger.Warn("Failed to connect to NATS, retrying", zap.Duration("retry_delay", h.config.RetryDelay), zap.Error(err))
+		} else {
+			select {
+			case <-closed:
+				h.config.Logger.Warn("NATS connection lost, reconnecting", zap.Duration("retry_delay", h.config.RetryDelay))
+			case <-ctx.Done():
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			h.mu.Lock()
+			h.stopped = true
+			h.mu.Unlock()
+			return
+		case <-time.After(h.config.RetryDelay):
+		}
+	}
+}
+
+// serve answers a request without blocking the connection's reader. Calls are not
+// bound to the Run context, so shutdown drains them like the gRPC server does.
+func (h *NATSHandler) serve(msg events.Msg) {
+	if msg.Reply == "" {
+		h.config.Logger.Debug("Ignoring NATS message without reply subject", zap.String("subject", msg.Subject))
+		return
+	}
+
+	h.mu.Lock()
+	if h.stopped {
+		h.mu.Unlock()
+		return
+	}
+	h.inflight.Add(1)
+	h.mu.Unlock()
+
+	go func() {
+		defer h.inflight.Done()
+		if err := h.conn.PublishData(context.Background(), msg.Reply, h.handle(msg.Subject, msg.Data)); err != nil {
+			h.config.Logger.Warn("Failed to reply over NATS", zap.String("subject", msg.Subject), zap.Error(err))
+		}
+	}()
+}
+
+// handle runs the call encoded in data and returns the encoded NATSReply
+func (h *NATSHandler) handle(subject string, data []byte) []byte {
+	method, ok := h.methods[subject]
+	if !ok {
+		return natsReply(nil, nil, nil, status.Errorf(codes.Unimplemented, "no method is served on %s", subject))
+	}
+
+	var req NATSRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return natsReply(nil, nil, nil, status.Errorf(codes.InvalidArgument, "failed to decode request envelope: %v", err))
+	}
+	if req.TimeoutMs < 0 {
+		return natsReply(nil, nil, nil, status.Errorf(codes.InvalidArgument, "invalid timeout_ms %d", req.TimeoutMs))
+	}
+
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if req.TimeoutMs > 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(req.TimeoutMs)*time.Millisecond)
+	}
+	defer cancel()
+
+	md := metadata.MD{}
+	for key, values := range req.Metadata {
+		md.Append(strings.ToLower(key), values...)
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+	stream := &connectTransportStream{method: method.fullMethod}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+	dec := func(v any) error {
+		if err := proto.Unmarshal(req.Message, v.(proto.Message)); err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed to decode request: %v", err)
+		}
+		return nil
+	}
+
+	resp, err := method.desc.Handler(h.impl, ctx, dec, h.interceptor)
+	header, trailer := stream.metadata()
+	return natsReply(header, trailer, resp, err)
+}
+
+// natsReply encodes the response, or the status of err with its fallback reason
+func natsReply(header, trailer metadata.MD, resp any, err error) []byte {
+	reply := NATSReply{Header: header, Trailer: trailer}
+	if err == nil {
+		if reply.Message, err = proto.Marshal(resp.(proto.Message)); err != nil {
+			err = status.Errorf(codes.Internal, "failed to encode response: %v", err)
+		}
+	}
+	if err != nil {
+		st, ok := status.FromError(withFallbackReason(err))
+		if !ok {
+			st = status.New(codes.Unknown, err.Error())
+		}
+		reply.Message = nil
+		reply.Status, _ = proto.Marshal(st.Proto())
+	}
+
+	data, _ := json.Marshal(reply)
+	return data
+}
@@ -0,0 +1,131 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	grpcstatus "google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// natsIdentity answers GetUser for user-1 with the caller's authorization as name
+type natsIdentity struct {
+	proto.UnimplementedIdentityServiceServer
+}
+
+func (natsIdentity) GetUser(ctx context.Context, req *proto.GetUserRequest) (*proto.GetUserResponse, error) {
+	if req.GetId() != "user-1" {
+		return nil, grpcstatus.Error(codes.NotFound, "user not found")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs("x-served-by", "nats"))
+	return &proto.GetUserResponse{Name: md.Get("authorization")[0]}, nil
+}
+
+func newTestNATSHandler(t *testing.T, interceptors ...grpc.UnaryServerInterceptor) *NATSHandler {
+	t.Helper()
+	h, err := NewNATSHandler(nil, &proto.IdentityService_ServiceDesc, natsIdentity{}, NATSConfig{
+		Methods: []string{proto.IdentityService_GetUser_FullMethodName},
+	}, interceptors...)
+	if err != nil {
+		t.Fatalf("NewNATSHandler: %v", err)
+	}
+	return h
+}
+
+func natsCall(t *testing.T, h *NATSHandler, subject string, id string) NATSReply {
+	t.Helper()
+	message, err := protobuf.Marshal(&proto.GetUserRequest{Id: id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(NATSRequest{Metadata: map[string][]string{"Authorization": {"Bearer token"}}, Message: message})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reply NATSReply
+	if err := json.Unmarshal(h.handle(subject, data), &reply); err != nil {
+		t.Fatalf("decoding reply: %v", err)
+	}
+	return reply
+}
+
+func replyStatus(t *testing.T, reply NATSReply) *grpcstatus.Status {
+	t.Helper()
+	var st status.Status
+	if err := protobuf.Unmarshal(reply.Status, &st); err != nil {
+		t.Fatalf("decoding status: %v", err)
+	}
+	return grpcstatus.FromProto(&st)
+}
+
+func TestNATSHandlerRunsInterceptors(t *testing.T) {
+	var intercepted string
+	h := newTestNATSHandler(t, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		intercepted = info.FullMethod
+		return handler(ctx, req)
+	})
+	subject := h.Subject(proto.IdentityService_GetUser_FullMethodName)
+	if subject != "momentum.rpc.shared.IdentityService.GetUser" {
+		t.Fatalf("subject = %q", subject)
+	}
+
+	reply := natsCall(t, h, subject, "user-1")
+	if reply.Status != nil {
+		t.Fatalf("call failed: %v", replyStatus(t, reply).Err())
+	}
+	var resp proto.GetUserResponse
+	if err := protobuf.Unmarshal(reply.Message, &resp); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if resp.GetName() != "Bearer token" {
+		t.Errorf("handler saw authorization %q, want the request metadata", resp.GetName())
+	}
+	if got := reply.Header["x-served-by"]; len(got) != 1 || got[0] != "nats" {
+		t.Errorf("header = %v, want the one set by the handler", reply.Header)
+	}
+	if intercepted != proto.IdentityService_GetUser_FullMethodName {
+		t.Errorf("interceptor saw method %q", intercepted)
+	}
+}
+
+func TestNATSHandlerReportsErrorReasons(t *testing.T) {
+	h := newTestNATSHandler(t)
+
+	tests := []struct {
+		name       string
+		subject    string
+		wantCode   codes.Code
+		wantReason proto.ErrorReason
+	}{
+		{"handler error", h.Subject(proto.IdentityService_GetUser_FullMethodName), codes.NotFound, proto.ErrorReason_NOT_FOUND},
+		{"method not served", h.Subject(proto.IdentityService_StoreUser_FullMethodName), codes.Unimplemented, proto.ErrorReason_UNIMPLEMENTED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply := natsCall(t, h, tt.subject, "missing")
+			if reply.Message != nil {
+				t.Fatalf("failed call replied with a message")
+			}
+			st := replyStatus(t, reply)
+			if st.Code() != tt.wantCode || ErrorReason(st.Err()) != tt.wantReason {
+				t.Fatalf("status = %v (%v), want %v (%v)", st.Code(), ErrorReason(st.Err()), tt.wantCode, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestNewNATSHandlerRejectsUnknownMethods(t *testing.T) {
+	for _, method := range []string{"/shared.IdentityService/Missing", "/other.Service/GetUser", "/shared.IdentityService/ExportUsers"} {
+		if _, err := NewNATSHandler(nil, &proto.IdentityService_ServiceDesc, natsIdentity{}, NATSConfig{Methods: []string{method}}); err == nil {
+			t.Errorf("NewNATSHandler accepted %s", method)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package shared
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultLogMaxSizeMB is the size at which log files are rotated
+	DefaultLogMaxSizeMB = 100
+
+	// rotationTimeFormat is added to the name of rotated files, e.g. app-2024-05-01T10-00-00.000.log
+	rotationTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// Rotation limits the size and retention of a log file
+type Rotation struct {
+	// MaxSizeMB rotates the file once it would exceed this size (0 never rotates)
+	MaxSizeMB int
+
+	// MaxBackups is the number of rotated files kept (0 keeps every file)
+	MaxBackups int
+
+	// MaxAge removes rotated files older than this (0 keeps every file)
+	MaxAge time.Duration
+
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// RotatingFile is a zapcore.WriteSyncer appending to a file that is renamed with a
+// timestamp suffix when it reaches MaxSizeMB, like lumberjack. Old files are
+// compressed and pruned in the background.
+type RotatingFile struct {
+	path     string
+	rotation Rotation
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// cleanup serializes compression and pruning of rotated files
+	cleanup sync.Mutex
+}
+
+// NewRotatingFile opens path for appending, creating its directory if needed
+func NewRotatingFile(path string, rotation Rotation) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f := &RotatingFile{path: path, rotation: rotation}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write appends p, rotating first when it would push the file past MaxSizeMB. An
+// entry larger than the limit is still written whole to a fresh file.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if max := int64(f.rotation.MaxSizeMB) << 20; max > 0 && f.size > 0 && f.size+int64(len(p)) > max {
+		if err := f.rotate(); err != nil {
+			return 0, fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync flushes the current file to disk
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close closes the current file; later writes fail
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// rotate renames the current file and opens a new one; callers must hold mu
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(f.path)
+	backup := strings.TrimSuffix(f.path, ext) + "-" + time.Now().Format(rotationTimeFormat) + ext
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	go f.cleanupBackups()
+	return nil
+}
+
+// backups lists the rotated files, newest first
+func (f *RotatingFile) backups() ([]string, error) {
+	ext := filepath.Ext(f.path)
+	prefix := filepath.Base(strings.TrimSuffix(f.path, ext)) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if _, err := time.Parse(rotationTimeFormat, stamp); err == nil {
+			names = append(names, name)
+		}
+	}
+	// The timestamp format sorts chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+// cleanupBackups compresses rotated files and removes those beyond MaxBackups or MaxAge
+func (f *RotatingFile) cleanupBackups() {
+	f.cleanup.Lock()
+	defer f.cleanup.Unlock()
+
+	names, err := f.backups()
+	if err != nil {
+		return
+	}
+	dir := filepath.Dir(f.path)
+	for i, name := range names {
+		path := filepath.Join(dir, name)
+		expired := f.rotation.MaxBackups > 0 && i >= f.rotation.MaxBackups
+		if !expired && f.rotation.MaxAge > 0 {
+			if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > f.rotation.MaxAge {
+				expired = true
+			}
+		}
+
+		switch {
+		case expired:
+			os.Remove(path)
+		case f.rotation.Compress && !strings.HasSuffix(name, ".gz"):
+			if err := compressFile(path); err == nil {
+				os.Remove(path)
+			}
+		}
+	}
+}
+
+// compressFile writes path.gz, keeping the modification time for MaxAge
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(dst.Name())
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(path+".gz", info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,132 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSensitiveFields are the field names redacted by every service unless configured otherwise
+var DefaultSensitiveFields = []string{"password", "token", "secret", "key", "authorization", "cookie"}
+
+// SensitiveFieldsFile is the format of the file loaded by LoadSensitiveFields
+type SensitiveFieldsFile struct {
+	// Fields replace DefaultSensitiveFields for every tenant
+	Fields []string `json:"fields"`
+
+	// Tenants adds fields that are only sensitive for the given tenant IDs
+	Tenants map[string][]string `json:"tenants"`
+}
+
+// SensitiveFieldRegistry is the central list of field names that logging and masking
+// layers must redact. It can be extended per tenant and changed at runtime.
+type SensitiveFieldRegistry struct {
+	mu      sync.Mutex
+	fields  []string
+	tenants map[string][]string
+
+	// snapshot is the lowercase base list read on the request hot path
+	snapshot atomic.Pointer[[]string]
+}
+
+// NewSensitiveFieldRegistry creates a registry with the given base fields
+func NewSensitiveFieldRegistry(fields ...string) *SensitiveFieldRegistry {
+	r := &SensitiveFieldRegistry{tenants: make(map[string][]string)}
+	r.fields = normalizeFields(nil, fields)
+	r.publish()
+	return r
+}
+
+// LoadSensitiveFields builds a registry from a JSON file, falling back to
+// DefaultSensitiveFields when path is empty or the file lists no base fields
+func LoadSensitiveFields(path string) (*SensitiveFieldRegistry, error) {
+	if path == "" {
+		return NewSensitiveFieldRegistry(DefaultSensitiveFields...), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sensitive fields file: %w", err)
+	}
+
+	var file SensitiveFieldsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse sensitive fields file: %w", err)
+	}
+
+	fields := file.Fields
+	if len(fields) == 0 {
+		fields = DefaultSensitiveFields
+	}
+	registry := NewSensitiveFieldRegistry(fields...)
+	for tenantID, tenantFields := range file.Tenants {
+		registry.Add(tenantID, tenantFields...)
+	}
+	return registry, nil
+}
+
+// Fields returns the lowercase fields sensitive for every tenant
+func (r *SensitiveFieldRegistry) Fields() []string {
+	return *r.snapshot.Load()
+}
+
+// ForTenant returns the base fields plus the ones added for the tenant
+func (r *SensitiveFieldRegistry) ForTenant(tenantID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return normalizeFields(slices.Clone(r.fields), r.tenants[tenantID])
+}
+
+// Add marks fields as sensitive, for every tenant when tenantID is empty
+func (r *SensitiveFieldRegistry) Add(tenantID string, fields ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tenantID == "" {
+		r.fields = normalizeFields(r.fields, fields)
+		r.publish()
+		return
+	}
+	r.tenants[tenantID] = normalizeFields(r.tenants[tenantID], fields)
+}
+
+// Remove unmarks fields, for every tenant when tenantID is empty
+func (r *SensitiveFieldRegistry) Remove(tenantID string, fields ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remove := func(list []string) []string {
+		return slices.DeleteFunc(list, func(field string) bool {
+			return slices.ContainsFunc(fields, func(f string) bool { return strings.EqualFold(f, field) })
+		})
+	}
+
+	if tenantID == "" {
+		r.fields = remove(slices.Clone(r.fields))
+		r.publish()
+		return
+	}
+	r.tenants[tenantID] = remove(r.tenants[tenantID])
+}
+
+// publish swaps the hot path snapshot; callers must hold mu
+func (r *SensitiveFieldRegistry) publish() {
+	snapshot := slices.Clone(r.fields)
+	r.snapshot.Store(&snapshot)
+}
+
+// normalizeFields appends the lowercase, trimmed fields that are not in list yet
+func normalizeFields(list, fields []string) []string {
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field != "" && !slices.Contains(list, field) {
+			list = append(list, field)
+		}
+	}
+	return list
+}
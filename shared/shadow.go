@@ -0,0 +1,185 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultShadowTimeout bounds each shadow call
+	DefaultShadowTimeout = 5 * time.Second
+
+	// DefaultShadowConcurrency bounds the shadow calls running at once; samples
+	// taken while the limit is reached are skipped
+	DefaultShadowConcurrency = 16
+)
+
+// ShadowHandler is an alternate implementation of a read-only method. It receives
+// a copy of the request and a context carrying the values of the original call.
+type ShadowHandler func(ctx context.Context, req any) (any, error)
+
+// ShadowConfig configures ShadowUnaryInterceptor
+type ShadowConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Metrics receives the shadow_calls_total counter (optional)
+	Metrics *Metrics
+
+	// ServerName labels the metrics
+	ServerName string
+
+	// Handlers maps full method names to their alternate implementation. Only
+	// methods without side effects may be shadowed, since both implementations run.
+	Handlers map[string]ShadowHandler
+
+	// SampleRate is the fraction of calls also sent to the shadow, from 0 to 1
+	SampleRate float64
+
+	// Timeout bounds each shadow call (defaults to DefaultShadowTimeout)
+	Timeout time.Duration
+
+	// Concurrency bounds the shadow calls running at once (defaults to DefaultShadowConcurrency)
+	Concurrency int
+
+	// SensitiveRegistry redacts the responses logged on divergence (optional)
+	SensitiveRegistry *SensitiveFieldRegistry
+}
+
+// shadowResults counts shadow calls by method and result
+type shadowResults struct {
+	mu     sync.Mutex
+	counts map[shadowLabels]uint64
+}
+
+type shadowLabels struct {
+	methodLabels
+	result string
+}
+
+// ShadowUnaryInterceptor sends a sample of the calls to the methods in Handlers to
+// their alternate implementation once the original handler returned, and logs
+// responses or status codes that differ. Callers always get the original response.
+func ShadowUnaryInterceptor(config *ShadowConfig) grpc.UnaryServerInterceptor {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultShadowTimeout
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = DefaultShadowConcurrency
+	}
+	results := &shadowResults{counts: make(map[shadowLabels]uint64)}
+	if config.Metrics != nil {
+		config.Metrics.Register(results)
+	}
+	slots := make(chan struct{}, config.Concurrency)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		shadow, ok := config.Handlers[info.FullMethod]
+		if !ok || config.SampleRate <= 0 || rand.Float64() >= config.SampleRate {
+			return handler(ctx, req)
+		}
+
+		// Copied before the handler runs, in case it modifies the request
+		shadowReq := req
+		if msg, ok := req.(proto.Message); ok {
+			shadowReq = proto.Clone(msg)
+		}
+
+		resp, err := handler(ctx, req)
+
+		labels := newMethodLabels(config.ServerName, info.FullMethod, "unary")
+		select {
+		case slots <- struct{}{}:
+		default:
+			results.add(labels, "skipped")
+			return resp, err
+		}
+
+		go func() {
+			defer func() { <-slots }()
+			shadowCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), config.Timeout)
+			defer cancel()
+
+			shadowResp, shadowErr := shadow(shadowCtx, shadowReq)
+			if sameResult(resp, err, shadowResp, shadowErr) {
+				results.add(labels, "match")
+				return
+			}
+			results.add(labels, "diverged")
+
+			var sensitiveFields []string
+			if config.SensitiveRegistry != nil {
+				sensitiveFields = config.SensitiveRegistry.Fields()
+			}
+			config.Logger.Warn("Shadow response diverged",
+				zap.String("grpc.method", info.FullMethod),
+				zap.String("code", status.Code(err).String()),
+				zap.String("shadow_code", status.Code(shadowErr).String()),
+				zap.String("response", sanitizeFields(resp, sensitiveFields)),
+				zap.String("shadow_response", sanitizeFields(shadowResp, sensitiveFields)),
+			)
+		}()
+		return resp, err
+	}
+}
+
+// sameResult compares the status codes and, for successful calls, the responses
+func sameResult(resp any, err error, shadowResp any, shadowErr error) bool {
+	if status.Code(err) != status.Code(shadowErr) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	msg, ok := resp.(proto.Message)
+	shadowMsg, shadowOK := shadowResp.(proto.Message)
+	if !ok || !shadowOK {
+		return false
+	}
+	return proto.Equal(msg, shadowMsg)
+}
+
+func (r *shadowResults) add(labels methodLabels, result string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counts[shadowLabels{methodLabels: labels, result: result}]++
+}
+
+// WriteMetrics renders the shadow call counters in the Prometheus text format
+func (r *shadowResults) WriteMetrics(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.counts) == 0 {
+		return
+	}
+	labels := make([]shadowLabels, 0, len(r.counts))
+	for l := range r.counts {
+		labels = append(labels, l)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if labels[i].methodLabels != labels[j].methodLabels {
+			return labels[i].methodLabels.less(labels[j].methodLabels)
+		}
+		return labels[i].result < labels[j].result
+	})
+
+	fmt.Fprint(w, "# HELP shadow_calls_total Calls sent to a shadow implementation, by result (match, diverged or skipped).\n")
+	fmt.Fprint(w, "# TYPE shadow_calls_total counter\n")
+	for _, l := range labels {
+		fmt.Fprintf(w, "shadow_calls_total{%s,result=%q} %d\n", l.methodLabels.format(), l.result, r.counts[l])
+	}
+}
@@ -0,0 +1,182 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// DefaultDrainTimeout bounds how long a shutdown waits for in-flight calls and workers
+const DefaultDrainTimeout = 30 * time.Second
+
+// ShutdownConfig configures a ShutdownManager
+type ShutdownConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// DrainTimeout bounds the whole shutdown (defaults to DefaultDrainTimeout). Servers
+	// still draining afterwards are stopped forcibly and running workers are abandoned.
+	DrainTimeout time.Duration
+
+	// Health, when set, reports NOT_SERVING as soon as the shutdown starts, so load
+	// balancers stop routing new calls while in-flight ones drain
+	Health *health.Server
+}
+
+// ShutdownManager stops a service in order: health first, then the servers within
+// the drain timeout, then the background workers. A stream that never ends can no
+// longer block the process from exiting.
+type ShutdownManager struct {
+	config *ShutdownConfig
+
+	mu          sync.Mutex
+	grpcServers []*grpc.Server
+	httpServers map[string]*http.Server
+	running     []string
+
+	workers  sync.WaitGroup
+	inFlight atomic.Int64
+}
+
+// NewShutdownManager creates a manager; servers and workers are added before Shutdown
+func NewShutdownManager(config *ShutdownConfig) *ShutdownManager {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+	if config.DrainTimeout <= 0 {
+		config.DrainTimeout = DefaultDrainTimeout
+	}
+	return &ShutdownManager{config: config, httpServers: make(map[string]*http.Server)}
+}
+
+// AddGRPCServer drains the server on shutdown with GracefulStop
+func (m *ShutdownManager) AddGRPCServer(server *grpc.Server) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.grpcServers = append(m.grpcServers, server)
+}
+
+// AddHTTPServer drains the server on shutdown; name identifies it in the logs
+func (m *ShutdownManager) AddHTTPServer(name string, server *http.Server) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.httpServers[name] = server
+}
+
+// Go runs a background worker that shutdown waits for. The worker must return once
+// the context it was given is cancelled.
+func (m *ShutdownManager) Go(name string, run func()) {
+	m.mu.Lock()
+	m.running = append(m.running, name)
+	m.mu.Unlock()
+
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		defer func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if i := slices.Index(m.running, name); i >= 0 {
+				m.running = slices.Delete(m.running, i, i+1)
+			}
+		}()
+		run()
+	}()
+}
+
+// UnaryInterceptor counts in-flight calls, reported when the drain times out
+func (m *ShutdownManager) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor counts in-flight streams, reported when the drain times out
+func (m *ShutdownManager) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+		return handler(srv, ss)
+	}
+}
+
+// Shutdown marks the service NOT_SERVING, drains the servers until the drain
+// timeout, forcing those still busy to stop, and waits for the workers
+func (m *ShutdownManager) Shutdown() {
+	logger := m.config.Logger
+	if m.config.Health != nil {
+		m.config.Health.Shutdown()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.DrainTimeout)
+	defer cancel()
+
+	m.mu.Lock()
+	grpcServers := slices.Clone(m.grpcServers)
+	httpServers := make(map[string]*http.Server, len(m.httpServers))
+	for name, server := range m.httpServers {
+		httpServers[name] = server
+	}
+	m.mu.Unlock()
+
+	var servers sync.WaitGroup
+	for name, server := range httpServers {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			if err := server.Shutdown(ctx); err != nil {
+				logger.Warn("Drain timeout reached, closing HTTP server",
+					zap.String("server", name),
+					zap.Int64("in_flight", m.inFlight.Load()),
+					zap.Error(err),
+				)
+				_ = server.Close()
+			}
+		}()
+	}
+	for _, server := range grpcServers {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			stopped := make(chan struct{})
+			go func() {
+				server.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				logger.Warn("Drain timeout reached, stopping gRPC server",
+					zap.Int64("in_flight", m.inFlight.Load()),
+				)
+				server.Stop()
+				<-stopped
+			}
+		}()
+	}
+	servers.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		m.mu.Lock()
+		running := slices.Clone(m.running)
+		m.mu.Unlock()
+		logger.Warn("Drain timeout reached, abandoning background workers", zap.Strings("workers", running))
+	}
+}
@@ -0,0 +1,336 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// DefaultSLOWindow is the period over which the error budget is computed
+	DefaultSLOWindow = 24 * time.Hour
+
+	// sloSnapshotInterval is the resolution of the in-memory counter history
+	sloSnapshotInterval = time.Minute
+)
+
+// SLOBurnWindows are the short windows reported for multiwindow burn rate alerts
+var SLOBurnWindows = []time.Duration{5 * time.Minute, time.Hour}
+
+// SLO declares the availability and latency objectives of one RPC
+type SLO struct {
+	// Method is the full gRPC method name, e.g. /shared.IdentityService/GetUser
+	Method string `json:"method"`
+
+	// Availability is the target ratio of calls not failing with a server error (e.g. 0.999)
+	Availability float64 `json:"availability"`
+
+	// Latency is the threshold, e.g. "250ms", that LatencyTarget of calls must meet
+	Latency       string  `json:"latency,omitempty"`
+	LatencyTarget float64 `json:"latency_target,omitempty"`
+
+	latency time.Duration
+}
+
+// SLOFile is the format of the file loaded by LoadSLOs
+type SLOFile struct {
+	// Window is the error budget period, e.g. "24h" (defaults to DefaultSLOWindow)
+	Window string `json:"window"`
+	SLOs   []SLO  `json:"slos"`
+}
+
+// SLOStatus is the compliance of one SLO over the budget window
+type SLOStatus struct {
+	SLO
+	Requests int64
+
+	// Availability and LatencyCompliance are the measured ratios (1 without traffic)
+	Availability      float64
+	LatencyCompliance float64
+
+	// ErrorBudgetRemaining is the unspent share of the availability budget; negative when exhausted
+	ErrorBudgetRemaining float64
+
+	// BurnRates maps each of SLOBurnWindows to how fast the budget is being spent (1 = exactly on budget)
+	BurnRates map[time.Duration]float64
+}
+
+// LoadSLOs reads and validates an SLO definition file
+func LoadSLOs(path string) ([]SLO, time.Duration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read SLO file: %w", err)
+	}
+
+	var file SLOFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse SLO file: %w", err)
+	}
+
+	window := DefaultSLOWindow
+	if file.Window != "" {
+		if window, err = time.ParseDuration(file.Window); err != nil || window < sloSnapshotInterval {
+			return nil, 0, fmt.Errorf("invalid SLO window %q", file.Window)
+		}
+	}
+
+	for i := range file.SLOs {
+		slo := &file.SLOs[i]
+		if slo.Method == "" {
+			return nil, 0, fmt.Errorf("SLO %d: method is required", i)
+		}
+		if slo.Availability <= 0 || slo.Availability >= 1 {
+			return nil, 0, fmt.Errorf("SLO %s: availability must be between 0 and 1", slo.Method)
+		}
+		if slo.Latency != "" {
+			if slo.latency, err = time.ParseDuration(slo.Latency); err != nil || slo.latency <= 0 {
+				return nil, 0, fmt.Errorf("SLO %s: invalid latency %q", slo.Method, slo.Latency)
+			}
+			if slo.LatencyTarget <= 0 || slo.LatencyTarget >= 1 {
+				return nil, 0, fmt.Errorf("SLO %s: latency_target must be between 0 and 1", slo.Method)
+			}
+		}
+	}
+
+	return file.SLOs, window, nil
+}
+
+// sloCounts are cumulative call counts of one method
+type sloCounts struct {
+	total, failed, fast int64
+}
+
+type sloSnapshot struct {
+	at     time.Time
+	counts []sloCounts
+}
+
+// SLOTracker computes error budgets and burn rates from the RPC metrics. It keeps an
+// in-memory history of the counters, so results cover at most the process uptime;
+// long-term reporting should be computed from the exported counters instead.
+type SLOTracker struct {
+	metrics *Metrics
+	slos    []SLO
+	window  time.Duration
+
+	mu        sync.Mutex
+	snapshots []sloSnapshot
+}
+
+// NewSLOTracker creates a tracker for the given objectives and registers its alert
+// metrics with metrics
+func NewSLOTracker(metrics *Metrics, slos []SLO, window time.Duration) *SLOTracker {
+	if window <= 0 {
+		window = DefaultSLOWindow
+	}
+	t := &SLOTracker{metrics: metrics, slos: slos, window: window}
+	t.snapshot(time.Now())
+	metrics.Register(t)
+	return t
+}
+
+// Window returns the error budget period
+func (t *SLOTracker) Window() time.Duration {
+	return t.window
+}
+
+// Run records a counter snapshot every minute until ctx is done
+func (t *SLOTracker) Run(ctx context.Context) {
+	ticker := time.NewTicker(sloSnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			t.snapshot(now)
+		}
+	}
+}
+
+func (t *SLOTracker) snapshot(now time.Time) {
+	counts := t.current()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.snapshots = append(t.snapshots, sloSnapshot{at: now, counts: counts})
+
+	// Keep one snapshot older than the window as the baseline of the full period
+	cutoff := now.Add(-t.window)
+	drop := 0
+	for drop+1 < len(t.snapshots) && !t.snapshots[drop+1].at.After(cutoff) {
+		drop++
+	}
+	t.snapshots = t.snapshots[drop:]
+}
+
+// current reads the cumulative counts of every SLO from the metrics
+func (t *SLOTracker) current() []sloCounts {
+	counts := make([]sloCounts, len(t.slos))
+	for i, slo := range t.slos {
+		counts[i] = t.metrics.methodCounts(slo.Method, slo.latency)
+	}
+	return counts
+}
+
+// baseline returns the counts of the oldest snapshot taken within d of now
+func (t *SLOTracker) baseline(now time.Time, d time.Duration) []sloCounts {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := now.Add(-d)
+	for _, snapshot := range t.snapshots {
+		if !snapshot.at.Before(cutoff) {
+			return snapshot.counts
+		}
+	}
+	return t.snapshots[len(t.snapshots)-1].counts
+}
+
+// Status returns the compliance of every SLO
+func (t *SLOTracker) Status() []SLOStatus {
+	now := time.Now()
+	current := t.current()
+	windowBase := t.baseline(now, t.window)
+	burnBases := make([][]sloCounts, len(SLOBurnWindows))
+	for i, d := range SLOBurnWindows {
+		burnBases[i] = t.baseline(now, d)
+	}
+
+	statuses := make([]SLOStatus, len(t.slos))
+	for i, slo := range t.slos {
+		delta := current[i].sub(windowBase[i])
+		status := SLOStatus{
+			SLO:                  slo,
+			Requests:             delta.total,
+			Availability:         1 - delta.ratio(delta.failed),
+			LatencyCompliance:    delta.ratio(delta.fast),
+			ErrorBudgetRemaining: 1 - delta.ratio(delta.failed)/(1-slo.Availability),
+			BurnRates:            make(map[time.Duration]float64, len(SLOBurnWindows)),
+		}
+		if delta.total == 0 {
+			status.LatencyCompliance = 1
+		}
+		for j, d := range SLOBurnWindows {
+			burn := current[i].sub(burnBases[j][i])
+			status.BurnRates[d] = burn.ratio(burn.failed) / (1 - slo.Availability)
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// WriteMetrics renders the alertable SLO series in the Prometheus text format
+func (t *SLOTracker) WriteMetrics(w io.Writer) {
+	statuses := t.Status()
+	if len(statuses) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("# HELP slo_error_budget_remaining Unspent share of the availability error budget over the SLO window.\n")
+	b.WriteString("# TYPE slo_error_budget_remaining gauge\n")
+	for _, s := range statuses {
+		fmt.Fprintf(&b, "slo_error_budget_remaining{grpc_method=%q} %s\n", s.Method, formatFloat(s.ErrorBudgetRemaining))
+	}
+
+	b.WriteString("# HELP slo_burn_rate Rate at which the error budget is spent, 1 meaning exactly on budget.\n")
+	b.WriteString("# TYPE slo_burn_rate gauge\n")
+	for _, s := range statuses {
+		for _, d := range SLOBurnWindows {
+			fmt.Fprintf(&b, "slo_burn_rate{grpc_method=%q,window=%q} %s\n", s.Method, FormatWindow(d), formatFloat(s.BurnRates[d]))
+		}
+	}
+
+	b.WriteString("# HELP slo_latency_compliance Share of calls meeting the latency threshold over the SLO window.\n")
+	b.WriteString("# TYPE slo_latency_compliance gauge\n")
+	for _, s := range statuses {
+		if s.latency > 0 {
+			fmt.Fprintf(&b, "slo_latency_compliance{grpc_method=%q} %s\n", s.Method, formatFloat(s.LatencyCompliance))
+		}
+	}
+
+	io.WriteString(w, b.String())
+}
+
+func (c sloCounts) sub(base sloCounts) sloCounts {
+	return sloCounts{total: c.total - base.total, failed: c.failed - base.failed, fast: c.fast - base.fast}
+}
+
+// ratio returns n over the total, or 0 without traffic
+func (c sloCounts) ratio(n int64) float64 {
+	if c.total == 0 {
+		return 0
+	}
+	return float64(n) / float64(c.total)
+}
+
+// FormatWindow renders a window without zero units, e.g. "5m" or "24h"
+func FormatWindow(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// serverErrorCodes are the status codes that spend the availability budget; client
+// errors such as InvalidArgument or NotFound do not
+var serverErrorCodes = map[string]bool{
+	codes.Unknown.String():          true,
+	codes.DeadlineExceeded.String(): true,
+	codes.Internal.String():         true,
+	codes.Unavailable.String():      true,
+	codes.DataLoss.String():         true,
+}
+
+// methodCounts sums the calls of a full method name across servers, tenants and
+// stream kinds. Fast calls are counted at the largest bucket not above latency.
+func (m *Metrics) methodCounts(fullMethod string, latency time.Duration) sloCounts {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bucket := -1
+	if latency > 0 {
+		bucket = sort.SearchFloat64s(m.buckets, latency.Seconds())
+		if bucket == len(m.buckets) || m.buckets[bucket] > latency.Seconds() {
+			bucket--
+		}
+	}
+
+	var counts sloCounts
+	for labels, n := range m.handled {
+		if labels.fullMethod() == fullMethod {
+			counts.total += int64(n)
+			if serverErrorCodes[labels.code] {
+				counts.failed += int64(n)
+			}
+		}
+	}
+	if bucket >= 0 {
+		for labels, h := range m.latency {
+			if labels.fullMethod() == fullMethod {
+				counts.fast += int64(h.counts[bucket])
+			}
+		}
+	}
+	return counts
+}
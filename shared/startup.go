@@ -0,0 +1,129 @@
+package shared
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StartupCheck is a named validation run before the server starts accepting traffic
+type StartupCheck struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// StartupCheckResult is the outcome of a single startup check
+type StartupCheckResult struct {
+	Name     string        `json:"name"`
+	OK       bool          `json:"ok"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// StartupReport summarizes every startup check of a service
+type StartupReport struct {
+	Service string               `json:"service"`
+	Version string               `json:"version"`
+	OK      bool                 `json:"ok"`
+	Checks  []StartupCheckResult `json:"checks"`
+}
+
+// RunStartupChecks runs all checks in order, without stopping at the first failure,
+// so a misconfigured service reports every problem at once
+func RunStartupChecks(ctx context.Context, service, version string, checks ...StartupCheck) *StartupReport {
+	report := &StartupReport{Service: service, Version: version, OK: true}
+
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Run(ctx)
+
+		result := StartupCheckResult{Name: check.Name, OK: err == nil, Duration: time.Since(start)}
+		if err != nil {
+			result.Error = err.Error()
+			report.OK = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	return report
+}
+
+// Failed returns the names of the checks that did not pass
+func (r *StartupReport) Failed() []string {
+	var failed []string
+	for _, check := range r.Checks {
+		if !check.OK {
+			failed = append(failed, check.Name)
+		}
+	}
+	return failed
+}
+
+// Log emits the report as a single structured log entry
+func (r *StartupReport) Log(logger *zap.Logger) {
+	fields := []zap.Field{
+		zap.String("server_name", r.Service),
+		zap.String("version", r.Version),
+		zap.Bool("startup.ok", r.OK),
+		zap.Any("startup.checks", r.Checks),
+	}
+
+	if r.OK {
+		logger.Info("Startup report", fields...)
+		return
+	}
+	logger.Error("Startup report", append(fields, zap.Strings("startup.failed", r.Failed()))...)
+}
+
+// WriteError writes a machine-readable description of the failed startup
+func (r *StartupReport) WriteError(w io.Writer) error {
+	return json.NewEncoder(w).Encode(struct {
+		Error  string   `json:"error"`
+		Failed []string `json:"failed_checks"`
+		*StartupReport
+	}{
+		Error:         "startup_validation_failed",
+		Failed:        r.Failed(),
+		StartupReport: r,
+	})
+}
+
+// RequireEnv checks that all the given environment variables are set
+func RequireEnv(names ...string) StartupCheck {
+	return StartupCheck{
+		Name: "env.required",
+		Run: func(ctx context.Context) error {
+			var missing []string
+			for _, name := range names {
+				if os.Getenv(name) == "" {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing environment variables: %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// CheckPortFree checks that a TCP port can be bound
+func CheckPortFree(name, port string) StartupCheck {
+	return StartupCheck{
+		Name: "port." + name,
+		Run: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", ":"+port)
+			if err != nil {
+				return fmt.Errorf("port %s is not available: %w", port, err)
+			}
+			return listener.Close()
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package shared
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+)
+
+type statementCounterKey struct{}
+
+// statementCounter tracks the SQL statements executed while serving one RPC
+type statementCounter struct {
+	mu     sync.Mutex
+	total  int
+	byStmt map[string]int
+}
+
+func (c *statementCounter) record(sql string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total++
+	c.byStmt[sql]++
+}
+
+// mostRepeated returns the statement executed the most times, the usual N+1 culprit
+func (c *statementCounter) mostRepeated() (string, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var top string
+	var count int
+	for sql, n := range c.byStmt {
+		if n > count || (n == count && sql < top) {
+			top, count = sql, n
+		}
+	}
+	return top, count
+}
+
+// StatementBudgetConfig configures the per-request SQL statement budget guard
+type StatementBudgetConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Budget is the maximum number of statements an RPC may execute before it is flagged (0 disables the guard)
+	Budget int
+
+	// ServerName is added to all log entries to identify the server
+	ServerName string
+}
+
+// DefaultStatementBudgetConfig reads the budget from SQL_STATEMENT_BUDGET (disabled when unset)
+func DefaultStatementBudgetConfig() *StatementBudgetConfig {
+	budget, _ := strconv.Atoi(os.Getenv("SQL_STATEMENT_BUDGET"))
+
+	serverName := os.Getenv("SERVER_NAME")
+	if serverName == "" {
+		serverName = "unknown-server"
+	}
+
+	return &StatementBudgetConfig{
+		Logger:     GetLogger(),
+		Budget:     budget,
+		ServerName: serverName,
+	}
+}
+
+// StatementBudgetUnaryInterceptor counts the SQL statements executed by each RPC and
+// logs a warning when a request exceeds the configured budget. Statements are only
+// counted when the query runs with the RPC context and the StatementCounter plugin is
+// registered on the GORM connection.
+func StatementBudgetUnaryInterceptor(config *StatementBudgetConfig) grpc.UnaryServerInterceptor {
+	if config == nil {
+		config = DefaultStatementBudgetConfig()
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if config.Budget <= 0 {
+			return handler(ctx, req)
+		}
+
+		counter := &statementCounter{byStmt: make(map[string]int)}
+		resp, err := handler(context.WithValue(ctx, statementCounterKey{}, counter), req)
+
+		if counter.total > config.Budget {
+			sql, repeated := counter.mostRepeated()
+			config.Logger.Warn("SQL statement budget exceeded",
+				zap.String("server_name", config.ServerName),
+				zap.String("grpc.method", info.FullMethod),
+				zap.Int("db.statements", counter.total),
+				zap.Int("db.statement_budget", config.Budget),
+				zap.String("db.most_repeated_statement", sql),
+				zap.Int("db.most_repeated_count", repeated),
+				zap.Bool("db.n_plus_one_suspect", repeated > 1),
+			)
+		}
+
+		return resp, err
+	}
+}
+
+// StatementCounter is a GORM plugin that reports executed statements to the
+// counter attached by StatementBudgetUnaryInterceptor and to the call cost of
+// CostUnaryInterceptor
+type StatementCounter struct{}
+
+// Name implements gorm.Plugin
+func (StatementCounter) Name() string {
+	return "momentum:statement_counter"
+}
+
+// Initialize implements gorm.Plugin
+func (p StatementCounter) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		name     string
+		register func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range registrations {
+		if err := r.register(p.Name()+":"+r.name, countStatement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countStatement(db *gorm.DB) {
+	if db.Statement == nil || db.Statement.Context == nil || db.Statement.SQL.Len() == 0 {
+		return
+	}
+	if counter, ok := db.Statement.Context.Value(statementCounterKey{}).(*statementCounter); ok {
+		counter.record(db.Statement.SQL.String())
+	}
+	if cost := callCostFrom(db.Statement.Context); cost != nil {
+		cost.statements.record(db.Statement.SQL.String())
+	}
+}
@@ -0,0 +1,129 @@
+package shared
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/stats"
+)
+
+// DefaultLargePayloadBytes is the message size above which a payload is logged as abnormally large
+const DefaultLargePayloadBytes = 1 << 20
+
+// TrafficConfig configures the per-RPC byte and message accounting
+type TrafficConfig struct {
+	// Logger is the zap logger to use (defaults to global logger)
+	Logger *zap.Logger
+
+	// Metrics receives byte and message counters (optional)
+	Metrics *Metrics
+
+	// LargePayloadBytes logs a warning for any single message larger than this (0 disables)
+	LargePayloadBytes int
+
+	// ServerName is added to all metrics and log entries to identify the server
+	ServerName string
+}
+
+// TrafficStatsHandler is a grpc stats.Handler recording request/response sizes and
+// message counts per RPC; install it with grpc.StatsHandler
+type TrafficStatsHandler struct {
+	config *TrafficConfig
+}
+
+// NewTrafficStatsHandler creates the stats handler
+func NewTrafficStatsHandler(config *TrafficConfig) *TrafficStatsHandler {
+	if config.Logger == nil {
+		config.Logger = GetLogger()
+	}
+	return &TrafficStatsHandler{config: config}
+}
+
+// rpcTraffic accumulates the traffic of a single RPC
+type rpcTraffic struct {
+	method      string
+	kind        string
+	bytesIn     atomic.Int64
+	bytesOut    atomic.Int64
+	messagesIn  atomic.Int64
+	messagesOut atomic.Int64
+	largest     atomic.Int64
+}
+
+type rpcTrafficKey struct{}
+
+// TagRPC implements stats.Handler
+func (h *TrafficStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, rpcTrafficKey{}, &rpcTraffic{method: info.FullMethodName, kind: "unary"})
+}
+
+// HandleRPC implements stats.Handler
+func (h *TrafficStatsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	traffic, ok := ctx.Value(rpcTrafficKey{}).(*rpcTraffic)
+	if !ok || s.IsClient() {
+		return
+	}
+
+	switch s := s.(type) {
+	case *stats.Begin:
+		switch {
+		case s.IsClientStream && s.IsServerStream:
+			traffic.kind = "bidi_stream"
+		case s.IsClientStream:
+			traffic.kind = "client_stream"
+		case s.IsServerStream:
+			traffic.kind = "server_stream"
+		}
+	case *stats.InPayload:
+		traffic.bytesIn.Add(int64(s.WireLength))
+		traffic.messagesIn.Add(1)
+		h.checkSize(traffic, "in", s.WireLength)
+	case *stats.OutPayload:
+		traffic.bytesOut.Add(int64(s.WireLength))
+		traffic.messagesOut.Add(1)
+		h.checkSize(traffic, "out", s.WireLength)
+	case *stats.End:
+		if h.config.Metrics != nil {
+			labels := newMethodLabels(h.config.ServerName, traffic.method, traffic.kind)
+			h.config.Metrics.recordTraffic(labels, traffic)
+		}
+		h.config.Logger.Debug("gRPC traffic",
+			zap.String("server_name", h.config.ServerName),
+			zap.String("grpc.method", traffic.method),
+			zap.Int64("grpc.bytes_in", traffic.bytesIn.Load()),
+			zap.Int64("grpc.bytes_out", traffic.bytesOut.Load()),
+			zap.Int64("grpc.messages_in", traffic.messagesIn.Load()),
+			zap.Int64("grpc.messages_out", traffic.messagesOut.Load()),
+			zap.Int64("grpc.largest_message", traffic.largest.Load()),
+		)
+	}
+}
+
+// checkSize tracks the largest message and warns about abnormally large payloads
+func (h *TrafficStatsHandler) checkSize(traffic *rpcTraffic, direction string, size int) {
+	for {
+		largest := traffic.largest.Load()
+		if int64(size) <= largest || traffic.largest.CompareAndSwap(largest, int64(size)) {
+			break
+		}
+	}
+
+	if h.config.LargePayloadBytes > 0 && size > h.config.LargePayloadBytes {
+		h.config.Logger.Warn("gRPC payload exceeds size threshold",
+			zap.String("server_name", h.config.ServerName),
+			zap.String("grpc.method", traffic.method),
+			zap.String("grpc.direction", direction),
+			zap.Int("grpc.message_bytes", size),
+			zap.Int("grpc.threshold_bytes", h.config.LargePayloadBytes),
+		)
+	}
+}
+
+// TagConn implements stats.Handler
+func (h *TrafficStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+// HandleConn implements stats.Handler
+func (h *TrafficStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,89 @@
+package shared
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// TenantMetadataKey carries the tenant of calls that are not authenticated with a token
+	TenantMetadataKey = "x-tenant-id"
+
+	// OtherTenant is the label shared by every tenant beyond the cardinality limit
+	OtherTenant = "other"
+
+	// DefaultTenantLabelLimit is the number of tenants given their own metrics label
+	DefaultTenantLabelLimit = 20
+)
+
+// TenantFromContext returns the tenant of the authenticated caller, falling back to
+// the x-tenant-id metadata when the call carries no token
+func TenantFromContext(ctx context.Context) string {
+	if user, ok := UserFromContext(ctx); ok && user.TenantID != "" {
+		return user.TenantID
+	}
+	return tenantFromMetadata(ctx)
+}
+
+func tenantFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(TenantMetadataKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// TenantLabeler bounds the cardinality of tenant labels: pinned tenants and the first
+// limit tenants seen keep their own label, every later tenant is reported as "other"
+type TenantLabeler struct {
+	mu       sync.RWMutex
+	limit    int
+	pinned   map[string]struct{}
+	admitted map[string]struct{}
+}
+
+// NewTenantLabeler creates a labeler admitting up to limit tenants besides the pinned ones,
+// which should list the largest tenants so they are labeled regardless of arrival order
+func NewTenantLabeler(limit int, pinned ...string) *TenantLabeler {
+	l := &TenantLabeler{
+		limit:    limit,
+		pinned:   make(map[string]struct{}, len(pinned)),
+		admitted: make(map[string]struct{}),
+	}
+	for _, tenantID := range pinned {
+		l.pinned[tenantID] = struct{}{}
+	}
+	return l
+}
+
+// Label returns the metrics label for the tenant; a nil labeler disables tenant labels
+func (l *TenantLabeler) Label(tenantID string) string {
+	if l == nil || tenantID == "" {
+		return ""
+	}
+
+	l.mu.RLock()
+	_, pinned := l.pinned[tenantID]
+	_, admitted := l.admitted[tenantID]
+	full := len(l.admitted) >= l.limit
+	l.mu.RUnlock()
+
+	switch {
+	case pinned || admitted:
+		return tenantID
+	case full:
+		return OtherTenant
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.admitted) >= l.limit {
+		return OtherTenant
+	}
+	l.admitted[tenantID] = struct{}{}
+	return tenantID
+}
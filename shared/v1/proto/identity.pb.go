@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,14 +24,18 @@ const (
 )
 
 type User struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email     string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Role      string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	CreatedAt string                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Nullable timestamps are optional so clients can tell "not set" from empty
+	ScheduleDeactivationAt *string `protobuf:"bytes,7,opt,name=schedule_deactivation_at,json=scheduleDeactivationAt,proto3,oneof" json:"schedule_deactivation_at,omitempty"`
+	DeletedAt              *string `protobuf:"bytes,8,opt,name=deleted_at,json=deletedAt,proto3,oneof" json:"deleted_at,omitempty"`
+	EmailVerifiedAt        *string `protobuf:"bytes,9,opt,name=email_verified_at,json=emailVerifiedAt,proto3,oneof" json:"email_verified_at,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
@@ -98,6 +103,27 @@ func (x *User) GetCreatedAt() string {
 	return ""
 }
 
+func (x *User) GetScheduleDeactivationAt() string {
+	if x != nil && x.ScheduleDeactivationAt != nil {
+		return *x.ScheduleDeactivationAt
+	}
+	return ""
+}
+
+func (x *User) GetDeletedAt() string {
+	if x != nil && x.DeletedAt != nil {
+		return *x.DeletedAt
+	}
+	return ""
+}
+
+func (x *User) GetEmailVerifiedAt() string {
+	if x != nil && x.EmailVerifiedAt != nil {
+		return *x.EmailVerifiedAt
+	}
+	return ""
+}
+
 type Role struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -254,6 +280,246 @@ func (x *GetUsersResponse) GetUsers() []*User {
 	return nil
 }
 
+type ListUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// include_deleted also lists deactivated users, whose deleted_at is set
+	IncludeDeleted bool  `protobuf:"varint,1,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	PageSize       int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is the next_page_token of the previous page. Pages continue after
+	// the last user listed, so writes between pages never repeat or skip users.
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// snapshot pins the listing to the time of its first page: users created later
+	// are left out and users deactivated later are still listed. Fields show their
+	// current values. Only read on the first page; the token carries it afterwards.
+	Snapshot      bool `protobuf:"varint,4,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersRequest) Reset() {
+	*x = ListUsersRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersRequest) ProtoMessage() {}
+
+func (x *ListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersRequest.ProtoReflect.Descriptor instead.
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListUsersRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *ListUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListUsersRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+type ListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*User                `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersResponse) Reset() {
+	*x = ListUsersResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersResponse) ProtoMessage() {}
+
+func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *ListUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SearchUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// query matches words and parts of the name or email, tolerating typos
+	Query         string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize      int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersRequest) Reset() {
+	*x = SearchUsersRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersRequest) ProtoMessage() {}
+
+func (x *SearchUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersRequest.ProtoReflect.Descriptor instead.
+func (*SearchUsersRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SearchUsersRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchUsersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchUsersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type SearchUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// users are ordered by relevance
+	Users         []*User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	NextPageToken string  `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchUsersResponse) Reset() {
+	*x = SearchUsersResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchUsersResponse) ProtoMessage() {}
+
+func (x *SearchUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchUsersResponse.ProtoReflect.Descriptor instead.
+func (*SearchUsersResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *SearchUsersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 type GetUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -263,7 +529,7 @@ type GetUserRequest struct {
 
 func (x *GetUserRequest) Reset() {
 	*x = GetUserRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[4]
+	mi := &file_protobuf_identity_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -275,7 +541,7 @@ func (x *GetUserRequest) String() string {
 func (*GetUserRequest) ProtoMessage() {}
 
 func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[4]
+	mi := &file_protobuf_identity_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +554,7 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
 func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{4}
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *GetUserRequest) GetId() string {
@@ -299,20 +565,22 @@ func (x *GetUserRequest) GetId() string {
 }
 
 type GetUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	CreatedAt     string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
-	RoleId        string                 `protobuf:"bytes,5,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
-	Permissions   []string               `protobuf:"bytes,6,rep,name=permissions,proto3" json:"permissions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Name                   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email                  string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	CreatedAt              string                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Role                   string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	RoleId                 string                 `protobuf:"bytes,5,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	Permissions            []string               `protobuf:"bytes,6,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	ScheduleDeactivationAt *string                `protobuf:"bytes,7,opt,name=schedule_deactivation_at,json=scheduleDeactivationAt,proto3,oneof" json:"schedule_deactivation_at,omitempty"`
+	DeletedAt              *string                `protobuf:"bytes,8,opt,name=deleted_at,json=deletedAt,proto3,oneof" json:"deleted_at,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *GetUserResponse) Reset() {
 	*x = GetUserResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[5]
+	mi := &file_protobuf_identity_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -324,7 +592,7 @@ func (x *GetUserResponse) String() string {
 func (*GetUserResponse) ProtoMessage() {}
 
 func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[5]
+	mi := &file_protobuf_identity_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -337,7 +605,7 @@ func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
 func (*GetUserResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{5}
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetUserResponse) GetName() string {
@@ -382,6 +650,20 @@ func (x *GetUserResponse) GetPermissions() []string {
 	return nil
 }
 
+func (x *GetUserResponse) GetScheduleDeactivationAt() string {
+	if x != nil && x.ScheduleDeactivationAt != nil {
+		return *x.ScheduleDeactivationAt
+	}
+	return ""
+}
+
+func (x *GetUserResponse) GetDeletedAt() string {
+	if x != nil && x.DeletedAt != nil {
+		return *x.DeletedAt
+	}
+	return ""
+}
+
 type StoreUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -394,7 +676,7 @@ type StoreUserRequest struct {
 
 func (x *StoreUserRequest) Reset() {
 	*x = StoreUserRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[6]
+	mi := &file_protobuf_identity_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -406,7 +688,7 @@ func (x *StoreUserRequest) String() string {
 func (*StoreUserRequest) ProtoMessage() {}
 
 func (x *StoreUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[6]
+	mi := &file_protobuf_identity_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -419,7 +701,7 @@ func (x *StoreUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StoreUserRequest.ProtoReflect.Descriptor instead.
 func (*StoreUserRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{6}
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *StoreUserRequest) GetName() string {
@@ -459,7 +741,7 @@ type StoreUserResponse struct {
 
 func (x *StoreUserResponse) Reset() {
 	*x = StoreUserResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[7]
+	mi := &file_protobuf_identity_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -471,7 +753,7 @@ func (x *StoreUserResponse) String() string {
 func (*StoreUserResponse) ProtoMessage() {}
 
 func (x *StoreUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[7]
+	mi := &file_protobuf_identity_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -484,7 +766,7 @@ func (x *StoreUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StoreUserResponse.ProtoReflect.Descriptor instead.
 func (*StoreUserResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{7}
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *StoreUserResponse) GetUser() *User {
@@ -506,7 +788,7 @@ type UpdateUserRequest struct {
 
 func (x *UpdateUserRequest) Reset() {
 	*x = UpdateUserRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[8]
+	mi := &file_protobuf_identity_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -518,7 +800,7 @@ func (x *UpdateUserRequest) String() string {
 func (*UpdateUserRequest) ProtoMessage() {}
 
 func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[8]
+	mi := &file_protobuf_identity_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -531,7 +813,7 @@ func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
 func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{8}
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *UpdateUserRequest) GetId() string {
@@ -563,15 +845,17 @@ func (x *UpdateUserRequest) GetRoleId() string {
 }
 
 type UpdateUserResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// changes lists only the fields whose value was modified
+	Changes       []*FieldChange `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateUserResponse) Reset() {
 	*x = UpdateUserResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[9]
+	mi := &file_protobuf_identity_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -583,7 +867,7 @@ func (x *UpdateUserResponse) String() string {
 func (*UpdateUserResponse) ProtoMessage() {}
 
 func (x *UpdateUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[9]
+	mi := &file_protobuf_identity_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -596,7 +880,7 @@ func (x *UpdateUserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateUserResponse.ProtoReflect.Descriptor instead.
 func (*UpdateUserResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{9}
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *UpdateUserResponse) GetUser() *User {
@@ -606,28 +890,37 @@ func (x *UpdateUserResponse) GetUser() *User {
 	return nil
 }
 
-type DeleteUserRequest struct {
+func (x *UpdateUserResponse) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type FieldChange struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Before        string                 `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	After         string                 `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserRequest) Reset() {
-	*x = DeleteUserRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[10]
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_protobuf_identity_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserRequest) String() string {
+func (x *FieldChange) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserRequest) ProtoMessage() {}
+func (*FieldChange) ProtoMessage() {}
 
-func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[10]
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -638,40 +931,54 @@ func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
-func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *DeleteUserRequest) GetId() string {
+func (x *FieldChange) GetField() string {
 	if x != nil {
-		return x.Id
+		return x.Field
 	}
 	return ""
 }
 
-type DeleteUserResponse struct {
+func (x *FieldChange) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *FieldChange) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+type DeleteUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteUserResponse) Reset() {
-	*x = DeleteUserResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[11]
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteUserResponse) String() string {
+func (x *DeleteUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteUserResponse) ProtoMessage() {}
+func (*DeleteUserRequest) ProtoMessage() {}
 
-func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[11]
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -682,40 +989,40 @@ func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
-func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *DeleteUserResponse) GetSuccess() bool {
+func (x *DeleteUserRequest) GetId() string {
 	if x != nil {
-		return x.Success
+		return x.Id
 	}
-	return false
+	return ""
 }
 
-type RolesResponse struct {
+type DeleteUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Roles         []*Role                `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RolesResponse) Reset() {
-	*x = RolesResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[12]
+func (x *DeleteUserResponse) Reset() {
+	*x = DeleteUserResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RolesResponse) String() string {
+func (x *DeleteUserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RolesResponse) ProtoMessage() {}
+func (*DeleteUserResponse) ProtoMessage() {}
 
-func (x *RolesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[12]
+func (x *DeleteUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -726,40 +1033,42 @@ func (x *RolesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RolesResponse.ProtoReflect.Descriptor instead.
-func (*RolesResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use DeleteUserResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *RolesResponse) GetRoles() []*Role {
+func (x *DeleteUserResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Roles
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-type RoleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+type ScheduleDeactivationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// deactivate_at is an RFC 3339 timestamp in the future
+	DeactivateAt  string `protobuf:"bytes,2,opt,name=deactivate_at,json=deactivateAt,proto3" json:"deactivate_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoleRequest) Reset() {
-	*x = RoleRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[13]
+func (x *ScheduleDeactivationRequest) Reset() {
+	*x = ScheduleDeactivationRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoleRequest) String() string {
+func (x *ScheduleDeactivationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoleRequest) ProtoMessage() {}
+func (*ScheduleDeactivationRequest) ProtoMessage() {}
 
-func (x *RoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[13]
+func (x *ScheduleDeactivationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -770,41 +1079,47 @@ func (x *RoleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoleRequest.ProtoReflect.Descriptor instead.
-func (*RoleRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ScheduleDeactivationRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleDeactivationRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *RoleRequest) GetId() string {
+func (x *ScheduleDeactivationRequest) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type RoleResponse struct {
+func (x *ScheduleDeactivationRequest) GetDeactivateAt() string {
+	if x != nil {
+		return x.DeactivateAt
+	}
+	return ""
+}
+
+type ScheduleDeactivationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Role          *Role                  `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	Permissions   []*Permission          `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoleResponse) Reset() {
-	*x = RoleResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[14]
+func (x *ScheduleDeactivationResponse) Reset() {
+	*x = ScheduleDeactivationResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoleResponse) String() string {
+func (x *ScheduleDeactivationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoleResponse) ProtoMessage() {}
+func (*ScheduleDeactivationResponse) ProtoMessage() {}
 
-func (x *RoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[14]
+func (x *ScheduleDeactivationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -815,48 +1130,40 @@ func (x *RoleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoleResponse.ProtoReflect.Descriptor instead.
-func (*RoleResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{14}
-}
-
-func (x *RoleResponse) GetRole() *Role {
-	if x != nil {
-		return x.Role
-	}
-	return nil
+// Deprecated: Use ScheduleDeactivationResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleDeactivationResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *RoleResponse) GetPermissions() []*Permission {
+func (x *ScheduleDeactivationResponse) GetUser() *User {
 	if x != nil {
-		return x.Permissions
+		return x.User
 	}
 	return nil
 }
 
-type StoreRoleRequest struct {
+type CancelDeactivationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	PermissionIds []string               `protobuf:"bytes,2,rep,name=permission_ids,json=permissionIds,proto3" json:"permission_ids,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StoreRoleRequest) Reset() {
-	*x = StoreRoleRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[15]
+func (x *CancelDeactivationRequest) Reset() {
+	*x = CancelDeactivationRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StoreRoleRequest) String() string {
+func (x *CancelDeactivationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StoreRoleRequest) ProtoMessage() {}
+func (*CancelDeactivationRequest) ProtoMessage() {}
 
-func (x *StoreRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[15]
+func (x *CancelDeactivationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -867,47 +1174,40 @@ func (x *StoreRoleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StoreRoleRequest.ProtoReflect.Descriptor instead.
-func (*StoreRoleRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use CancelDeactivationRequest.ProtoReflect.Descriptor instead.
+func (*CancelDeactivationRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *StoreRoleRequest) GetName() string {
+func (x *CancelDeactivationRequest) GetId() string {
 	if x != nil {
-		return x.Name
+		return x.Id
 	}
 	return ""
 }
 
-func (x *StoreRoleRequest) GetPermissionIds() []string {
-	if x != nil {
-		return x.PermissionIds
-	}
-	return nil
-}
-
-type StoreRoleResponse struct {
+type RestoreUserRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Role          *Role                  `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StoreRoleResponse) Reset() {
-	*x = StoreRoleResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[16]
+func (x *RestoreUserRequest) Reset() {
+	*x = RestoreUserRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StoreRoleResponse) String() string {
+func (x *RestoreUserRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StoreRoleResponse) ProtoMessage() {}
+func (*RestoreUserRequest) ProtoMessage() {}
 
-func (x *StoreRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[16]
+func (x *RestoreUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -918,42 +1218,40 @@ func (x *StoreRoleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StoreRoleResponse.ProtoReflect.Descriptor instead.
-func (*StoreRoleResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use RestoreUserRequest.ProtoReflect.Descriptor instead.
+func (*RestoreUserRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *StoreRoleResponse) GetRole() *Role {
+func (x *RestoreUserRequest) GetId() string {
 	if x != nil {
-		return x.Role
+		return x.Id
 	}
-	return nil
+	return ""
 }
 
-type UpdateRoleRequest struct {
+type RestoreUserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
-	PermissionIds []string               `protobuf:"bytes,3,rep,name=permission_ids,json=permissionIds,proto3" json:"permission_ids,omitempty"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateRoleRequest) Reset() {
-	*x = UpdateRoleRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[17]
+func (x *RestoreUserResponse) Reset() {
+	*x = RestoreUserResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateRoleRequest) String() string {
+func (x *RestoreUserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateRoleRequest) ProtoMessage() {}
+func (*RestoreUserResponse) ProtoMessage() {}
 
-func (x *UpdateRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[17]
+func (x *RestoreUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -964,54 +1262,46 @@ func (x *UpdateRoleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateRoleRequest.ProtoReflect.Descriptor instead.
-func (*UpdateRoleRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{17}
-}
-
-func (x *UpdateRoleRequest) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
-}
-
-func (x *UpdateRoleRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
-	}
-	return ""
+// Deprecated: Use RestoreUserResponse.ProtoReflect.Descriptor instead.
+func (*RestoreUserResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *UpdateRoleRequest) GetPermissionIds() []string {
+func (x *RestoreUserResponse) GetUser() *User {
 	if x != nil {
-		return x.PermissionIds
+		return x.User
 	}
 	return nil
 }
 
-type UpdateRoleResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Role          *Role                  `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+type ExportUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// batch_size is how many users are read from the database at a time
+	BatchSize int32 `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// tenant_id restricts the export to the users of a tenant
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// snapshot reads every batch in one repeatable read transaction, so the export
+	// is a consistent view of the users when it started
+	Snapshot      bool `protobuf:"varint,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateRoleResponse) Reset() {
-	*x = UpdateRoleResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[18]
+func (x *ExportUsersRequest) Reset() {
+	*x = ExportUsersRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateRoleResponse) String() string {
+func (x *ExportUsersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateRoleResponse) ProtoMessage() {}
+func (*ExportUsersRequest) ProtoMessage() {}
 
-func (x *UpdateRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[18]
+func (x *ExportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1022,40 +1312,59 @@ func (x *UpdateRoleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateRoleResponse.ProtoReflect.Descriptor instead.
-func (*UpdateRoleResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use ExportUsersRequest.ProtoReflect.Descriptor instead.
+func (*ExportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *UpdateRoleResponse) GetRole() *Role {
+func (x *ExportUsersRequest) GetBatchSize() int32 {
 	if x != nil {
-		return x.Role
+		return x.BatchSize
 	}
-	return nil
+	return 0
 }
 
-type DeleteRoleRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+func (x *ExportUsersRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ExportUsersRequest) GetSnapshot() bool {
+	if x != nil {
+		return x.Snapshot
+	}
+	return false
+}
+
+type ImportUser struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email  string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	RoleId string                 `protobuf:"bytes,3,opt,name=role_id,json=roleId,proto3" json:"role_id,omitempty"`
+	// password_hash is the bcrypt hash kept by the previous system. Users imported
+	// without one cannot sign in until they reset their password.
+	PasswordHash  string `protobuf:"bytes,4,opt,name=password_hash,json=passwordHash,proto3" json:"password_hash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRoleRequest) Reset() {
-	*x = DeleteRoleRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[19]
+func (x *ImportUser) Reset() {
+	*x = ImportUser{}
+	mi := &file_protobuf_identity_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRoleRequest) String() string {
+func (x *ImportUser) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRoleRequest) ProtoMessage() {}
+func (*ImportUser) ProtoMessage() {}
 
-func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[19]
+func (x *ImportUser) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1066,40 +1375,2702 @@ func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRoleRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRoleRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use ImportUser.ProtoReflect.Descriptor instead.
+func (*ImportUser) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *DeleteRoleRequest) GetId() string {
+func (x *ImportUser) GetName() string {
 	if x != nil {
-		return x.Id
+		return x.Name
 	}
 	return ""
 }
 
-type DeleteRoleResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	unknownFields protoimpl.UnknownFields
+func (x *ImportUser) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ImportUser) GetRoleId() string {
+	if x != nil {
+		return x.RoleId
+	}
+	return ""
+}
+
+func (x *ImportUser) GetPasswordHash() string {
+	if x != nil {
+		return x.PasswordHash
+	}
+	return ""
+}
+
+type BulkImportUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// users are validated one by one; up to 1000 per call
+	Users []*ImportUser `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// tenant_id is the tenant owning the imported users
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// dry_run validates the users without creating them
+	DryRun        bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkImportUsersRequest) Reset() {
+	*x = BulkImportUsersRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkImportUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkImportUsersRequest) ProtoMessage() {}
+
+func (x *BulkImportUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkImportUsersRequest.ProtoReflect.Descriptor instead.
+func (*BulkImportUsersRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BulkImportUsersRequest) GetUsers() []*ImportUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *BulkImportUsersRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *BulkImportUsersRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ImportViolation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Field string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	// rule is the failed rule, e.g. required, email, exists (unknown role) or
+	// unique (email already used or repeated in the request)
+	Rule          string `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportViolation) Reset() {
+	*x = ImportViolation{}
+	mi := &file_protobuf_identity_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportViolation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportViolation) ProtoMessage() {}
+
+func (x *ImportViolation) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportViolation.ProtoReflect.Descriptor instead.
+func (*ImportViolation) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ImportViolation) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ImportViolation) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+type ImportUserResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// index is the position of the user in the request
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// id is the created user, empty for rejected users and dry runs
+	Id            string             `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Violations    []*ImportViolation `protobuf:"bytes,3,rep,name=violations,proto3" json:"violations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportUserResult) Reset() {
+	*x = ImportUserResult{}
+	mi := &file_protobuf_identity_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportUserResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportUserResult) ProtoMessage() {}
+
+func (x *ImportUserResult) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportUserResult.ProtoReflect.Descriptor instead.
+func (*ImportUserResult) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ImportUserResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportUserResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportUserResult) GetViolations() []*ImportViolation {
+	if x != nil {
+		return x.Violations
+	}
+	return nil
+}
+
+type BulkImportUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// results has one entry per requested user, in request order
+	Results       []*ImportUserResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Imported      int32               `protobuf:"varint,2,opt,name=imported,proto3" json:"imported,omitempty"`
+	Rejected      int32               `protobuf:"varint,3,opt,name=rejected,proto3" json:"rejected,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkImportUsersResponse) Reset() {
+	*x = BulkImportUsersResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkImportUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkImportUsersResponse) ProtoMessage() {}
+
+func (x *BulkImportUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkImportUsersResponse.ProtoReflect.Descriptor instead.
+func (*BulkImportUsersResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BulkImportUsersResponse) GetResults() []*ImportUserResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BulkImportUsersResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *BulkImportUsersResponse) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+type RolesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Roles         []*Role                `protobuf:"bytes,1,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RolesResponse) Reset() {
+	*x = RolesResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RolesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RolesResponse) ProtoMessage() {}
+
+func (x *RolesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RolesResponse.ProtoReflect.Descriptor instead.
+func (*RolesResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RolesResponse) GetRoles() []*Role {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type RoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleRequest) Reset() {
+	*x = RoleRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleRequest) ProtoMessage() {}
+
+func (x *RoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleRequest.ProtoReflect.Descriptor instead.
+func (*RoleRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RoleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          *Role                  `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Permissions   []*Permission          `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoleResponse) Reset() {
+	*x = RoleResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoleResponse) ProtoMessage() {}
+
+func (x *RoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoleResponse.ProtoReflect.Descriptor instead.
+func (*RoleResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RoleResponse) GetRole() *Role {
+	if x != nil {
+		return x.Role
+	}
+	return nil
+}
+
+func (x *RoleResponse) GetPermissions() []*Permission {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+type StoreRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	PermissionIds []string               `protobuf:"bytes,2,rep,name=permission_ids,json=permissionIds,proto3" json:"permission_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreRoleRequest) Reset() {
+	*x = StoreRoleRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreRoleRequest) ProtoMessage() {}
+
+func (x *StoreRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreRoleRequest.ProtoReflect.Descriptor instead.
+func (*StoreRoleRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *StoreRoleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *StoreRoleRequest) GetPermissionIds() []string {
+	if x != nil {
+		return x.PermissionIds
+	}
+	return nil
+}
+
+type StoreRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          *Role                  `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoreRoleResponse) Reset() {
+	*x = StoreRoleResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoreRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoreRoleResponse) ProtoMessage() {}
+
+func (x *StoreRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoreRoleResponse.ProtoReflect.Descriptor instead.
+func (*StoreRoleResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *StoreRoleResponse) GetRole() *Role {
+	if x != nil {
+		return x.Role
+	}
+	return nil
+}
+
+type UpdateRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	PermissionIds []string               `protobuf:"bytes,3,rep,name=permission_ids,json=permissionIds,proto3" json:"permission_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoleRequest) Reset() {
+	*x = UpdateRoleRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoleRequest) ProtoMessage() {}
+
+func (x *UpdateRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoleRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UpdateRoleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateRoleRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateRoleRequest) GetPermissionIds() []string {
+	if x != nil {
+		return x.PermissionIds
+	}
+	return nil
+}
+
+type UpdateRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          *Role                  `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoleResponse) Reset() {
+	*x = UpdateRoleResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoleResponse) ProtoMessage() {}
+
+func (x *UpdateRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRoleResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UpdateRoleResponse) GetRole() *Role {
+	if x != nil {
+		return x.Role
+	}
+	return nil
+}
+
+type DeleteRoleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoleRequest) Reset() {
+	*x = DeleteRoleRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleRequest) ProtoMessage() {}
+
+func (x *DeleteRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoleRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DeleteRoleRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoleResponse) Reset() {
+	*x = DeleteRoleResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoleResponse) ProtoMessage() {}
+
+func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoleResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteRoleResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type PermissionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permissions   []*Permission          `protobuf:"bytes,1,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionsResponse) Reset() {
+	*x = PermissionsResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionsResponse) ProtoMessage() {}
+
+func (x *PermissionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionsResponse.ProtoReflect.Descriptor instead.
+func (*PermissionsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PermissionsResponse) GetPermissions() []*Permission {
+	if x != nil {
+		return x.Permissions
+	}
+	return nil
+}
+
+type PermissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionRequest) Reset() {
+	*x = PermissionRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionRequest) ProtoMessage() {}
+
+func (x *PermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionRequest.ProtoReflect.Descriptor instead.
+func (*PermissionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PermissionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PermissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permission    *Permission            `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PermissionResponse) Reset() {
+	*x = PermissionResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PermissionResponse) ProtoMessage() {}
+
+func (x *PermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PermissionResponse.ProtoReflect.Descriptor instead.
+func (*PermissionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PermissionResponse) GetPermission() *Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return nil
+}
+
+type StorePermissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorePermissionRequest) Reset() {
+	*x = StorePermissionRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorePermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorePermissionRequest) ProtoMessage() {}
+
+func (x *StorePermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorePermissionRequest.ProtoReflect.Descriptor instead.
+func (*StorePermissionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *StorePermissionRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type StorePermissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permission    *Permission            `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorePermissionResponse) Reset() {
+	*x = StorePermissionResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorePermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorePermissionResponse) ProtoMessage() {}
+
+func (x *StorePermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorePermissionResponse.ProtoReflect.Descriptor instead.
+func (*StorePermissionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *StorePermissionResponse) GetPermission() *Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return nil
+}
+
+type UpdatePermissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePermissionRequest) Reset() {
+	*x = UpdatePermissionRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePermissionRequest) ProtoMessage() {}
+
+func (x *UpdatePermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePermissionRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePermissionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *UpdatePermissionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdatePermissionRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type UpdatePermissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Permission    *Permission            `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePermissionResponse) Reset() {
+	*x = UpdatePermissionResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePermissionResponse) ProtoMessage() {}
+
+func (x *UpdatePermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePermissionResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePermissionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *UpdatePermissionResponse) GetPermission() *Permission {
+	if x != nil {
+		return x.Permission
+	}
+	return nil
+}
+
+type DeletePermissionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePermissionRequest) Reset() {
+	*x = DeletePermissionRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePermissionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePermissionRequest) ProtoMessage() {}
+
+func (x *DeletePermissionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePermissionRequest.ProtoReflect.Descriptor instead.
+func (*DeletePermissionRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DeletePermissionRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeletePermissionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePermissionResponse) Reset() {
+	*x = DeletePermissionResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePermissionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePermissionResponse) ProtoMessage() {}
+
+func (x *DeletePermissionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePermissionResponse.ProtoReflect.Descriptor instead.
+func (*DeletePermissionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *DeletePermissionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type ConfigBundle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       []byte                 `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Signature     string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	ExportedAt    string                 `protobuf:"bytes,3,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigBundle) Reset() {
+	*x = ConfigBundle{}
+	mi := &file_protobuf_identity_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigBundle) ProtoMessage() {}
+
+func (x *ConfigBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigBundle.ProtoReflect.Descriptor instead.
+func (*ConfigBundle) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ConfigBundle) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ConfigBundle) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *ConfigBundle) GetExportedAt() string {
+	if x != nil {
+		return x.ExportedAt
+	}
+	return ""
+}
+
+type ExportConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bundle        *ConfigBundle          `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportConfigResponse) Reset() {
+	*x = ExportConfigResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportConfigResponse) ProtoMessage() {}
+
+func (x *ExportConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportConfigResponse.ProtoReflect.Descriptor instead.
+func (*ExportConfigResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ExportConfigResponse) GetBundle() *ConfigBundle {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+type ImportConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bundle        *ConfigBundle          `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Prune         bool                   `protobuf:"varint,3,opt,name=prune,proto3" json:"prune,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportConfigRequest) Reset() {
+	*x = ImportConfigRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportConfigRequest) ProtoMessage() {}
+
+func (x *ImportConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportConfigRequest.ProtoReflect.Descriptor instead.
+func (*ImportConfigRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ImportConfigRequest) GetBundle() *ConfigBundle {
+	if x != nil {
+		return x.Bundle
+	}
+	return nil
+}
+
+func (x *ImportConfigRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ImportConfigRequest) GetPrune() bool {
+	if x != nil {
+		return x.Prune
+	}
+	return false
+}
+
+type ImportConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Changes       []string               `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+	Applied       bool                   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportConfigResponse) Reset() {
+	*x = ImportConfigResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportConfigResponse) ProtoMessage() {}
+
+func (x *ImportConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportConfigResponse.ProtoReflect.Descriptor instead.
+func (*ImportConfigResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ImportConfigResponse) GetChanges() []string {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+func (x *ImportConfigResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+type GetSensitiveFieldsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tenant_id includes the fields added for that tenant
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSensitiveFieldsRequest) Reset() {
+	*x = GetSensitiveFieldsRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSensitiveFieldsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSensitiveFieldsRequest) ProtoMessage() {}
+
+func (x *GetSensitiveFieldsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSensitiveFieldsRequest.ProtoReflect.Descriptor instead.
+func (*GetSensitiveFieldsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetSensitiveFieldsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type UpdateSensitiveFieldsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tenant_id scopes the change to one tenant; empty applies to every tenant
+	TenantId      string   `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Add           []string `protobuf:"bytes,2,rep,name=add,proto3" json:"add,omitempty"`
+	Remove        []string `protobuf:"bytes,3,rep,name=remove,proto3" json:"remove,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSensitiveFieldsRequest) Reset() {
+	*x = UpdateSensitiveFieldsRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSensitiveFieldsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSensitiveFieldsRequest) ProtoMessage() {}
+
+func (x *UpdateSensitiveFieldsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSensitiveFieldsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSensitiveFieldsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *UpdateSensitiveFieldsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *UpdateSensitiveFieldsRequest) GetAdd() []string {
+	if x != nil {
+		return x.Add
+	}
+	return nil
+}
+
+func (x *UpdateSensitiveFieldsRequest) GetRemove() []string {
+	if x != nil {
+		return x.Remove
+	}
+	return nil
+}
+
+type SensitiveFieldsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fields        []string               `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SensitiveFieldsResponse) Reset() {
+	*x = SensitiveFieldsResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SensitiveFieldsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SensitiveFieldsResponse) ProtoMessage() {}
+
+func (x *SensitiveFieldsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SensitiveFieldsResponse.ProtoReflect.Descriptor instead.
+func (*SensitiveFieldsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SensitiveFieldsResponse) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type Tenant struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is the tenant_id carried by tokens and stored on the tenant's records
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt     string `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_protobuf_identity_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *Tenant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tenant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tenant) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Tenant) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CreateTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTenantRequest) Reset() {
+	*x = CreateTenantRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTenantRequest) ProtoMessage() {}
+
+func (x *CreateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTenantRequest.ProtoReflect.Descriptor instead.
+func (*CreateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CreateTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantRequest) Reset() {
+	*x = GetTenantRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantRequest) ProtoMessage() {}
+
+func (x *GetTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListTenantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsRequest) ProtoMessage() {}
+
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ListTenantsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTenantsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListTenantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*Tenant              `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsResponse) Reset() {
+	*x = ListTenantsResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsResponse) ProtoMessage() {}
+
+func (x *ListTenantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListTenantsResponse) GetTenants() []*Tenant {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+func (x *ListTenantsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTenantRequest) Reset() {
+	*x = UpdateTenantRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTenantRequest) ProtoMessage() {}
+
+func (x *UpdateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTenantRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *UpdateTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ReassignRoleRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	FromRoleId string                 `protobuf:"bytes,1,opt,name=from_role_id,json=fromRoleId,proto3" json:"from_role_id,omitempty"`
+	ToRoleId   string                 `protobuf:"bytes,2,opt,name=to_role_id,json=toRoleId,proto3" json:"to_role_id,omitempty"`
+	BatchSize  int32                  `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	// operation_id resumes or follows an existing operation instead of starting a new one
+	OperationId   string `protobuf:"bytes,4,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReassignRoleRequest) Reset() {
+	*x = ReassignRoleRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReassignRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReassignRoleRequest) ProtoMessage() {}
+
+func (x *ReassignRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReassignRoleRequest.ProtoReflect.Descriptor instead.
+func (*ReassignRoleRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ReassignRoleRequest) GetFromRoleId() string {
+	if x != nil {
+		return x.FromRoleId
+	}
+	return ""
+}
+
+func (x *ReassignRoleRequest) GetToRoleId() string {
+	if x != nil {
+		return x.ToRoleId
+	}
+	return ""
+}
+
+func (x *ReassignRoleRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *ReassignRoleRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type DeprovisionTenantRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// retention_days is how long suspended users are kept before being purged, 30 when unset
+	RetentionDays int32 `protobuf:"varint,2,opt,name=retention_days,json=retentionDays,proto3" json:"retention_days,omitempty"`
+	// operation_id resumes or follows an existing operation instead of starting a new one
+	OperationId   string `protobuf:"bytes,3,opt,name=operation_id,json=operationId,proto3" json:"operation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeprovisionTenantRequest) Reset() {
+	*x = DeprovisionTenantRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeprovisionTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeprovisionTenantRequest) ProtoMessage() {}
+
+func (x *DeprovisionTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeprovisionTenantRequest.ProtoReflect.Descriptor instead.
+func (*DeprovisionTenantRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *DeprovisionTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeprovisionTenantRequest) GetRetentionDays() int32 {
+	if x != nil {
+		return x.RetentionDays
+	}
+	return 0
+}
+
+func (x *DeprovisionTenantRequest) GetOperationId() string {
+	if x != nil {
+		return x.OperationId
+	}
+	return ""
+}
+
+type Operation struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind  string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// status is one of running, succeeded, failed or cancelled
+	Status        string           `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Done          bool             `protobuf:"varint,4,opt,name=done,proto3" json:"done,omitempty"`
+	Total         int64            `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	Processed     int64            `protobuf:"varint,6,opt,name=processed,proto3" json:"processed,omitempty"`
+	Metadata      *structpb.Struct `protobuf:"bytes,7,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Result        *structpb.Struct `protobuf:"bytes,8,opt,name=result,proto3" json:"result,omitempty"`
+	Error         string           `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     string           `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string           `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Operation) Reset() {
+	*x = Operation{}
+	mi := &file_protobuf_identity_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Operation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Operation) ProtoMessage() {}
+
+func (x *Operation) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Operation.ProtoReflect.Descriptor instead.
+func (*Operation) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *Operation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Operation) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Operation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Operation) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *Operation) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Operation) GetProcessed() int64 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *Operation) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Operation) GetResult() *structpb.Struct {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Operation) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Operation) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Operation) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type GetOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOperationRequest) Reset() {
+	*x = GetOperationRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOperationRequest) ProtoMessage() {}
+
+func (x *GetOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOperationRequest.ProtoReflect.Descriptor instead.
+func (*GetOperationRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListOperationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperationsRequest) Reset() {
+	*x = ListOperationsRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsRequest) ProtoMessage() {}
+
+func (x *ListOperationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsRequest.ProtoReflect.Descriptor instead.
+func (*ListOperationsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ListOperationsRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ListOperationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOperationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListOperationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operations    []*Operation           `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOperationsResponse) Reset() {
+	*x = ListOperationsResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOperationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOperationsResponse) ProtoMessage() {}
+
+func (x *ListOperationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOperationsResponse.ProtoReflect.Descriptor instead.
+func (*ListOperationsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ListOperationsResponse) GetOperations() []*Operation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *ListOperationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type AuditEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId    string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	TenantId   string                 `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Action     string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	TargetType string                 `protobuf:"bytes,5,opt,name=target_type,json=targetType,proto3" json:"target_type,omitempty"`
+	TargetId   string                 `protobuf:"bytes,6,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	// before and after are JSON snapshots of the changed fields, empty when unknown
+	Before        string `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	After         string `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	Code          string `protobuf:"bytes,9,opt,name=code,proto3" json:"code,omitempty"`
+	CreatedAt     string `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_protobuf_identity_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *AuditEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTargetType() string {
+	if x != nil {
+		return x.TargetType
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListAuditEventsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ActorId  string                 `protobuf:"bytes,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	TargetId string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Action   string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	// since and until are RFC 3339 timestamps bounding created_at
+	Since         string `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until         string `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	PageSize      int32  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRoleResponse) Reset() {
-	*x = DeleteRoleResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[20]
+func (x *ListAuditEventsRequest) Reset() {
+	*x = ListAuditEventsRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRoleResponse) String() string {
+func (x *ListAuditEventsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRoleResponse) ProtoMessage() {}
+func (*ListAuditEventsRequest) ProtoMessage() {}
 
-func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[20]
+func (x *ListAuditEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *ListAuditEventsRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetUntil() string {
+	if x != nil {
+		return x.Until
+	}
+	return ""
+}
+
+func (x *ListAuditEventsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAuditEventsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListAuditEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEventsResponse) Reset() {
+	*x = ListAuditEventsResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEventsResponse) ProtoMessage() {}
+
+func (x *ListAuditEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEventsResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListAuditEventsResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListAuditEventsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Artifact struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// kind is the export that produced the artifact, e.g. tenant_archive
+	Kind        string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name        string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	ContentType string `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// sha256 is the hex digest of the content, also sent as Repr-Digest on download
+	Sha256    string `protobuf:"bytes,5,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Size      int64  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	CreatedBy string `protobuf:"bytes,7,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt string `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// download_url is signed and stops working at download_url_expires_at
+	DownloadUrl          string `protobuf:"bytes,9,opt,name=download_url,json=downloadUrl,proto3" json:"download_url,omitempty"`
+	DownloadUrlExpiresAt string `protobuf:"bytes,10,opt,name=download_url_expires_at,json=downloadUrlExpiresAt,proto3" json:"download_url_expires_at,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Artifact) Reset() {
+	*x = Artifact{}
+	mi := &file_protobuf_identity_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artifact) ProtoMessage() {}
+
+func (x *Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artifact.ProtoReflect.Descriptor instead.
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *Artifact) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Artifact) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Artifact) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Artifact) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Artifact) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *Artifact) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Artifact) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *Artifact) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Artifact) GetDownloadUrl() string {
+	if x != nil {
+		return x.DownloadUrl
+	}
+	return ""
+}
+
+func (x *Artifact) GetDownloadUrlExpiresAt() string {
+	if x != nil {
+		return x.DownloadUrlExpiresAt
+	}
+	return ""
+}
+
+type GetArtifactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetArtifactRequest) Reset() {
+	*x = GetArtifactRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetArtifactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArtifactRequest) ProtoMessage() {}
+
+func (x *GetArtifactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArtifactRequest.ProtoReflect.Descriptor instead.
+func (*GetArtifactRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetArtifactRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelOperationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOperationRequest) Reset() {
+	*x = CancelOperationRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOperationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOperationRequest) ProtoMessage() {}
+
+func (x *CancelOperationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOperationRequest.ProtoReflect.Descriptor instead.
+func (*CancelOperationRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *CancelOperationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type BurnRate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Rate          float64                `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BurnRate) Reset() {
+	*x = BurnRate{}
+	mi := &file_protobuf_identity_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BurnRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BurnRate) ProtoMessage() {}
+
+func (x *BurnRate) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1110,40 +4081,55 @@ func (x *DeleteRoleResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRoleResponse.ProtoReflect.Descriptor instead.
-func (*DeleteRoleResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use BurnRate.ProtoReflect.Descriptor instead.
+func (*BurnRate) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *DeleteRoleResponse) GetSuccess() bool {
+func (x *BurnRate) GetWindow() string {
 	if x != nil {
-		return x.Success
+		return x.Window
 	}
-	return false
+	return ""
 }
 
-type PermissionsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Permissions   []*Permission          `protobuf:"bytes,1,rep,name=permissions,proto3" json:"permissions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *BurnRate) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
 }
 
-func (x *PermissionsResponse) Reset() {
-	*x = PermissionsResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[21]
+type SLOStatus struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Method               string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Requests             int64                  `protobuf:"varint,2,opt,name=requests,proto3" json:"requests,omitempty"`
+	AvailabilityTarget   float64                `protobuf:"fixed64,3,opt,name=availability_target,json=availabilityTarget,proto3" json:"availability_target,omitempty"`
+	Availability         float64                `protobuf:"fixed64,4,opt,name=availability,proto3" json:"availability,omitempty"`
+	ErrorBudgetRemaining float64                `protobuf:"fixed64,5,opt,name=error_budget_remaining,json=errorBudgetRemaining,proto3" json:"error_budget_remaining,omitempty"`
+	BurnRates            []*BurnRate            `protobuf:"bytes,6,rep,name=burn_rates,json=burnRates,proto3" json:"burn_rates,omitempty"`
+	LatencyThreshold     string                 `protobuf:"bytes,7,opt,name=latency_threshold,json=latencyThreshold,proto3" json:"latency_threshold,omitempty"`
+	LatencyTarget        float64                `protobuf:"fixed64,8,opt,name=latency_target,json=latencyTarget,proto3" json:"latency_target,omitempty"`
+	LatencyCompliance    float64                `protobuf:"fixed64,9,opt,name=latency_compliance,json=latencyCompliance,proto3" json:"latency_compliance,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *SLOStatus) Reset() {
+	*x = SLOStatus{}
+	mi := &file_protobuf_identity_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PermissionsResponse) String() string {
+func (x *SLOStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PermissionsResponse) ProtoMessage() {}
+func (*SLOStatus) ProtoMessage() {}
 
-func (x *PermissionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[21]
+func (x *SLOStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1154,84 +4140,97 @@ func (x *PermissionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PermissionsResponse.ProtoReflect.Descriptor instead.
-func (*PermissionsResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use SLOStatus.ProtoReflect.Descriptor instead.
+func (*SLOStatus) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *PermissionsResponse) GetPermissions() []*Permission {
+func (x *SLOStatus) GetMethod() string {
 	if x != nil {
-		return x.Permissions
+		return x.Method
 	}
-	return nil
+	return ""
 }
 
-type PermissionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SLOStatus) GetRequests() int64 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
 }
 
-func (x *PermissionRequest) Reset() {
-	*x = PermissionRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[22]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *SLOStatus) GetAvailabilityTarget() float64 {
+	if x != nil {
+		return x.AvailabilityTarget
+	}
+	return 0
 }
 
-func (x *PermissionRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SLOStatus) GetAvailability() float64 {
+	if x != nil {
+		return x.Availability
+	}
+	return 0
 }
 
-func (*PermissionRequest) ProtoMessage() {}
+func (x *SLOStatus) GetErrorBudgetRemaining() float64 {
+	if x != nil {
+		return x.ErrorBudgetRemaining
+	}
+	return 0
+}
 
-func (x *PermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[22]
+func (x *SLOStatus) GetBurnRates() []*BurnRate {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.BurnRates
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use PermissionRequest.ProtoReflect.Descriptor instead.
-func (*PermissionRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{22}
+func (x *SLOStatus) GetLatencyThreshold() string {
+	if x != nil {
+		return x.LatencyThreshold
+	}
+	return ""
 }
 
-func (x *PermissionRequest) GetId() int64 {
+func (x *SLOStatus) GetLatencyTarget() float64 {
 	if x != nil {
-		return x.Id
+		return x.LatencyTarget
 	}
 	return 0
 }
 
-type PermissionResponse struct {
+func (x *SLOStatus) GetLatencyCompliance() float64 {
+	if x != nil {
+		return x.LatencyCompliance
+	}
+	return 0
+}
+
+type SLOStatusResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Permission    *Permission            `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	Window        string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Slos          []*SLOStatus           `protobuf:"bytes,2,rep,name=slos,proto3" json:"slos,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PermissionResponse) Reset() {
-	*x = PermissionResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[23]
+func (x *SLOStatusResponse) Reset() {
+	*x = SLOStatusResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PermissionResponse) String() string {
+func (x *SLOStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PermissionResponse) ProtoMessage() {}
+func (*SLOStatusResponse) ProtoMessage() {}
 
-func (x *PermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[23]
+func (x *SLOStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1242,40 +4241,51 @@ func (x *PermissionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PermissionResponse.ProtoReflect.Descriptor instead.
-func (*PermissionResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use SLOStatusResponse.ProtoReflect.Descriptor instead.
+func (*SLOStatusResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *PermissionResponse) GetPermission() *Permission {
+func (x *SLOStatusResponse) GetWindow() string {
 	if x != nil {
-		return x.Permission
+		return x.Window
+	}
+	return ""
+}
+
+func (x *SLOStatusResponse) GetSlos() []*SLOStatus {
+	if x != nil {
+		return x.Slos
 	}
 	return nil
 }
 
-type StorePermissionRequest struct {
+type VersionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Commit        string                 `protobuf:"bytes,3,opt,name=commit,proto3" json:"commit,omitempty"`
+	BuildTime     string                 `protobuf:"bytes,4,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	GoVersion     string                 `protobuf:"bytes,5,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StorePermissionRequest) Reset() {
-	*x = StorePermissionRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[24]
+func (x *VersionResponse) Reset() {
+	*x = VersionResponse{}
+	mi := &file_protobuf_identity_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StorePermissionRequest) String() string {
+func (x *VersionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StorePermissionRequest) ProtoMessage() {}
+func (*VersionResponse) ProtoMessage() {}
 
-func (x *StorePermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[24]
+func (x *VersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1286,40 +4296,68 @@ func (x *StorePermissionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StorePermissionRequest.ProtoReflect.Descriptor instead.
-func (*StorePermissionRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *StorePermissionRequest) GetName() string {
+func (x *VersionResponse) GetService() string {
 	if x != nil {
-		return x.Name
+		return x.Service
 	}
 	return ""
 }
 
-type StorePermissionResponse struct {
+func (x *VersionResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetBuildTime() string {
+	if x != nil {
+		return x.BuildTime
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+type RequestPasswordResetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Permission    *Permission            `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StorePermissionResponse) Reset() {
-	*x = StorePermissionResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[25]
+func (x *RequestPasswordResetRequest) Reset() {
+	*x = RequestPasswordResetRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StorePermissionResponse) String() string {
+func (x *RequestPasswordResetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StorePermissionResponse) ProtoMessage() {}
+func (*RequestPasswordResetRequest) ProtoMessage() {}
 
-func (x *StorePermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[25]
+func (x *RequestPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1330,41 +4368,41 @@ func (x *StorePermissionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StorePermissionResponse.ProtoReflect.Descriptor instead.
-func (*StorePermissionResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use RequestPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*RequestPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *StorePermissionResponse) GetPermission() *Permission {
+func (x *RequestPasswordResetRequest) GetEmail() string {
 	if x != nil {
-		return x.Permission
+		return x.Email
 	}
-	return nil
+	return ""
 }
 
-type UpdatePermissionRequest struct {
+type ConfirmPasswordResetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	NewPassword   string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdatePermissionRequest) Reset() {
-	*x = UpdatePermissionRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[26]
+func (x *ConfirmPasswordResetRequest) Reset() {
+	*x = ConfirmPasswordResetRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePermissionRequest) String() string {
+func (x *ConfirmPasswordResetRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePermissionRequest) ProtoMessage() {}
+func (*ConfirmPasswordResetRequest) ProtoMessage() {}
 
-func (x *UpdatePermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[26]
+func (x *ConfirmPasswordResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1375,47 +4413,49 @@ func (x *UpdatePermissionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePermissionRequest.ProtoReflect.Descriptor instead.
-func (*UpdatePermissionRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use ConfirmPasswordResetRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPasswordResetRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *UpdatePermissionRequest) GetId() int64 {
+func (x *ConfirmPasswordResetRequest) GetToken() string {
 	if x != nil {
-		return x.Id
+		return x.Token
 	}
-	return 0
+	return ""
 }
 
-func (x *UpdatePermissionRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
+func (x *ConfirmPasswordResetRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
 	}
 	return ""
 }
 
-type UpdatePermissionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Permission    *Permission            `protobuf:"bytes,1,opt,name=permission,proto3" json:"permission,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// ChangePasswordRequest changes the password of the authenticated user
+type ChangePasswordRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	CurrentPassword string                 `protobuf:"bytes,1,opt,name=current_password,json=currentPassword,proto3" json:"current_password,omitempty"`
+	NewPassword     string                 `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *UpdatePermissionResponse) Reset() {
-	*x = UpdatePermissionResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[27]
+func (x *ChangePasswordRequest) Reset() {
+	*x = ChangePasswordRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdatePermissionResponse) String() string {
+func (x *ChangePasswordRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdatePermissionResponse) ProtoMessage() {}
+func (*ChangePasswordRequest) ProtoMessage() {}
 
-func (x *UpdatePermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[27]
+func (x *ChangePasswordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1426,40 +4466,47 @@ func (x *UpdatePermissionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdatePermissionResponse.ProtoReflect.Descriptor instead.
-func (*UpdatePermissionResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use ChangePasswordRequest.ProtoReflect.Descriptor instead.
+func (*ChangePasswordRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *UpdatePermissionResponse) GetPermission() *Permission {
+func (x *ChangePasswordRequest) GetCurrentPassword() string {
 	if x != nil {
-		return x.Permission
+		return x.CurrentPassword
 	}
-	return nil
+	return ""
 }
 
-type DeletePermissionRequest struct {
+func (x *ChangePasswordRequest) GetNewPassword() string {
+	if x != nil {
+		return x.NewPassword
+	}
+	return ""
+}
+
+type SendVerificationEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeletePermissionRequest) Reset() {
-	*x = DeletePermissionRequest{}
-	mi := &file_protobuf_identity_proto_msgTypes[28]
+func (x *SendVerificationEmailRequest) Reset() {
+	*x = SendVerificationEmailRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeletePermissionRequest) String() string {
+func (x *SendVerificationEmailRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeletePermissionRequest) ProtoMessage() {}
+func (*SendVerificationEmailRequest) ProtoMessage() {}
 
-func (x *DeletePermissionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[28]
+func (x *SendVerificationEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1470,40 +4517,40 @@ func (x *DeletePermissionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeletePermissionRequest.ProtoReflect.Descriptor instead.
-func (*DeletePermissionRequest) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use SendVerificationEmailRequest.ProtoReflect.Descriptor instead.
+func (*SendVerificationEmailRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *DeletePermissionRequest) GetId() int64 {
+func (x *SendVerificationEmailRequest) GetEmail() string {
 	if x != nil {
-		return x.Id
+		return x.Email
 	}
-	return 0
+	return ""
 }
 
-type DeletePermissionResponse struct {
+type VerifyEmailRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeletePermissionResponse) Reset() {
-	*x = DeletePermissionResponse{}
-	mi := &file_protobuf_identity_proto_msgTypes[29]
+func (x *VerifyEmailRequest) Reset() {
+	*x = VerifyEmailRequest{}
+	mi := &file_protobuf_identity_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeletePermissionResponse) String() string {
+func (x *VerifyEmailRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeletePermissionResponse) ProtoMessage() {}
+func (*VerifyEmailRequest) ProtoMessage() {}
 
-func (x *DeletePermissionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protobuf_identity_proto_msgTypes[29]
+func (x *VerifyEmailRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protobuf_identity_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1514,30 +4561,37 @@ func (x *DeletePermissionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeletePermissionResponse.ProtoReflect.Descriptor instead.
-func (*DeletePermissionResponse) Descriptor() ([]byte, []int) {
-	return file_protobuf_identity_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use VerifyEmailRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return file_protobuf_identity_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *DeletePermissionResponse) GetSuccess() bool {
+func (x *VerifyEmailRequest) GetToken() string {
 	if x != nil {
-		return x.Success
+		return x.Token
 	}
-	return false
+	return ""
 }
 
 var File_protobuf_identity_proto protoreflect.FileDescriptor
 
 const file_protobuf_identity_proto_rawDesc = "" +
 	"\n" +
-	"\x17protobuf/identity.proto\x12\x06shared\x1a\x1bgoogle/protobuf/empty.proto\"s\n" +
+	"\x17protobuf/identity.proto\x12\x06shared\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\"\xc9\x02\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x03 \x01(\tR\x05email\x12\x12\n" +
 	"\x04role\x18\x04 \x01(\tR\x04role\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\tR\tcreatedAt\"`\n" +
+	"created_at\x18\x06 \x01(\tR\tcreatedAt\x12=\n" +
+	"\x18schedule_deactivation_at\x18\a \x01(\tH\x00R\x16scheduleDeactivationAt\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"deleted_at\x18\b \x01(\tH\x01R\tdeletedAt\x88\x01\x01\x12/\n" +
+	"\x11email_verified_at\x18\t \x01(\tH\x02R\x0femailVerifiedAt\x88\x01\x01B\x1b\n" +
+	"\x19_schedule_deactivation_atB\r\n" +
+	"\v_deleted_atB\x14\n" +
+	"\x12_email_verified_at\"`\n" +
 	"\x04Role\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
@@ -1547,9 +4601,26 @@ const file_protobuf_identity_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\"6\n" +
 	"\x10GetUsersResponse\x12\"\n" +
-	"\x05users\x18\x01 \x03(\v2\f.shared.UserR\x05users\" \n" +
+	"\x05users\x18\x01 \x03(\v2\f.shared.UserR\x05users\"\x93\x01\n" +
+	"\x10ListUsersRequest\x12'\n" +
+	"\x0finclude_deleted\x18\x01 \x01(\bR\x0eincludeDeleted\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x1a\n" +
+	"\bsnapshot\x18\x04 \x01(\bR\bsnapshot\"_\n" +
+	"\x11ListUsersResponse\x12\"\n" +
+	"\x05users\x18\x01 \x03(\v2\f.shared.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"f\n" +
+	"\x12SearchUsersRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"a\n" +
+	"\x13SearchUsersResponse\x12\"\n" +
+	"\x05users\x18\x01 \x03(\v2\f.shared.UserR\x05users\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\" \n" +
 	"\x0eGetUserRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xa9\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xb8\x02\n" +
 	"\x0fGetUserResponse\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1d\n" +
@@ -1557,7 +4628,12 @@ const file_protobuf_identity_proto_rawDesc = "" +
 	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x12\n" +
 	"\x04role\x18\x03 \x01(\tR\x04role\x12\x17\n" +
 	"\arole_id\x18\x05 \x01(\tR\x06roleId\x12 \n" +
-	"\vpermissions\x18\x06 \x03(\tR\vpermissions\"q\n" +
+	"\vpermissions\x18\x06 \x03(\tR\vpermissions\x12=\n" +
+	"\x18schedule_deactivation_at\x18\a \x01(\tH\x00R\x16scheduleDeactivationAt\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"deleted_at\x18\b \x01(\tH\x01R\tdeletedAt\x88\x01\x01B\x1b\n" +
+	"\x19_schedule_deactivation_atB\r\n" +
+	"\v_deleted_at\"q\n" +
 	"\x10StoreUserRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1a\n" +
@@ -1573,13 +4649,57 @@ const file_protobuf_identity_proto_rawDesc = "" +
 	"\x05_nameB\b\n" +
 	"\x06_emailB\n" +
 	"\n" +
-	"\b_role_id\"6\n" +
+	"\b_role_id\"e\n" +
 	"\x12UpdateUserResponse\x12 \n" +
-	"\x04user\x18\x01 \x01(\v2\f.shared.UserR\x04user\"#\n" +
+	"\x04user\x18\x01 \x01(\v2\f.shared.UserR\x04user\x12-\n" +
+	"\achanges\x18\x02 \x03(\v2\x13.shared.FieldChangeR\achanges\"Q\n" +
+	"\vFieldChange\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06before\x18\x02 \x01(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\x03 \x01(\tR\x05after\"#\n" +
 	"\x11DeleteUserRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\".\n" +
 	"\x12DeleteUserResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"3\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"R\n" +
+	"\x1bScheduleDeactivationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12#\n" +
+	"\rdeactivate_at\x18\x02 \x01(\tR\fdeactivateAt\"@\n" +
+	"\x1cScheduleDeactivationResponse\x12 \n" +
+	"\x04user\x18\x01 \x01(\v2\f.shared.UserR\x04user\"+\n" +
+	"\x19CancelDeactivationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"$\n" +
+	"\x12RestoreUserRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"7\n" +
+	"\x13RestoreUserResponse\x12 \n" +
+	"\x04user\x18\x01 \x01(\v2\f.shared.UserR\x04user\"l\n" +
+	"\x12ExportUsersRequest\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x01 \x01(\x05R\tbatchSize\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1a\n" +
+	"\bsnapshot\x18\x03 \x01(\bR\bsnapshot\"t\n" +
+	"\n" +
+	"ImportUser\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x17\n" +
+	"\arole_id\x18\x03 \x01(\tR\x06roleId\x12#\n" +
+	"\rpassword_hash\x18\x04 \x01(\tR\fpasswordHash\"x\n" +
+	"\x16BulkImportUsersRequest\x12(\n" +
+	"\x05users\x18\x01 \x03(\v2\x12.shared.ImportUserR\x05users\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\";\n" +
+	"\x0fImportViolation\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x12\n" +
+	"\x04rule\x18\x02 \x01(\tR\x04rule\"q\n" +
+	"\x10ImportUserResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x127\n" +
+	"\n" +
+	"violations\x18\x03 \x03(\v2\x17.shared.ImportViolationR\n" +
+	"violations\"\x85\x01\n" +
+	"\x17BulkImportUsersResponse\x122\n" +
+	"\aresults\x18\x01 \x03(\v2\x18.shared.ImportUserResultR\aresults\x12\x1a\n" +
+	"\bimported\x18\x02 \x01(\x05R\bimported\x12\x1a\n" +
+	"\brejected\x18\x03 \x01(\x05R\brejected\"3\n" +
 	"\rRolesResponse\x12\"\n" +
 	"\x05roles\x18\x01 \x03(\v2\f.shared.RoleR\x05roles\"\x1d\n" +
 	"\vRoleRequest\x12\x0e\n" +
@@ -1628,15 +4748,192 @@ const file_protobuf_identity_proto_rawDesc = "" +
 	"\x17DeletePermissionRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"4\n" +
 	"\x18DeletePermissionResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xa5\b\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"g\n" +
+	"\fConfigBundle\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\tR\tsignature\x12\x1f\n" +
+	"\vexported_at\x18\x03 \x01(\tR\n" +
+	"exportedAt\"D\n" +
+	"\x14ExportConfigResponse\x12,\n" +
+	"\x06bundle\x18\x01 \x01(\v2\x14.shared.ConfigBundleR\x06bundle\"r\n" +
+	"\x13ImportConfigRequest\x12,\n" +
+	"\x06bundle\x18\x01 \x01(\v2\x14.shared.ConfigBundleR\x06bundle\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\x12\x14\n" +
+	"\x05prune\x18\x03 \x01(\bR\x05prune\"J\n" +
+	"\x14ImportConfigResponse\x12\x18\n" +
+	"\achanges\x18\x01 \x03(\tR\achanges\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\"8\n" +
+	"\x19GetSensitiveFieldsRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"e\n" +
+	"\x1cUpdateSensitiveFieldsRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x10\n" +
+	"\x03add\x18\x02 \x03(\tR\x03add\x12\x16\n" +
+	"\x06remove\x18\x03 \x03(\tR\x06remove\"1\n" +
+	"\x17SensitiveFieldsResponse\x12\x16\n" +
+	"\x06fields\x18\x01 \x03(\tR\x06fields\"j\n" +
+	"\x06Tenant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\"9\n" +
+	"\x13CreateTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\"\n" +
+	"\x10GetTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"P\n" +
+	"\x12ListTenantsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"g\n" +
+	"\x13ListTenantsResponse\x12(\n" +
+	"\atenants\x18\x01 \x03(\v2\x0e.shared.TenantR\atenants\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"9\n" +
+	"\x13UpdateTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x97\x01\n" +
+	"\x13ReassignRoleRequest\x12 \n" +
+	"\ffrom_role_id\x18\x01 \x01(\tR\n" +
+	"fromRoleId\x12\x1c\n" +
+	"\n" +
+	"to_role_id\x18\x02 \x01(\tR\btoRoleId\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x03 \x01(\x05R\tbatchSize\x12!\n" +
+	"\foperation_id\x18\x04 \x01(\tR\voperationId\"\x81\x01\n" +
+	"\x18DeprovisionTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12%\n" +
+	"\x0eretention_days\x18\x02 \x01(\x05R\rretentionDays\x12!\n" +
+	"\foperation_id\x18\x03 \x01(\tR\voperationId\"\xc9\x02\n" +
+	"\tOperation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x12\n" +
+	"\x04done\x18\x04 \x01(\bR\x04done\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\x12\x1c\n" +
+	"\tprocessed\x18\x06 \x01(\x03R\tprocessed\x123\n" +
+	"\bmetadata\x18\a \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12/\n" +
+	"\x06result\x18\b \x01(\v2\x17.google.protobuf.StructR\x06result\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\"%\n" +
+	"\x13GetOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"g\n" +
+	"\x15ListOperationsRequest\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\"s\n" +
+	"\x16ListOperationsResponse\x121\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x11.shared.OperationR\n" +
+	"operations\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x8b\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x1f\n" +
+	"\vtarget_type\x18\x05 \x01(\tR\n" +
+	"targetType\x12\x1b\n" +
+	"\ttarget_id\x18\x06 \x01(\tR\btargetId\x12\x16\n" +
+	"\x06before\x18\a \x01(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\b \x01(\tR\x05after\x12\x12\n" +
+	"\x04code\x18\t \x01(\tR\x04code\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\"\xd0\x01\n" +
+	"\x16ListAuditEventsRequest\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\tR\aactorId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\tR\btargetId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\tR\x05since\x12\x14\n" +
+	"\x05until\x18\x05 \x01(\tR\x05until\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\"m\n" +
+	"\x17ListAuditEventsResponse\x12*\n" +
+	"\x06events\x18\x01 \x03(\v2\x12.shared.AuditEventR\x06events\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa9\x02\n" +
+	"\bArtifact\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\x12\x16\n" +
+	"\x06sha256\x18\x05 \x01(\tR\x06sha256\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\a \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\x12!\n" +
+	"\fdownload_url\x18\t \x01(\tR\vdownloadUrl\x125\n" +
+	"\x17download_url_expires_at\x18\n" +
+	" \x01(\tR\x14downloadUrlExpiresAt\"$\n" +
+	"\x12GetArtifactRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"(\n" +
+	"\x16CancelOperationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\bBurnRate\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\"\xfe\x02\n" +
+	"\tSLOStatus\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x1a\n" +
+	"\brequests\x18\x02 \x01(\x03R\brequests\x12/\n" +
+	"\x13availability_target\x18\x03 \x01(\x01R\x12availabilityTarget\x12\"\n" +
+	"\favailability\x18\x04 \x01(\x01R\favailability\x124\n" +
+	"\x16error_budget_remaining\x18\x05 \x01(\x01R\x14errorBudgetRemaining\x12/\n" +
+	"\n" +
+	"burn_rates\x18\x06 \x03(\v2\x10.shared.BurnRateR\tburnRates\x12+\n" +
+	"\x11latency_threshold\x18\a \x01(\tR\x10latencyThreshold\x12%\n" +
+	"\x0elatency_target\x18\b \x01(\x01R\rlatencyTarget\x12-\n" +
+	"\x12latency_compliance\x18\t \x01(\x01R\x11latencyCompliance\"R\n" +
+	"\x11SLOStatusResponse\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12%\n" +
+	"\x04slos\x18\x02 \x03(\v2\x11.shared.SLOStatusR\x04slos\"\x9b\x01\n" +
+	"\x0fVersionResponse\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06commit\x18\x03 \x01(\tR\x06commit\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x04 \x01(\tR\tbuildTime\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x05 \x01(\tR\tgoVersion\"3\n" +
+	"\x1bRequestPasswordResetRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"V\n" +
+	"\x1bConfirmPasswordResetRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"e\n" +
+	"\x15ChangePasswordRequest\x12)\n" +
+	"\x10current_password\x18\x01 \x01(\tR\x0fcurrentPassword\x12!\n" +
+	"\fnew_password\x18\x02 \x01(\tR\vnewPassword\"4\n" +
+	"\x1cSendVerificationEmailRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\"*\n" +
+	"\x12VerifyEmailRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token2\x88\x19\n" +
 	"\x0fIdentityService\x12<\n" +
-	"\bGetUsers\x12\x16.google.protobuf.Empty\x1a\x18.shared.GetUsersResponse\x12:\n" +
+	"\bGetUsers\x12\x16.google.protobuf.Empty\x1a\x18.shared.GetUsersResponse\x12@\n" +
+	"\tListUsers\x12\x18.shared.ListUsersRequest\x1a\x19.shared.ListUsersResponse\x12F\n" +
+	"\vSearchUsers\x12\x1a.shared.SearchUsersRequest\x1a\x1b.shared.SearchUsersResponse\x12:\n" +
 	"\aGetUser\x12\x16.shared.GetUserRequest\x1a\x17.shared.GetUserResponse\x12@\n" +
 	"\tStoreUser\x12\x18.shared.StoreUserRequest\x1a\x19.shared.StoreUserResponse\x12C\n" +
 	"\n" +
 	"UpdateUser\x12\x19.shared.UpdateUserRequest\x1a\x1a.shared.UpdateUserResponse\x12C\n" +
 	"\n" +
-	"DeleteUser\x12\x19.shared.DeleteUserRequest\x1a\x1a.shared.DeleteUserResponse\x129\n" +
+	"DeleteUser\x12\x19.shared.DeleteUserRequest\x1a\x1a.shared.DeleteUserResponse\x12a\n" +
+	"\x14ScheduleDeactivation\x12#.shared.ScheduleDeactivationRequest\x1a$.shared.ScheduleDeactivationResponse\x12O\n" +
+	"\x12CancelDeactivation\x12!.shared.CancelDeactivationRequest\x1a\x16.google.protobuf.Empty\x12F\n" +
+	"\vRestoreUser\x12\x1a.shared.RestoreUserRequest\x1a\x1b.shared.RestoreUserResponse\x129\n" +
+	"\vExportUsers\x12\x1a.shared.ExportUsersRequest\x1a\f.shared.User0\x01\x12R\n" +
+	"\x0fBulkImportUsers\x12\x1e.shared.BulkImportUsersRequest\x1a\x1f.shared.BulkImportUsersResponse\x12S\n" +
+	"\x14RequestPasswordReset\x12#.shared.RequestPasswordResetRequest\x1a\x16.google.protobuf.Empty\x12S\n" +
+	"\x14ConfirmPasswordReset\x12#.shared.ConfirmPasswordResetRequest\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\x0eChangePassword\x12\x1d.shared.ChangePasswordRequest\x1a\x16.google.protobuf.Empty\x12U\n" +
+	"\x15SendVerificationEmail\x12$.shared.SendVerificationEmailRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\vVerifyEmail\x12\x1a.shared.VerifyEmailRequest\x1a\x16.google.protobuf.Empty\x129\n" +
 	"\bGetRoles\x12\x16.google.protobuf.Empty\x1a\x15.shared.RolesResponse\x124\n" +
 	"\aGetRole\x12\x13.shared.RoleRequest\x1a\x14.shared.RoleResponse\x12@\n" +
 	"\tStoreRole\x12\x18.shared.StoreRoleRequest\x1a\x19.shared.StoreRoleResponse\x12C\n" +
@@ -1648,7 +4945,25 @@ const file_protobuf_identity_proto_rawDesc = "" +
 	"\rGetPermission\x12\x19.shared.PermissionRequest\x1a\x1a.shared.PermissionResponse\x12R\n" +
 	"\x0fStorePermission\x12\x1e.shared.StorePermissionRequest\x1a\x1f.shared.StorePermissionResponse\x12U\n" +
 	"\x10UpdatePermission\x12\x1f.shared.UpdatePermissionRequest\x1a .shared.UpdatePermissionResponse\x12U\n" +
-	"\x10DeletePermission\x12\x1f.shared.DeletePermissionRequest\x1a .shared.DeletePermissionResponseB\n" +
+	"\x10DeletePermission\x12\x1f.shared.DeletePermissionRequest\x1a .shared.DeletePermissionResponse\x12D\n" +
+	"\fExportConfig\x12\x16.google.protobuf.Empty\x1a\x1c.shared.ExportConfigResponse\x12I\n" +
+	"\fImportConfig\x12\x1b.shared.ImportConfigRequest\x1a\x1c.shared.ImportConfigResponse\x12X\n" +
+	"\x12GetSensitiveFields\x12!.shared.GetSensitiveFieldsRequest\x1a\x1f.shared.SensitiveFieldsResponse\x12^\n" +
+	"\x15UpdateSensitiveFields\x12$.shared.UpdateSensitiveFieldsRequest\x1a\x1f.shared.SensitiveFieldsResponse\x12;\n" +
+	"\fCreateTenant\x12\x1b.shared.CreateTenantRequest\x1a\x0e.shared.Tenant\x125\n" +
+	"\tGetTenant\x12\x18.shared.GetTenantRequest\x1a\x0e.shared.Tenant\x12F\n" +
+	"\vListTenants\x12\x1a.shared.ListTenantsRequest\x1a\x1b.shared.ListTenantsResponse\x12;\n" +
+	"\fUpdateTenant\x12\x1b.shared.UpdateTenantRequest\x1a\x0e.shared.Tenant\x12@\n" +
+	"\fReassignRole\x12\x1b.shared.ReassignRoleRequest\x1a\x11.shared.Operation0\x01\x12J\n" +
+	"\x11DeprovisionTenant\x12 .shared.DeprovisionTenantRequest\x1a\x11.shared.Operation0\x01\x12>\n" +
+	"\fGetOperation\x12\x1b.shared.GetOperationRequest\x1a\x11.shared.Operation\x12O\n" +
+	"\x0eListOperations\x12\x1d.shared.ListOperationsRequest\x1a\x1e.shared.ListOperationsResponse\x12I\n" +
+	"\x0fCancelOperation\x12\x1e.shared.CancelOperationRequest\x1a\x16.google.protobuf.Empty\x12R\n" +
+	"\x0fListAuditEvents\x12\x1e.shared.ListAuditEventsRequest\x1a\x1f.shared.ListAuditEventsResponse\x12;\n" +
+	"\vGetArtifact\x12\x1a.shared.GetArtifactRequest\x1a\x10.shared.Artifact\x12A\n" +
+	"\fGetSLOStatus\x12\x16.google.protobuf.Empty\x1a\x19.shared.SLOStatusResponse\x12=\n" +
+	"\n" +
+	"GetVersion\x12\x16.google.protobuf.Empty\x1a\x17.shared.VersionResponseB\n" +
 	"Z\bv1/protob\x06proto3"
 
 var (
@@ -1663,89 +4978,215 @@ func file_protobuf_identity_proto_rawDescGZIP() []byte {
 	return file_protobuf_identity_proto_rawDescData
 }
 
-var file_protobuf_identity_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_protobuf_identity_proto_msgTypes = make([]protoimpl.MessageInfo, 80)
 var file_protobuf_identity_proto_goTypes = []any{
-	(*User)(nil),                     // 0: shared.User
-	(*Role)(nil),                     // 1: shared.Role
-	(*Permission)(nil),               // 2: shared.Permission
-	(*GetUsersResponse)(nil),         // 3: shared.GetUsersResponse
-	(*GetUserRequest)(nil),           // 4: shared.GetUserRequest
-	(*GetUserResponse)(nil),          // 5: shared.GetUserResponse
-	(*StoreUserRequest)(nil),         // 6: shared.StoreUserRequest
-	(*StoreUserResponse)(nil),        // 7: shared.StoreUserResponse
-	(*UpdateUserRequest)(nil),        // 8: shared.UpdateUserRequest
-	(*UpdateUserResponse)(nil),       // 9: shared.UpdateUserResponse
-	(*DeleteUserRequest)(nil),        // 10: shared.DeleteUserRequest
-	(*DeleteUserResponse)(nil),       // 11: shared.DeleteUserResponse
-	(*RolesResponse)(nil),            // 12: shared.RolesResponse
-	(*RoleRequest)(nil),              // 13: shared.RoleRequest
-	(*RoleResponse)(nil),             // 14: shared.RoleResponse
-	(*StoreRoleRequest)(nil),         // 15: shared.StoreRoleRequest
-	(*StoreRoleResponse)(nil),        // 16: shared.StoreRoleResponse
-	(*UpdateRoleRequest)(nil),        // 17: shared.UpdateRoleRequest
-	(*UpdateRoleResponse)(nil),       // 18: shared.UpdateRoleResponse
-	(*DeleteRoleRequest)(nil),        // 19: shared.DeleteRoleRequest
-	(*DeleteRoleResponse)(nil),       // 20: shared.DeleteRoleResponse
-	(*PermissionsResponse)(nil),      // 21: shared.PermissionsResponse
-	(*PermissionRequest)(nil),        // 22: shared.PermissionRequest
-	(*PermissionResponse)(nil),       // 23: shared.PermissionResponse
-	(*StorePermissionRequest)(nil),   // 24: shared.StorePermissionRequest
-	(*StorePermissionResponse)(nil),  // 25: shared.StorePermissionResponse
-	(*UpdatePermissionRequest)(nil),  // 26: shared.UpdatePermissionRequest
-	(*UpdatePermissionResponse)(nil), // 27: shared.UpdatePermissionResponse
-	(*DeletePermissionRequest)(nil),  // 28: shared.DeletePermissionRequest
-	(*DeletePermissionResponse)(nil), // 29: shared.DeletePermissionResponse
-	(*emptypb.Empty)(nil),            // 30: google.protobuf.Empty
+	(*User)(nil),                         // 0: shared.User
+	(*Role)(nil),                         // 1: shared.Role
+	(*Permission)(nil),                   // 2: shared.Permission
+	(*GetUsersResponse)(nil),             // 3: shared.GetUsersResponse
+	(*ListUsersRequest)(nil),             // 4: shared.ListUsersRequest
+	(*ListUsersResponse)(nil),            // 5: shared.ListUsersResponse
+	(*SearchUsersRequest)(nil),           // 6: shared.SearchUsersRequest
+	(*SearchUsersResponse)(nil),          // 7: shared.SearchUsersResponse
+	(*GetUserRequest)(nil),               // 8: shared.GetUserRequest
+	(*GetUserResponse)(nil),              // 9: shared.GetUserResponse
+	(*StoreUserRequest)(nil),             // 10: shared.StoreUserRequest
+	(*StoreUserResponse)(nil),            // 11: shared.StoreUserResponse
+	(*UpdateUserRequest)(nil),            // 12: shared.UpdateUserRequest
+	(*UpdateUserResponse)(nil),           // 13: shared.UpdateUserResponse
+	(*FieldChange)(nil),                  // 14: shared.FieldChange
+	(*DeleteUserRequest)(nil),            // 15: shared.DeleteUserRequest
+	(*DeleteUserResponse)(nil),           // 16: shared.DeleteUserResponse
+	(*ScheduleDeactivationRequest)(nil),  // 17: shared.ScheduleDeactivationRequest
+	(*ScheduleDeactivationResponse)(nil), // 18: shared.ScheduleDeactivationResponse
+	(*CancelDeactivationRequest)(nil),    // 19: shared.CancelDeactivationRequest
+	(*RestoreUserRequest)(nil),           // 20: shared.RestoreUserRequest
+	(*RestoreUserResponse)(nil),          // 21: shared.RestoreUserResponse
+	(*ExportUsersRequest)(nil),           // 22: shared.ExportUsersRequest
+	(*ImportUser)(nil),                   // 23: shared.ImportUser
+	(*BulkImportUsersRequest)(nil),       // 24: shared.BulkImportUsersRequest
+	(*ImportViolation)(nil),              // 25: shared.ImportViolation
+	(*ImportUserResult)(nil),             // 26: shared.ImportUserResult
+	(*BulkImportUsersResponse)(nil),      // 27: shared.BulkImportUsersResponse
+	(*RolesResponse)(nil),                // 28: shared.RolesResponse
+	(*RoleRequest)(nil),                  // 29: shared.RoleRequest
+	(*RoleResponse)(nil),                 // 30: shared.RoleResponse
+	(*StoreRoleRequest)(nil),             // 31: shared.StoreRoleRequest
+	(*StoreRoleResponse)(nil),            // 32: shared.StoreRoleResponse
+	(*UpdateRoleRequest)(nil),            // 33: shared.UpdateRoleRequest
+	(*UpdateRoleResponse)(nil),           // 34: shared.UpdateRoleResponse
+	(*DeleteRoleRequest)(nil),            // 35: shared.DeleteRoleRequest
+	(*DeleteRoleResponse)(nil),           // 36: shared.DeleteRoleResponse
+	(*PermissionsResponse)(nil),          // 37: shared.PermissionsResponse
+	(*PermissionRequest)(nil),            // 38: shared.PermissionRequest
+	(*PermissionResponse)(nil),           // 39: shared.PermissionResponse
+	(*StorePermissionRequest)(nil),       // 40: shared.StorePermissionRequest
+	(*StorePermissionResponse)(nil),      // 41: shared.StorePermissionResponse
+	(*UpdatePermissionRequest)(nil),      // 42: shared.UpdatePermissionRequest
+	(*UpdatePermissionResponse)(nil),     // 43: shared.UpdatePermissionResponse
+	(*DeletePermissionRequest)(nil),      // 44: shared.DeletePermissionRequest
+	(*DeletePermissionResponse)(nil),     // 45: shared.DeletePermissionResponse
+	(*ConfigBundle)(nil),                 // 46: shared.ConfigBundle
+	(*ExportConfigResponse)(nil),         // 47: shared.ExportConfigResponse
+	(*ImportConfigRequest)(nil),          // 48: shared.ImportConfigRequest
+	(*ImportConfigResponse)(nil),         // 49: shared.ImportConfigResponse
+	(*GetSensitiveFieldsRequest)(nil),    // 50: shared.GetSensitiveFieldsRequest
+	(*UpdateSensitiveFieldsRequest)(nil), // 51: shared.UpdateSensitiveFieldsRequest
+	(*SensitiveFieldsResponse)(nil),      // 52: shared.SensitiveFieldsResponse
+	(*Tenant)(nil),                       // 53: shared.Tenant
+	(*CreateTenantRequest)(nil),          // 54: shared.CreateTenantRequest
+	(*GetTenantRequest)(nil),             // 55: shared.GetTenantRequest
+	(*ListTenantsRequest)(nil),           // 56: shared.ListTenantsRequest
+	(*ListTenantsResponse)(nil),          // 57: shared.ListTenantsResponse
+	(*UpdateTenantRequest)(nil),          // 58: shared.UpdateTenantRequest
+	(*ReassignRoleRequest)(nil),          // 59: shared.ReassignRoleRequest
+	(*DeprovisionTenantRequest)(nil),     // 60: shared.DeprovisionTenantRequest
+	(*Operation)(nil),                    // 61: shared.Operation
+	(*GetOperationRequest)(nil),          // 62: shared.GetOperationRequest
+	(*ListOperationsRequest)(nil),        // 63: shared.ListOperationsRequest
+	(*ListOperationsResponse)(nil),       // 64: shared.ListOperationsResponse
+	(*AuditEvent)(nil),                   // 65: shared.AuditEvent
+	(*ListAuditEventsRequest)(nil),       // 66: shared.ListAuditEventsRequest
+	(*ListAuditEventsResponse)(nil),      // 67: shared.ListAuditEventsResponse
+	(*Artifact)(nil),                     // 68: shared.Artifact
+	(*GetArtifactRequest)(nil),           // 69: shared.GetArtifactRequest
+	(*CancelOperationRequest)(nil),       // 70: shared.CancelOperationRequest
+	(*BurnRate)(nil),                     // 71: shared.BurnRate
+	(*SLOStatus)(nil),                    // 72: shared.SLOStatus
+	(*SLOStatusResponse)(nil),            // 73: shared.SLOStatusResponse
+	(*VersionResponse)(nil),              // 74: shared.VersionResponse
+	(*RequestPasswordResetRequest)(nil),  // 75: shared.RequestPasswordResetRequest
+	(*ConfirmPasswordResetRequest)(nil),  // 76: shared.ConfirmPasswordResetRequest
+	(*ChangePasswordRequest)(nil),        // 77: shared.ChangePasswordRequest
+	(*SendVerificationEmailRequest)(nil), // 78: shared.SendVerificationEmailRequest
+	(*VerifyEmailRequest)(nil),           // 79: shared.VerifyEmailRequest
+	(*structpb.Struct)(nil),              // 80: google.protobuf.Struct
+	(*emptypb.Empty)(nil),                // 81: google.protobuf.Empty
 }
 var file_protobuf_identity_proto_depIdxs = []int32{
 	2,  // 0: shared.Role.permissions:type_name -> shared.Permission
 	0,  // 1: shared.GetUsersResponse.users:type_name -> shared.User
-	0,  // 2: shared.StoreUserResponse.user:type_name -> shared.User
-	0,  // 3: shared.UpdateUserResponse.user:type_name -> shared.User
-	1,  // 4: shared.RolesResponse.roles:type_name -> shared.Role
-	1,  // 5: shared.RoleResponse.role:type_name -> shared.Role
-	2,  // 6: shared.RoleResponse.permissions:type_name -> shared.Permission
-	1,  // 7: shared.StoreRoleResponse.role:type_name -> shared.Role
-	1,  // 8: shared.UpdateRoleResponse.role:type_name -> shared.Role
-	2,  // 9: shared.PermissionsResponse.permissions:type_name -> shared.Permission
-	2,  // 10: shared.PermissionResponse.permission:type_name -> shared.Permission
-	2,  // 11: shared.StorePermissionResponse.permission:type_name -> shared.Permission
-	2,  // 12: shared.UpdatePermissionResponse.permission:type_name -> shared.Permission
-	30, // 13: shared.IdentityService.GetUsers:input_type -> google.protobuf.Empty
-	4,  // 14: shared.IdentityService.GetUser:input_type -> shared.GetUserRequest
-	6,  // 15: shared.IdentityService.StoreUser:input_type -> shared.StoreUserRequest
-	8,  // 16: shared.IdentityService.UpdateUser:input_type -> shared.UpdateUserRequest
-	10, // 17: shared.IdentityService.DeleteUser:input_type -> shared.DeleteUserRequest
-	30, // 18: shared.IdentityService.GetRoles:input_type -> google.protobuf.Empty
-	13, // 19: shared.IdentityService.GetRole:input_type -> shared.RoleRequest
-	15, // 20: shared.IdentityService.StoreRole:input_type -> shared.StoreRoleRequest
-	17, // 21: shared.IdentityService.UpdateRole:input_type -> shared.UpdateRoleRequest
-	19, // 22: shared.IdentityService.DeleteRole:input_type -> shared.DeleteRoleRequest
-	30, // 23: shared.IdentityService.GetPermissions:input_type -> google.protobuf.Empty
-	22, // 24: shared.IdentityService.GetPermission:input_type -> shared.PermissionRequest
-	24, // 25: shared.IdentityService.StorePermission:input_type -> shared.StorePermissionRequest
-	26, // 26: shared.IdentityService.UpdatePermission:input_type -> shared.UpdatePermissionRequest
-	28, // 27: shared.IdentityService.DeletePermission:input_type -> shared.DeletePermissionRequest
-	3,  // 28: shared.IdentityService.GetUsers:output_type -> shared.GetUsersResponse
-	5,  // 29: shared.IdentityService.GetUser:output_type -> shared.GetUserResponse
-	7,  // 30: shared.IdentityService.StoreUser:output_type -> shared.StoreUserResponse
-	9,  // 31: shared.IdentityService.UpdateUser:output_type -> shared.UpdateUserResponse
-	11, // 32: shared.IdentityService.DeleteUser:output_type -> shared.DeleteUserResponse
-	12, // 33: shared.IdentityService.GetRoles:output_type -> shared.RolesResponse
-	14, // 34: shared.IdentityService.GetRole:output_type -> shared.RoleResponse
-	16, // 35: shared.IdentityService.StoreRole:output_type -> shared.StoreRoleResponse
-	18, // 36: shared.IdentityService.UpdateRole:output_type -> shared.UpdateRoleResponse
-	20, // 37: shared.IdentityService.DeleteRole:output_type -> shared.DeleteRoleResponse
-	21, // 38: shared.IdentityService.GetPermissions:output_type -> shared.PermissionsResponse
-	23, // 39: shared.IdentityService.GetPermission:output_type -> shared.PermissionResponse
-	25, // 40: shared.IdentityService.StorePermission:output_type -> shared.StorePermissionResponse
-	27, // 41: shared.IdentityService.UpdatePermission:output_type -> shared.UpdatePermissionResponse
-	29, // 42: shared.IdentityService.DeletePermission:output_type -> shared.DeletePermissionResponse
-	28, // [28:43] is the sub-list for method output_type
-	13, // [13:28] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	0,  // 2: shared.ListUsersResponse.users:type_name -> shared.User
+	0,  // 3: shared.SearchUsersResponse.users:type_name -> shared.User
+	0,  // 4: shared.StoreUserResponse.user:type_name -> shared.User
+	0,  // 5: shared.UpdateUserResponse.user:type_name -> shared.User
+	14, // 6: shared.UpdateUserResponse.changes:type_name -> shared.FieldChange
+	0,  // 7: shared.ScheduleDeactivationResponse.user:type_name -> shared.User
+	0,  // 8: shared.RestoreUserResponse.user:type_name -> shared.User
+	23, // 9: shared.BulkImportUsersRequest.users:type_name -> shared.ImportUser
+	25, // 10: shared.ImportUserResult.violations:type_name -> shared.ImportViolation
+	26, // 11: shared.BulkImportUsersResponse.results:type_name -> shared.ImportUserResult
+	1,  // 12: shared.RolesResponse.roles:type_name -> shared.Role
+	1,  // 13: shared.RoleResponse.role:type_name -> shared.Role
+	2,  // 14: shared.RoleResponse.permissions:type_name -> shared.Permission
+	1,  // 15: shared.StoreRoleResponse.role:type_name -> shared.Role
+	1,  // 16: shared.UpdateRoleResponse.role:type_name -> shared.Role
+	2,  // 17: shared.PermissionsResponse.permissions:type_name -> shared.Permission
+	2,  // 18: shared.PermissionResponse.permission:type_name -> shared.Permission
+	2,  // 19: shared.StorePermissionResponse.permission:type_name -> shared.Permission
+	2,  // 20: shared.UpdatePermissionResponse.permission:type_name -> shared.Permission
+	46, // 21: shared.ExportConfigResponse.bundle:type_name -> shared.ConfigBundle
+	46, // 22: shared.ImportConfigRequest.bundle:type_name -> shared.ConfigBundle
+	53, // 23: shared.ListTenantsResponse.tenants:type_name -> shared.Tenant
+	80, // 24: shared.Operation.metadata:type_name -> google.protobuf.Struct
+	80, // 25: shared.Operation.result:type_name -> google.protobuf.Struct
+	61, // 26: shared.ListOperationsResponse.operations:type_name -> shared.Operation
+	65, // 27: shared.ListAuditEventsResponse.events:type_name -> shared.AuditEvent
+	71, // 28: shared.SLOStatus.burn_rates:type_name -> shared.BurnRate
+	72, // 29: shared.SLOStatusResponse.slos:type_name -> shared.SLOStatus
+	81, // 30: shared.IdentityService.GetUsers:input_type -> google.protobuf.Empty
+	4,  // 31: shared.IdentityService.ListUsers:input_type -> shared.ListUsersRequest
+	6,  // 32: shared.IdentityService.SearchUsers:input_type -> shared.SearchUsersRequest
+	8,  // 33: shared.IdentityService.GetUser:input_type -> shared.GetUserRequest
+	10, // 34: shared.IdentityService.StoreUser:input_type -> shared.StoreUserRequest
+	12, // 35: shared.IdentityService.UpdateUser:input_type -> shared.UpdateUserRequest
+	15, // 36: shared.IdentityService.DeleteUser:input_type -> shared.DeleteUserRequest
+	17, // 37: shared.IdentityService.ScheduleDeactivation:input_type -> shared.ScheduleDeactivationRequest
+	19, // 38: shared.IdentityService.CancelDeactivation:input_type -> shared.CancelDeactivationRequest
+	20, // 39: shared.IdentityService.RestoreUser:input_type -> shared.RestoreUserRequest
+	22, // 40: shared.IdentityService.ExportUsers:input_type -> shared.ExportUsersRequest
+	24, // 41: shared.IdentityService.BulkImportUsers:input_type -> shared.BulkImportUsersRequest
+	75, // 42: shared.IdentityService.RequestPasswordReset:input_type -> shared.RequestPasswordResetRequest
+	76, // 43: shared.IdentityService.ConfirmPasswordReset:input_type -> shared.ConfirmPasswordResetRequest
+	77, // 44: shared.IdentityService.ChangePassword:input_type -> shared.ChangePasswordRequest
+	78, // 45: shared.IdentityService.SendVerificationEmail:input_type -> shared.SendVerificationEmailRequest
+	79, // 46: shared.IdentityService.VerifyEmail:input_type -> shared.VerifyEmailRequest
+	81, // 47: shared.IdentityService.GetRoles:input_type -> google.protobuf.Empty
+	29, // 48: shared.IdentityService.GetRole:input_type -> shared.RoleRequest
+	31, // 49: shared.IdentityService.StoreRole:input_type -> shared.StoreRoleRequest
+	33, // 50: shared.IdentityService.UpdateRole:input_type -> shared.UpdateRoleRequest
+	35, // 51: shared.IdentityService.DeleteRole:input_type -> shared.DeleteRoleRequest
+	81, // 52: shared.IdentityService.GetPermissions:input_type -> google.protobuf.Empty
+	38, // 53: shared.IdentityService.GetPermission:input_type -> shared.PermissionRequest
+	40, // 54: shared.IdentityService.StorePermission:input_type -> shared.StorePermissionRequest
+	42, // 55: shared.IdentityService.UpdatePermission:input_type -> shared.UpdatePermissionRequest
+	44, // 56: shared.IdentityService.DeletePermission:input_type -> shared.DeletePermissionRequest
+	81, // 57: shared.IdentityService.ExportConfig:input_type -> google.protobuf.Empty
+	48, // 58: shared.IdentityService.ImportConfig:input_type -> shared.ImportConfigRequest
+	50, // 59: shared.IdentityService.GetSensitiveFields:input_type -> shared.GetSensitiveFieldsRequest
+	51, // 60: shared.IdentityService.UpdateSensitiveFields:input_type -> shared.UpdateSensitiveFieldsRequest
+	54, // 61: shared.IdentityService.CreateTenant:input_type -> shared.CreateTenantRequest
+	55, // 62: shared.IdentityService.GetTenant:input_type -> shared.GetTenantRequest
+	56, // 63: shared.IdentityService.ListTenants:input_type -> shared.ListTenantsRequest
+	58, // 64: shared.IdentityService.UpdateTenant:input_type -> shared.UpdateTenantRequest
+	59, // 65: shared.IdentityService.ReassignRole:input_type -> shared.ReassignRoleRequest
+	60, // 66: shared.IdentityService.DeprovisionTenant:input_type -> shared.DeprovisionTenantRequest
+	62, // 67: shared.IdentityService.GetOperation:input_type -> shared.GetOperationRequest
+	63, // 68: shared.IdentityService.ListOperations:input_type -> shared.ListOperationsRequest
+	70, // 69: shared.IdentityService.CancelOperation:input_type -> shared.CancelOperationRequest
+	66, // 70: shared.IdentityService.ListAuditEvents:input_type -> shared.ListAuditEventsRequest
+	69, // 71: shared.IdentityService.GetArtifact:input_type -> shared.GetArtifactRequest
+	81, // 72: shared.IdentityService.GetSLOStatus:input_type -> google.protobuf.Empty
+	81, // 73: shared.IdentityService.GetVersion:input_type -> google.protobuf.Empty
+	3,  // 74: shared.IdentityService.GetUsers:output_type -> shared.GetUsersResponse
+	5,  // 75: shared.IdentityService.ListUsers:output_type -> shared.ListUsersResponse
+	7,  // 76: shared.IdentityService.SearchUsers:output_type -> shared.SearchUsersResponse
+	9,  // 77: shared.IdentityService.GetUser:output_type -> shared.GetUserResponse
+	11, // 78: shared.IdentityService.StoreUser:output_type -> shared.StoreUserResponse
+	13, // 79: shared.IdentityService.UpdateUser:output_type -> shared.UpdateUserResponse
+	16, // 80: shared.IdentityService.DeleteUser:output_type -> shared.DeleteUserResponse
+	18, // 81: shared.IdentityService.ScheduleDeactivation:output_type -> shared.ScheduleDeactivationResponse
+	81, // 82: shared.IdentityService.CancelDeactivation:output_type -> google.protobuf.Empty
+	21, // 83: shared.IdentityService.RestoreUser:output_type -> shared.RestoreUserResponse
+	0,  // 84: shared.IdentityService.ExportUsers:output_type -> shared.User
+	27, // 85: shared.IdentityService.BulkImportUsers:output_type -> shared.BulkImportUsersResponse
+	81, // 86: shared.IdentityService.RequestPasswordReset:output_type -> google.protobuf.Empty
+	81, // 87: shared.IdentityService.ConfirmPasswordReset:output_type -> google.protobuf.Empty
+	81, // 88: shared.IdentityService.ChangePassword:output_type -> google.protobuf.Empty
+	81, // 89: shared.IdentityService.SendVerificationEmail:output_type -> google.protobuf.Empty
+	81, // 90: shared.IdentityService.VerifyEmail:output_type -> google.protobuf.Empty
+	28, // 91: shared.IdentityService.GetRoles:output_type -> shared.RolesResponse
+	30, // 92: shared.IdentityService.GetRole:output_type -> shared.RoleResponse
+	32, // 93: shared.IdentityService.StoreRole:output_type -> shared.StoreRoleResponse
+	34, // 94: shared.IdentityService.UpdateRole:output_type -> shared.UpdateRoleResponse
+	36, // 95: shared.IdentityService.DeleteRole:output_type -> shared.DeleteRoleResponse
+	37, // 96: shared.IdentityService.GetPermissions:output_type -> shared.PermissionsResponse
+	39, // 97: shared.IdentityService.GetPermission:output_type -> shared.PermissionResponse
+	41, // 98: shared.IdentityService.StorePermission:output_type -> shared.StorePermissionResponse
+	43, // 99: shared.IdentityService.UpdatePermission:output_type -> shared.UpdatePermissionResponse
+	45, // 100: shared.IdentityService.DeletePermission:output_type -> shared.DeletePermissionResponse
+	47, // 101: shared.IdentityService.ExportConfig:output_type -> shared.ExportConfigResponse
+	49, // 102: shared.IdentityService.ImportConfig:output_type -> shared.ImportConfigResponse
+	52, // 103: shared.IdentityService.GetSensitiveFields:output_type -> shared.SensitiveFieldsResponse
+	52, // 104: shared.IdentityService.UpdateSensitiveFields:output_type -> shared.SensitiveFieldsResponse
+	53, // 105: shared.IdentityService.CreateTenant:output_type -> shared.Tenant
+	53, // 106: shared.IdentityService.GetTenant:output_type -> shared.Tenant
+	57, // 107: shared.IdentityService.ListTenants:output_type -> shared.ListTenantsResponse
+	53, // 108: shared.IdentityService.UpdateTenant:output_type -> shared.Tenant
+	61, // 109: shared.IdentityService.ReassignRole:output_type -> shared.Operation
+	61, // 110: shared.IdentityService.DeprovisionTenant:output_type -> shared.Operation
+	61, // 111: shared.IdentityService.GetOperation:output_type -> shared.Operation
+	64, // 112: shared.IdentityService.ListOperations:output_type -> shared.ListOperationsResponse
+	81, // 113: shared.IdentityService.CancelOperation:output_type -> google.protobuf.Empty
+	67, // 114: shared.IdentityService.ListAuditEvents:output_type -> shared.ListAuditEventsResponse
+	68, // 115: shared.IdentityService.GetArtifact:output_type -> shared.Artifact
+	73, // 116: shared.IdentityService.GetSLOStatus:output_type -> shared.SLOStatusResponse
+	74, // 117: shared.IdentityService.GetVersion:output_type -> shared.VersionResponse
+	74, // [74:118] is the sub-list for method output_type
+	30, // [30:74] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
 }
 
 func init() { file_protobuf_identity_proto_init() }
@@ -1753,16 +5194,18 @@ func file_protobuf_identity_proto_init() {
 	if File_protobuf_identity_proto != nil {
 		return
 	}
-	file_protobuf_identity_proto_msgTypes[8].OneofWrappers = []any{}
-	file_protobuf_identity_proto_msgTypes[17].OneofWrappers = []any{}
-	file_protobuf_identity_proto_msgTypes[26].OneofWrappers = []any{}
+	file_protobuf_identity_proto_msgTypes[0].OneofWrappers = []any{}
+	file_protobuf_identity_proto_msgTypes[9].OneofWrappers = []any{}
+	file_protobuf_identity_proto_msgTypes[12].OneofWrappers = []any{}
+	file_protobuf_identity_proto_msgTypes[33].OneofWrappers = []any{}
+	file_protobuf_identity_proto_msgTypes[42].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protobuf_identity_proto_rawDesc), len(file_protobuf_identity_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   30,
+			NumMessages:   80,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,118 @@
+package proto
+
+import "github.com/gabehamasaki/momentum/shared/model"
+
+// Request validation, run by shared.ValidationUnaryInterceptor before the handlers.
+// Field names are the proto names so clients can map violations to their inputs.
+
+func (r *StoreUserRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("name", "required,max=120", r.GetName())
+	fields.Check("email", "required,email,max=254", r.GetEmail())
+	fields.Check("password", "required,password,max=72", r.GetPassword())
+	fields.Check("role_id", "required", r.GetRoleId())
+	return fields.Err()
+}
+
+func (r *UpdateUserRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("id", "required", r.GetId())
+	if r.Name != nil {
+		fields.Check("name", "required,max=120", r.GetName())
+	}
+	if r.Email != nil {
+		fields.Check("email", "required,email,max=254", r.GetEmail())
+	}
+	if r.RoleId != nil {
+		fields.Check("role_id", "required", r.GetRoleId())
+	}
+	return fields.Err()
+}
+
+func (r *RequestPasswordResetRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("email", "required,email", r.GetEmail())
+	return fields.Err()
+}
+
+func (r *ConfirmPasswordResetRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("token", "required", r.GetToken())
+	fields.Check("new_password", "required,password,max=72", r.GetNewPassword())
+	return fields.Err()
+}
+
+func (r *ChangePasswordRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("current_password", "required", r.GetCurrentPassword())
+	fields.Check("new_password", "required,password,max=72", r.GetNewPassword())
+	return fields.Err()
+}
+
+func (r *SendVerificationEmailRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("email", "required,email", r.GetEmail())
+	return fields.Err()
+}
+
+func (r *VerifyEmailRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("token", "required", r.GetToken())
+	return fields.Err()
+}
+
+func (r *BulkImportUsersRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("users", "required,max=1000", r.GetUsers())
+	return fields.Err()
+}
+
+// Validate checks one user of BulkImportUsersRequest; invalid users are reported
+// in the response instead of failing the whole import
+func (u *ImportUser) Validate() error {
+	var fields model.Fields
+	fields.Check("name", "required,max=120", u.GetName())
+	fields.Check("email", "required,email,max=254", u.GetEmail())
+	fields.Check("role_id", "required", u.GetRoleId())
+	return fields.Err()
+}
+
+func (r *CreateTenantRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("id", "required,max=64", r.GetId())
+	fields.Check("name", "required,max=120", r.GetName())
+	return fields.Err()
+}
+
+func (r *UpdateTenantRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("id", "required", r.GetId())
+	fields.Check("name", "required,max=120", r.GetName())
+	return fields.Err()
+}
+
+func (r *ScheduleDeactivationRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("id", "required", r.GetId())
+	fields.Check("deactivate_at", "required,rfc3339", r.GetDeactivateAt())
+	return fields.Err()
+}
+
+func (r *SearchUsersRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("query", "required,max=200", r.GetQuery())
+	return fields.Err()
+}
+
+func (r *RestoreUserRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("id", "required", r.GetId())
+	return fields.Err()
+}
+
+func (r *ListAuditEventsRequest) Validate() error {
+	var fields model.Fields
+	fields.Check("since", "rfc3339", r.GetSince())
+	fields.Check("until", "rfc3339", r.GetUntil())
+	return fields.Err()
+}
@@ -0,0 +1,116 @@
+package shared
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gabehamasaki/momentum/shared/model"
+	"github.com/gabehamasaki/momentum/shared/v1/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Validator is implemented by request messages that check their own fields
+type Validator interface {
+	Validate() error
+}
+
+// ValidationStatus converts a *model.ValidationError to an InvalidArgument status with
+// reason VALIDATION_FAILED and one BadRequest field violation per failed rule; other
+// errors are returned as is
+func ValidationStatus(err error) error {
+	var validationErr *model.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	st := status.New(codes.InvalidArgument, err.Error())
+	violations := make([]*errdetails.BadRequest_FieldViolation, len(validationErr.Fields))
+	for i, field := range validationErr.Fields {
+		violations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       field.Field,
+			Description: field.Error(),
+		}
+	}
+	detailed, detailsErr := st.WithDetails(
+		reasonInfo(proto.ErrorReason_VALIDATION_FAILED, nil),
+		&errdetails.BadRequest{FieldViolations: violations},
+	)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// RequestCheck validates a request against state its message cannot see, such as
+// referenced records, adding violations to fields. A returned error fails the call.
+type RequestCheck func(ctx context.Context, req any, fields *model.Fields) error
+
+// ValidationConfig configures request validation
+type ValidationConfig struct {
+	// Checks maps full gRPC method names to a check run after Validate. Its
+	// violations are reported together with those of Validate.
+	Checks map[string]RequestCheck
+}
+
+// validateRequest runs Validate on messages implementing Validator and the check
+// of the method, returning every violation in a single InvalidArgument status
+func (c *ValidationConfig) validateRequest(ctx context.Context, method string, req any) error {
+	var fields model.Fields
+	if validator, ok := req.(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			var validationErr *model.ValidationError
+			if !errors.As(err, &validationErr) {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			fields = append(fields, validationErr.Fields...)
+		}
+	}
+
+	if check, ok := c.Checks[method]; ok {
+		if err := check(ctx, req, &fields); err != nil {
+			return err
+		}
+	}
+	return ValidationStatus(fields.Err())
+}
+
+// ValidationUnaryInterceptor rejects invalid requests with InvalidArgument; config may be nil
+func ValidationUnaryInterceptor(config *ValidationConfig) grpc.UnaryServerInterceptor {
+	if config == nil {
+		config = &ValidationConfig{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := config.validateRequest(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// ValidationStreamInterceptor validates every message received on a stream; config may be nil
+func ValidationStreamInterceptor(config *ValidationConfig) grpc.StreamServerInterceptor {
+	if config == nil {
+		config = &ValidationConfig{}
+	}
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingServerStream{ServerStream: ss, config: config, method: info.FullMethod})
+	}
+}
+
+type validatingServerStream struct {
+	grpc.ServerStream
+	config *ValidationConfig
+	method string
+}
+
+func (s *validatingServerStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.config.validateRequest(s.Context(), s.method, m)
+}
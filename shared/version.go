@@ -0,0 +1,139 @@
+package shared
+
+import (
+	"context"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Build information, set at link time:
+//
+//	go build -ldflags "-X github.com/gabehamasaki/momentum/shared.Version=v1.2.0 -X github.com/gabehamasaki/momentum/shared.Commit=$(git rev-parse --short HEAD)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+const (
+	// ServerVersionMetadataKey is the response header carrying the server version
+	ServerVersionMetadataKey = "x-server-version"
+
+	// ClientVersionMetadataKey is the request header carrying the version of the calling client
+	ClientVersionMetadataKey = "x-client-version"
+)
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Service   string
+	Version   string
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// GetBuildInfo returns the build information of the service; the commit falls back
+// to the VCS revision recorded by the Go toolchain when not set at link time
+func GetBuildInfo(service string) BuildInfo {
+	info := BuildInfo{
+		Service:   service,
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+	if info.Commit == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				switch setting.Key {
+				case "vcs.revision":
+					info.Commit = setting.Value
+				case "vcs.time":
+					if info.BuildTime == "" {
+						info.BuildTime = setting.Value
+					}
+				}
+			}
+		}
+	}
+	return info
+}
+
+// CompareVersions compares two "v1.2.3" versions, ignoring pre-release and build
+// suffixes; ok is false when either is not a semantic version
+func CompareVersions(a, b string) (cmp int, ok bool) {
+	partsA, okA := versionParts(a)
+	partsB, okB := versionParts(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range partsA {
+		switch {
+		case partsA[i] < partsB[i]:
+			return -1, true
+		case partsA[i] > partsB[i]:
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+// versionParts parses the major, minor and patch numbers; missing minor and patch count as 0
+func versionParts(version string) ([3]int, bool) {
+	var parts [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	fields := strings.Split(version, ".")
+	if len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// CompatibleVersions reports whether two versions share the same major version.
+// Development builds and versions that are not semantic versions are always compatible.
+func CompatibleVersions(a, b string) bool {
+	majorA, okA := majorVersion(a)
+	majorB, okB := majorVersion(b)
+	return !okA || !okB || majorA == majorB
+}
+
+// majorVersion parses the major number of a "v1.2.3" or "1.2.3" version
+func majorVersion(version string) (int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	return n, err == nil
+}
+
+// VersionUnaryInterceptor sends the server version in the header of every response
+func VersionUnaryInterceptor(version string) grpc.UnaryServerInterceptor {
+	header := metadata.Pairs(ServerVersionMetadataKey, version)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		_ = grpc.SetHeader(ctx, header)
+		return handler(ctx, req)
+	}
+}
+
+// VersionStreamInterceptor sends the server version in the header of every stream
+func VersionStreamInterceptor(version string) grpc.StreamServerInterceptor {
+	header := metadata.Pairs(ServerVersionMetadataKey, version)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		_ = ss.SetHeader(header)
+		return handler(srv, ss)
+	}
+}